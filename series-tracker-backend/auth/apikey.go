@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lab6/repository"
+)
+
+const (
+	// APIKeyPrefix antecede a todas las claves de API para distinguirlas de los
+	// tokens de sesión y facilitar su detección si se filtran.
+	APIKeyPrefix = "stk_"
+	// APIKeyHeader es el header alternativo para enviar una clave de API.
+	APIKeyHeader = "X-API-Key"
+
+	// apiKeyDisplayLength es la cantidad de caracteres de la clave que se guardan
+	// en claro para que el usuario pueda reconocerla.
+	apiKeyDisplayLength = len(APIKeyPrefix) + 8
+	// touchInterval limita la frecuencia con la que se actualiza last_used_at.
+	touchInterval = time.Minute
+)
+
+// IsAPIKey indica si el token tiene el formato de una clave de API.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// NewAPIKey genera una clave de API aleatoria de 256 bits y devuelve la clave,
+// su prefijo visible y su hash (lo único secreto que se guarda).
+func NewAPIKey() (key, prefix, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return key, key[:apiKeyDisplayLength], HashToken(key), nil
+}
+
+// APIKeyScopes devuelve los permisos que concede una clave con el scope indicado:
+// 'read' solo permite lecturas y 'write' permite además modificar datos.
+func APIKeyScopes(scope string) []string {
+	if scope == ScopeWrite {
+		return []string{ScopeRead, ScopeWrite}
+	}
+	return []string{ScopeRead}
+}
+
+// APIKeyAuthenticator valida las claves de API enviadas en el header X-API-Key
+// o como "Authorization: Bearer stk_...".
+type APIKeyAuthenticator struct {
+	keys  repository.APIKeyRepository
+	users repository.UserRepository
+}
+
+// NewAPIKeyAuthenticator crea un autenticador de claves de API.
+func NewAPIKeyAuthenticator(keys repository.APIKeyRepository, users repository.UserRepository) *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{keys: keys, users: users}
+}
+
+// Authenticate implementa Authenticator.
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	key := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+	if key == "" {
+		if token, ok := BearerToken(r); ok && IsAPIKey(token) {
+			key = token
+		}
+	}
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	apiKey, err := a.keys.GetAPIKeyByHash(r.Context(), HashToken(key))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Principal{}, invalid("clave de API inválida o revocada")
+		}
+		return Principal{}, err
+	}
+
+	user, err := a.users.GetUser(r.Context(), apiKey.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Principal{}, invalid("el usuario de la clave de API ya no existe")
+		}
+		return Principal{}, err
+	}
+
+	// Registrar el uso como máximo una vez por minuto para no escribir en cada solicitud
+	now := time.Now().UTC()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= touchInterval {
+		if err := a.keys.TouchAPIKey(context.WithoutCancel(r.Context()), apiKey.ID, now); err != nil {
+			log.Printf("Error registrando el uso de la clave de API %d: %v", apiKey.ID, err)
+		}
+	}
+
+	return Principal{
+		UserID:   user.ID,
+		Username: user.Username,
+		Method:   MethodAPIKey,
+		Scopes:   APIKeyScopes(apiKey.Scope),
+	}, nil
+}
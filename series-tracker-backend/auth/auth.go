@@ -0,0 +1,103 @@
+// Package auth contiene las primitivas de autenticación de la API: el principal
+// (usuario autenticado) que viaja en el contexto de cada solicitud, los
+// autenticadores de credenciales (sesiones, JWT, claves de API), el hash de
+// contraseñas y la generación de tokens de sesión opacos.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Scopes (permisos) que puede tener un principal.
+const (
+	// ScopeRead permite consultar datos (métodos GET y HEAD).
+	ScopeRead = "read"
+	// ScopeWrite permite crear, modificar y eliminar datos.
+	ScopeWrite = "write"
+)
+
+// Métodos de autenticación con los que se puede obtener un principal.
+const (
+	MethodSession = "session"
+	MethodJWT     = "jwt"
+	MethodAPIKey  = "apikey"
+)
+
+// Principal identifica al usuario autenticado que realiza la solicitud.
+type Principal struct {
+	UserID   int
+	Username string
+	// Method indica cómo se autenticó la solicitud (MethodSession, MethodJWT, ...).
+	Method string
+	// Scopes son los permisos concedidos a las credenciales usadas.
+	Scopes []string
+}
+
+// HasScope indica si el principal tiene el permiso indicado.
+func (p Principal) HasScope(scope string) bool {
+	return slices.Contains(p.Scopes, scope)
+}
+
+// contextKey es un tipo privado para evitar colisiones con otras claves de contexto.
+type contextKey struct{}
+
+// WithPrincipal devuelve una copia de ctx que transporta el principal p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext devuelve el principal guardado en ctx, si existe.
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
+
+// HashPassword genera el hash bcrypt de una contraseña en texto plano.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword indica si password corresponde al hash bcrypt indicado.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyPasswordHash es un hash bcrypt con el mismo costo que HashPassword que
+// no corresponde a ninguna contraseña usada.
+const dummyPasswordHash = "$2a$10$FwfwG9UaD2RMt1S5KPMxo.Qbe4IJOvLPtLAxCo1CZZFKnIyyWogrG"
+
+// RejectPassword compara password con un hash ficticio y descarta el
+// resultado. Se usa cuando el usuario no existe para que la respuesta tarde lo
+// mismo que con una contraseña incorrecta y no revele qué usuarios existen.
+func RejectPassword(password string) {
+	CheckPassword(dummyPasswordHash, password)
+}
+
+// NewToken genera un token aleatorio de 256 bits codificado en base64url y
+// devuelve también su hash, que es lo único que se guarda en la base de datos.
+func NewToken() (token, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken devuelve el hash SHA-256 (en hexadecimal) de un token.
+// Los tokens tienen suficiente entropía, por lo que no necesitan un hash lento.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"lab6/repository"
+)
+
+// ErrNoCredentials indica que la solicitud no trae credenciales del tipo que
+// maneja un Authenticator, por lo que se debe probar con el siguiente.
+var ErrNoCredentials = errors.New("no se enviaron credenciales")
+
+// CredentialsError indica que las credenciales enviadas no son válidas
+// (token expirado, firma incorrecta, usuario inexistente, etc.).
+type CredentialsError struct {
+	// Reason describe el motivo del rechazo; se incluye en la respuesta 401.
+	Reason string
+}
+
+// Error implementa error.
+func (e *CredentialsError) Error() string {
+	return "credenciales inválidas: " + e.Reason
+}
+
+// invalid crea un *CredentialsError con el motivo indicado.
+func invalid(reason string) error {
+	return &CredentialsError{Reason: reason}
+}
+
+// Authenticator obtiene el principal a partir de las credenciales de una solicitud.
+// Debe devolver ErrNoCredentials si la solicitud no trae credenciales de su tipo,
+// un *CredentialsError si son inválidas, o cualquier otro error ante fallas internas.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// BearerToken extrae el token del header "Authorization: Bearer <token>".
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
+
+// looksLikeJWT indica si el token tiene la forma de un JWT compacto (tres partes).
+func looksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// SessionAuthenticator valida los tokens de sesión opacos emitidos en el inicio de sesión.
+type SessionAuthenticator struct {
+	sessions repository.SessionRepository
+	users    repository.UserRepository
+}
+
+// NewSessionAuthenticator crea un autenticador de tokens de sesión.
+func NewSessionAuthenticator(sessions repository.SessionRepository, users repository.UserRepository) *SessionAuthenticator {
+	return &SessionAuthenticator{sessions: sessions, users: users}
+}
+
+// Authenticate implementa Authenticator. Las sesiones tienen todos los permisos.
+func (a *SessionAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	token, ok := BearerToken(r)
+	if !ok || looksLikeJWT(token) || IsAPIKey(token) {
+		return Principal{}, ErrNoCredentials
+	}
+
+	session, err := a.sessions.GetSessionByTokenHash(r.Context(), HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Principal{}, invalid("token inválido o expirado")
+		}
+		return Principal{}, err
+	}
+
+	user, err := a.users.GetUser(r.Context(), session.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return Principal{}, invalid("el usuario de la sesión ya no existe")
+		}
+		return Principal{}, err
+	}
+
+	return Principal{
+		UserID:   user.ID,
+		Username: user.Username,
+		Method:   MethodSession,
+		Scopes:   []string{ScopeRead, ScopeWrite},
+	}, nil
+}
@@ -0,0 +1,322 @@
+package auth
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// JWTConfig configura la validación de tokens JWT. Debe indicarse al menos
+// una fuente de claves: un secreto HMAC o un archivo JWKS local.
+type JWTConfig struct {
+	// Secret es la clave compartida para tokens firmados con HS256/HS384/HS512.
+	Secret []byte
+	// JWKSFile es la ruta de un archivo JWKS (RFC 7517) con claves públicas
+	// RSA, EC u Ed25519 (o claves simétricas "oct").
+	JWKSFile string
+	// Issuer y Audience, si no están vacíos, deben coincidir con los claims iss y aud.
+	Issuer   string
+	Audience string
+}
+
+// Enabled indica si hay alguna fuente de claves configurada.
+func (c JWTConfig) Enabled() bool {
+	return len(c.Secret) > 0 || c.JWKSFile != ""
+}
+
+// jwtClaims son los claims reconocidos en los tokens.
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	// PreferredUsername se usa para identificar al usuario cuando "sub" no es un ID numérico.
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	// Scope es la lista de permisos separada por espacios (RFC 8693).
+	Scope string `json:"scope,omitempty"`
+}
+
+// JWTAuthenticator valida tokens JWT enviados en el header "Authorization: Bearer".
+// El claim "sub" debe ser el ID de un usuario existente o, si no es numérico,
+// el claim "preferred_username" debe coincidir con su nombre de usuario.
+type JWTAuthenticator struct {
+	parser  *jwt.Parser
+	secret  []byte
+	keys    map[string]any        // claves del JWKS indexadas por "kid"
+	anyKeys []jwt.VerificationKey // claves del JWKS en orden, para tokens sin "kid"
+	users   repository.UserRepository
+}
+
+// NewJWTAuthenticator crea un autenticador JWT a partir de cfg, cargando el
+// archivo JWKS si se indicó.
+func NewJWTAuthenticator(cfg JWTConfig, users repository.UserRepository) (*JWTAuthenticator, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("se requiere un secreto HMAC o un archivo JWKS")
+	}
+
+	a := &JWTAuthenticator{secret: cfg.Secret, keys: map[string]any{}, users: users}
+	var methods []string
+	if len(cfg.Secret) > 0 {
+		methods = append(methods, "HS256", "HS384", "HS512")
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := loadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			if k.kid != "" {
+				a.keys[k.kid] = k.key
+			}
+			a.anyKeys = append(a.anyKeys, k.key)
+			methods = append(methods, k.methods...)
+		}
+	}
+	slices.Sort(methods)
+
+	// Restringir los algoritmos a los de las claves configuradas evita ataques de
+	// confusión de algoritmo (por ejemplo, "none" o HS256 con una clave pública).
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(slices.Compact(methods)),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+	}
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+	a.parser = jwt.NewParser(options...)
+	return a, nil
+}
+
+// keyFunc elige la clave de verificación según el algoritmo y el "kid" del token.
+func (a *JWTAuthenticator) keyFunc(token *jwt.Token) (any, error) {
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		if key, ok := a.keys[kid]; ok {
+			return key, nil
+		}
+		if _, hmac := token.Method.(*jwt.SigningMethodHMAC); !hmac || len(a.secret) == 0 {
+			return nil, fmt.Errorf("clave desconocida (kid %q)", kid)
+		}
+	}
+	if _, hmac := token.Method.(*jwt.SigningMethodHMAC); hmac && len(a.secret) > 0 {
+		return a.secret, nil
+	}
+	// Sin "kid": se prueban todas las claves del JWKS
+	return jwt.VerificationKeySet{Keys: a.anyKeys}, nil
+}
+
+// Authenticate implementa Authenticator.
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (Principal, error) {
+	raw, ok := BearerToken(r)
+	if !ok || !looksLikeJWT(raw) {
+		return Principal{}, ErrNoCredentials
+	}
+
+	var claims jwtClaims
+	if _, err := a.parser.ParseWithClaims(raw, &claims, a.keyFunc); err != nil {
+		return Principal{}, invalid("token JWT inválido (" + err.Error() + ")")
+	}
+
+	user, err := a.resolveUser(r, claims)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	// Un token sin claim "scope" actúa en nombre del usuario con todos los permisos
+	scopes := strings.Fields(claims.Scope)
+	if len(scopes) == 0 {
+		scopes = []string{ScopeRead, ScopeWrite}
+	}
+
+	return Principal{UserID: user.ID, Username: user.Username, Method: MethodJWT, Scopes: scopes}, nil
+}
+
+// resolveUser busca el usuario local al que se refiere el token.
+func (a *JWTAuthenticator) resolveUser(r *http.Request, claims jwtClaims) (models.User, error) {
+	var (
+		user models.User
+		err  error
+	)
+	if id, convErr := strconv.Atoi(claims.Subject); convErr == nil {
+		user, err = a.users.GetUser(r.Context(), id)
+	} else if claims.PreferredUsername != "" {
+		user, err = a.users.GetUserByUsername(r.Context(), claims.PreferredUsername)
+	} else {
+		return models.User{}, invalid("el token no identifica a un usuario (claims sub o preferred_username)")
+	}
+
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.User{}, invalid("el usuario del token no existe")
+		}
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// jsonWebKey es una clave de un documento JWKS (RFC 7517/7518/8037).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// verificationKey es una clave ya decodificada junto con los algoritmos que admite.
+type verificationKey struct {
+	kid     string
+	key     any
+	methods []string
+}
+
+// loadJWKS lee y decodifica las claves de verificación de un archivo JWKS.
+// Se ignoran las claves destinadas a cifrado ("use": "enc").
+func loadJWKS(path string) ([]verificationKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("leyendo el archivo JWKS: %w", err)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("decodificando el archivo JWKS: %w", err)
+	}
+
+	var keys []verificationKey
+	for i, k := range set.Keys {
+		if k.Use == "enc" {
+			continue
+		}
+		key, methods, err := k.decode()
+		if err != nil {
+			return nil, fmt.Errorf("clave %d (kid %q) del JWKS: %w", i, k.Kid, err)
+		}
+		if k.Alg != "" {
+			if !slices.Contains(methods, k.Alg) {
+				return nil, fmt.Errorf("clave %d (kid %q) del JWKS: algoritmo %q incompatible con kty %q", i, k.Kid, k.Alg, k.Kty)
+			}
+			methods = []string{k.Alg}
+		}
+		keys = append(keys, verificationKey{kid: k.Kid, key: key, methods: methods})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("el archivo JWKS no contiene claves de firma")
+	}
+	return keys, nil
+}
+
+// decodeB64 decodifica un campo base64url sin relleno.
+func decodeB64(field, value string) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("falta el campo %q", field)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("campo %q inválido: %w", field, err)
+	}
+	return b, nil
+}
+
+// decode convierte la clave JWK en una clave de verificación de crypto/*.
+func (k jsonWebKey) decode() (any, []string, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeB64("n", k.N)
+		if err != nil {
+			return nil, nil, err
+		}
+		e, err := decodeB64("e", k.E)
+		if err != nil {
+			return nil, nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() < 3 {
+			return nil, nil, errors.New("exponente RSA inválido")
+		}
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}
+		return key, []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}, nil
+
+	case "EC":
+		curves := map[string]struct {
+			curve  elliptic.Curve
+			ecdh   ecdh.Curve
+			method string
+		}{
+			"P-256": {elliptic.P256(), ecdh.P256(), "ES256"},
+			"P-384": {elliptic.P384(), ecdh.P384(), "ES384"},
+			"P-521": {elliptic.P521(), ecdh.P521(), "ES512"},
+		}
+		c, ok := curves[k.Crv]
+		if !ok {
+			return nil, nil, fmt.Errorf("curva no soportada: %q", k.Crv)
+		}
+		x, err := decodeB64("x", k.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		y, err := decodeB64("y", k.Y)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Validar que el punto pertenezca a la curva usando la codificación sin comprimir
+		size := (c.curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, nil, errors.New("coordenadas EC demasiado largas")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		if _, err := c.ecdh.NewPublicKey(point); err != nil {
+			return nil, nil, fmt.Errorf("punto EC inválido: %w", err)
+		}
+		key := &ecdsa.PublicKey{Curve: c.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		return key, []string{c.method}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil, fmt.Errorf("curva no soportada: %q", k.Crv)
+		}
+		x, err := decodeB64("x", k.X)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, nil, errors.New("clave Ed25519 con longitud inválida")
+		}
+		return ed25519.PublicKey(x), []string{"EdDSA"}, nil
+
+	case "oct":
+		secret, err := decodeB64("k", k.K)
+		if err != nil {
+			return nil, nil, err
+		}
+		return secret, []string{"HS256", "HS384", "HS512"}, nil
+	}
+	return nil, nil, fmt.Errorf("tipo de clave no soportado: %q", k.Kty)
+}
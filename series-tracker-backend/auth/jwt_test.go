@@ -0,0 +1,286 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+const (
+	testIssuer   = "https://idp.example.com"
+	testAudience = "series-tracker"
+)
+
+// testKeys son las claves de firma de los tokens de prueba.
+type testKeys struct {
+	rsa, otherRSA *rsa.PrivateKey
+	ec            *ecdsa.PrivateKey
+	jwksFile      string
+}
+
+// newTestKeys genera las claves y escribe un JWKS con la RSA ("rsa-1") y la EC
+// ("ec-1"); otherRSA no está en el JWKS.
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRSA, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwks := map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+	}}
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return testKeys{rsa: rsaKey, otherRSA: otherRSA, ec: ecKey, jwksFile: path}
+}
+
+// newTestUsers devuelve un repositorio con el usuario "ana" (ID 1).
+func newTestUsers(t *testing.T) repository.UserRepository {
+	t.Helper()
+	users := repository.NewMemoryUserRepository()
+	if err := users.CreateUser(context.Background(), &models.User{Username: "ana", PasswordHash: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	return users
+}
+
+// validClaims devuelve claims vigentes para el usuario 1, con el emisor y la
+// audiencia esperados.
+func validClaims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub": "1",
+		"iss": testIssuer,
+		"aud": testAudience,
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+// with devuelve una copia de validClaims con los cambios indicados (un valor
+// nil elimina el claim).
+func with(changes jwt.MapClaims) jwt.MapClaims {
+	claims := validClaims()
+	for k, v := range changes {
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+	}
+	return claims
+}
+
+// sign firma claims con method y key, y agrega el header "kid" si no está vacío.
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("firmando el token: %v", err)
+	}
+	return raw
+}
+
+// authenticate valida raw como token Bearer.
+func authenticate(a *JWTAuthenticator, raw string) (Principal, error) {
+	r := httptest.NewRequest("GET", "/api/series", nil)
+	r.Header.Set("Authorization", "Bearer "+raw)
+	return a.Authenticate(r)
+}
+
+func TestJWTAuthenticatorJWKS(t *testing.T) {
+	keys := newTestKeys(t)
+	a, err := NewJWTAuthenticator(JWTConfig{JWKSFile: keys.jwksFile, Issuer: testIssuer, Audience: testAudience}, newTestUsers(t))
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator: %v", err)
+	}
+	publicPEM, err := x509.MarshalPKIXPublicKey(&keys.rsa.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicPEM})
+	hour := time.Hour
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"RS256 con kid", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", validClaims()), true},
+		{"PS256 con kid", sign(t, jwt.SigningMethodPS256, keys.rsa, "rsa-1", validClaims()), true},
+		{"ES256 con kid", sign(t, jwt.SigningMethodES256, keys.ec, "ec-1", validClaims()), true},
+		{"sin kid prueba todas las claves", sign(t, jwt.SigningMethodES256, keys.ec, "", validClaims()), true},
+		{"kid desconocido", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-2", validClaims()), false},
+		{"kid de otra clave", sign(t, jwt.SigningMethodRS256, keys.rsa, "ec-1", validClaims()), false},
+		{"firmado con otra clave", sign(t, jwt.SigningMethodRS256, keys.otherRSA, "rsa-1", validClaims()), false},
+		{"alg none", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "rsa-1", validClaims()), false},
+		{"HS256 con la clave pública RSA", sign(t, jwt.SigningMethodHS256, publicPEM, "rsa-1", validClaims()), false},
+		{"HS256 sin secreto configurado", sign(t, jwt.SigningMethodHS256, []byte("secreto"), "", validClaims()), false},
+		{"ES384 fuera de la familia de la clave", sign(t, jwt.SigningMethodES384, mustECKey(t, elliptic.P384()), "ec-1", validClaims()), false},
+		{"vencido", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", with(jwt.MapClaims{"exp": time.Now().Add(-hour).Unix()})), false},
+		{"sin exp", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", with(jwt.MapClaims{"exp": nil})), false},
+		{"todavía no válido", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", with(jwt.MapClaims{"nbf": time.Now().Add(hour).Unix()})), false},
+		{"otro emisor", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", with(jwt.MapClaims{"iss": "https://otro.example.com"})), false},
+		{"otra audiencia", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", with(jwt.MapClaims{"aud": "otra-api"})), false},
+		{"sin audiencia", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", with(jwt.MapClaims{"aud": nil})), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticate(a, tt.token)
+			if tt.ok {
+				if err != nil || principal.UserID != 1 || principal.Method != MethodJWT {
+					t.Errorf("Authenticate = %+v, %v; se esperaba el usuario 1", principal, err)
+				}
+				return
+			}
+			var credErr *CredentialsError
+			if !errors.As(err, &credErr) {
+				t.Errorf("Authenticate = %+v, %v; se esperaba un *CredentialsError", principal, err)
+			}
+		})
+	}
+}
+
+// mustECKey genera una clave EC de la curva indicada.
+func mustECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestJWTAuthenticatorSecret(t *testing.T) {
+	keys := newTestKeys(t)
+	secret := []byte("un-secreto-compartido-de-prueba!")
+	a, err := NewJWTAuthenticator(JWTConfig{Secret: secret}, newTestUsers(t))
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"HS256", sign(t, jwt.SigningMethodHS256, secret, "", validClaims()), true},
+		{"HS512 con kid desconocido", sign(t, jwt.SigningMethodHS512, secret, "otra", validClaims()), true},
+		{"otro secreto", sign(t, jwt.SigningMethodHS256, []byte("otro-secreto"), "", validClaims()), false},
+		{"RS256 sin JWKS configurado", sign(t, jwt.SigningMethodRS256, keys.rsa, "rsa-1", validClaims()), false},
+		{"alg none", sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims()), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticate(a, tt.token)
+			if tt.ok != (err == nil) {
+				t.Errorf("Authenticate = %+v, %v; se esperaba ok = %t", principal, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestJWTAuthenticatorUser(t *testing.T) {
+	secret := []byte("un-secreto-compartido-de-prueba!")
+	a, err := NewJWTAuthenticator(JWTConfig{Secret: secret}, newTestUsers(t))
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   int // 0 si el token debe rechazarse
+		scopes []string
+	}{
+		{"sub numérico", validClaims(), 1, []string{ScopeRead, ScopeWrite}},
+		{"sub externo con preferred_username", with(jwt.MapClaims{"sub": "idp|abc", "preferred_username": "ana"}), 1, []string{ScopeRead, ScopeWrite}},
+		{"con scope", with(jwt.MapClaims{"scope": ScopeRead}), 1, []string{ScopeRead}},
+		{"sub externo sin preferred_username", with(jwt.MapClaims{"sub": "idp|abc"}), 0, nil},
+		{"preferred_username inexistente", with(jwt.MapClaims{"sub": "idp|abc", "preferred_username": "beto"}), 0, nil},
+		{"sub numérico inexistente", with(jwt.MapClaims{"sub": "99"}), 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := authenticate(a, sign(t, jwt.SigningMethodHS256, secret, "", tt.claims))
+			if tt.want == 0 {
+				if err == nil {
+					t.Errorf("Authenticate = %+v, se esperaba un error", principal)
+				}
+				return
+			}
+			if err != nil || principal.UserID != tt.want || principal.Username != "ana" {
+				t.Fatalf("Authenticate = %+v, %v; se esperaba el usuario %d", principal, err, tt.want)
+			}
+			if len(principal.Scopes) != len(tt.scopes) || principal.Scopes[0] != tt.scopes[0] {
+				t.Errorf("scopes = %v, se esperaba %v", principal.Scopes, tt.scopes)
+			}
+		})
+	}
+}
+
+func TestNewJWTAuthenticatorRejectsInvalidJWKS(t *testing.T) {
+	tests := []struct {
+		name string
+		jwks string
+	}{
+		{"sin claves de firma", `{"keys": [{"kty": "oct", "use": "enc", "k": "c2VjcmV0bw"}]}`},
+		{"alg incompatible", `{"keys": [{"kty": "oct", "alg": "RS256", "k": "c2VjcmV0bw"}]}`},
+		{"tipo no soportado", `{"keys": [{"kty": "XYZ"}]}`},
+		{"punto EC fuera de la curva", `{"keys": [{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`},
+		{"JSON inválido", `{"keys": [`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "jwks.json")
+			if err := os.WriteFile(path, []byte(tt.jwks), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewJWTAuthenticator(JWTConfig{JWKSFile: path}, newTestUsers(t)); err == nil {
+				t.Error("NewJWTAuthenticator no devolvió error")
+			}
+		})
+	}
+	if _, err := NewJWTAuthenticator(JWTConfig{}, newTestUsers(t)); err == nil {
+		t.Error("NewJWTAuthenticator sin claves no devolvió error")
+	}
+}
@@ -0,0 +1,207 @@
+// Package covers valida las imágenes de portada subidas por los usuarios y
+// genera sus miniaturas. Acepta JPEG, PNG, GIF (se usa el primer cuadro) y
+// WebP; las miniaturas se codifican siempre como JPEG.
+package covers
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const (
+	// MaxBytes es el tamaño máximo del archivo subido (5 MiB).
+	MaxBytes = 5 << 20
+	// MaxPixels limita el área de la imagen para evitar que una imagen pequeña
+	// en bytes pero enorme al decodificarse agote la memoria.
+	MaxPixels = 40_000_000
+	// MaxSide es el máximo de píxeles de ancho o alto.
+	MaxSide = 10_000
+	// thumbnailQuality es la calidad JPEG de las miniaturas.
+	thumbnailQuality = 85
+)
+
+// Tamaños de las imágenes de una portada. SizeOriginal es la imagen subida.
+const (
+	SizeOriginal = "original"
+	SizeMedium   = "medium"
+	SizeSmall    = "small"
+)
+
+// ThumbnailWidths es el ancho máximo (en píxeles) de cada miniatura. Las
+// imágenes más angostas no se amplían.
+var ThumbnailWidths = map[string]int{
+	SizeMedium: 480,
+	SizeSmall:  160,
+}
+
+// formats relaciona los tipos MIME aceptados con su extensión y su decodificador.
+var formats = map[string]struct {
+	ext          string
+	decode       func(*bytes.Reader) (image.Image, error)
+	decodeConfig func(*bytes.Reader) (image.Config, error)
+}{
+	"image/jpeg": {".jpg", func(r *bytes.Reader) (image.Image, error) { return jpeg.Decode(r) }, func(r *bytes.Reader) (image.Config, error) { return jpeg.DecodeConfig(r) }},
+	"image/png":  {".png", func(r *bytes.Reader) (image.Image, error) { return png.Decode(r) }, func(r *bytes.Reader) (image.Config, error) { return png.DecodeConfig(r) }},
+	"image/gif":  {".gif", func(r *bytes.Reader) (image.Image, error) { return gif.Decode(r) }, func(r *bytes.Reader) (image.Config, error) { return gif.DecodeConfig(r) }},
+	"image/webp": {".webp", func(r *bytes.Reader) (image.Image, error) { return webp.Decode(r) }, func(r *bytes.Reader) (image.Config, error) { return webp.DecodeConfig(r) }},
+}
+
+// PathPrefix es el prefijo de las rutas públicas de las imágenes
+// (GET /api/covers/{token}/{archivo}).
+const PathPrefix = "/api/covers/"
+
+// tokenPattern y filePattern validan los segmentos {token} y {archivo} de la
+// ruta de una imagen: el token es hexadecimal de 32 caracteres (ver NewToken) y
+// el archivo es el tamaño seguido de la extensión.
+var (
+	tokenPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	filePattern  = regexp.MustCompile(`^(original\.(jpg|png|gif|webp)|medium\.jpg|small\.jpg)$`)
+)
+
+// ErrUnsupportedType se devuelve cuando el contenido no es una imagen de un tipo aceptado.
+var ErrUnsupportedType = errors.New("tipo de imagen no soportado (use JPEG, PNG, GIF o WebP)")
+
+// Image es una imagen codificada lista para guardarse.
+type Image struct {
+	// Size es el nombre del tamaño (SizeOriginal, SizeMedium o SizeSmall).
+	Size string
+	// Data es el contenido codificado.
+	Data []byte
+	// ContentType es el tipo MIME y Ext la extensión de archivo correspondiente (con punto).
+	ContentType string
+	Ext         string
+	// Width y Height son las dimensiones en píxeles.
+	Width, Height int
+}
+
+// File devuelve el nombre de archivo de la imagen, por ejemplo "small.jpg".
+func (img Image) File() string {
+	return img.Size + img.Ext
+}
+
+// NewToken genera un token aleatorio (128 bits en hexadecimal) que identifica
+// las imágenes de una portada. Cada subida usa un token nuevo, de modo que el
+// contenido de una ruta nunca cambia y puede cachearse indefinidamente.
+func NewToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Key devuelve la clave en el almacenamiento de blobs del archivo file de la portada token.
+func Key(token, file string) string {
+	return "covers/" + token + "/" + file
+}
+
+// Keys devuelve todas las claves que puede ocupar la portada token (la original
+// con cualquiera de las extensiones aceptadas y las miniaturas), para eliminarla.
+func Keys(token string) []string {
+	keys := []string{Key(token, SizeMedium+".jpg"), Key(token, SizeSmall+".jpg")}
+	for _, format := range formats {
+		keys = append(keys, Key(token, SizeOriginal+format.ext))
+	}
+	return keys
+}
+
+// URL devuelve la ruta pública del archivo file de la portada token.
+func URL(token, file string) string {
+	return PathPrefix + token + "/" + file
+}
+
+// ValidPath indica si token y file forman la ruta de una imagen de portada.
+func ValidPath(token, file string) bool {
+	return tokenPattern.MatchString(token) && filePattern.MatchString(file)
+}
+
+// ParseURL extrae el token y el archivo de una ruta devuelta por URL. Devuelve
+// ok = false si raw no es la ruta de una imagen de portada.
+func ParseURL(raw string) (token, file string, ok bool) {
+	rest, found := strings.CutPrefix(raw, PathPrefix)
+	if !found {
+		return "", "", false
+	}
+	token, file, _ = strings.Cut(rest, "/")
+	return token, file, ValidPath(token, file)
+}
+
+// Process valida que data sea una imagen aceptada (por su contenido, no por
+// el nombre del archivo) y devuelve la imagen original seguida de sus miniaturas.
+// Los errores de validación envuelven ErrUnsupportedType o describen el problema.
+func Process(data []byte) ([]Image, error) {
+	contentType := http.DetectContentType(data)
+	format, ok := formats[contentType]
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	config, err := format.decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+	if config.Width < 1 || config.Height < 1 || config.Width > MaxSide || config.Height > MaxSide ||
+		config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("la imagen mide %dx%d píxeles; se admiten hasta %d por lado y %d en total",
+			config.Width, config.Height, MaxSide, MaxPixels)
+	}
+
+	src, err := format.decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedType, err)
+	}
+
+	images := []Image{{
+		Size:        SizeOriginal,
+		Data:        data,
+		ContentType: contentType,
+		Ext:         format.ext,
+		Width:       config.Width,
+		Height:      config.Height,
+	}}
+	for _, size := range []string{SizeMedium, SizeSmall} {
+		thumb, err := thumbnail(src, ThumbnailWidths[size])
+		if err != nil {
+			return nil, err
+		}
+		thumb.Size = size
+		images = append(images, thumb)
+	}
+	return images, nil
+}
+
+// thumbnail reduce src (sin ampliarla) a un ancho máximo de width píxeles
+// conservando la proporción y la codifica como JPEG. Las zonas transparentes
+// se rellenan de blanco, ya que JPEG no admite transparencia.
+func thumbnail(src image.Image, width int) (Image, error) {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > width {
+		h = max(1, h*width/w)
+		w = width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return Image{}, err
+	}
+	return Image{Data: buf.Bytes(), ContentType: "image/jpeg", Ext: ".jpg", Width: w, Height: h}, nil
+}
@@ -24,9 +24,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/series": {
-            "get": {
-                "description": "Obtiene una lista completa de todas las series almacenadas en la base de datos.",
+        "/auth/login": {
+            "post": {
+                "description": "Verifica las credenciales y devuelve un token de sesión para usar en el header Authorization.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,65 +34,75 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Series"
+                    "Auth"
+                ],
+                "summary": "Iniciar sesión",
+                "parameters": [
+                    {
+                        "description": "Nombre de usuario y contraseña",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Credentials"
+                        }
+                    }
                 ],
-                "summary": "Listar todas las series",
                 "responses": {
                     "200": {
-                        "description": "Lista de series recuperada exitosamente",
+                        "description": "Sesión iniciada",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Series"
-                            }
+                            "$ref": "#/definitions/models.AuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Cuerpo de la solicitud inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Credenciales incorrectas",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al buscar series",
+                        "description": "Error interno del servidor al iniciar sesión",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/logout": {
             "post": {
-                "description": "Añade una nueva serie a la base de datos utilizando los datos proporcionados en el cuerpo de la solicitud. El ID es auto-generado por la base de datos.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Invalida el token de sesión usado en la solicitud.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Series"
-                ],
-                "summary": "Crear una nueva serie",
-                "parameters": [
-                    {
-                        "description": "Datos de la nueva serie a crear (el campo ID será ignorado)",
-                        "name": "series",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Series"
-                        }
-                    }
+                    "Auth"
                 ],
+                "summary": "Cerrar sesión",
                 "responses": {
-                    "201": {
-                        "description": "Serie creada exitosamente (devuelve el objeto completo con el nuevo ID)",
-                        "schema": {
-                            "$ref": "#/definitions/models.Series"
-                        }
+                    "204": {
+                        "description": "Sin contenido (sesión cerrada)"
                     },
-                    "400": {
-                        "description": "Entrada inválida (ej. JSON mal formado, falta título)",
+                    "401": {
+                        "description": "No autenticado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al guardar la serie",
+                        "description": "Error interno del servidor al cerrar la sesión",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -100,59 +110,49 @@ const docTemplate = `{
                 }
             }
         },
-        "/series/{id}": {
+        "/auth/me": {
             "get": {
-                "description": "Obtiene los detalles de una serie específica usando su ID numérico proporcionado en la URL.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Devuelve los datos del usuario autenticado.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Series"
-                ],
-                "summary": "Obtener una serie por ID",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "example": 1,
-                        "description": "ID de la Serie a buscar",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Auth"
                 ],
+                "summary": "Usuario actual",
                 "responses": {
                     "200": {
-                        "description": "Detalles de la serie encontrados",
-                        "schema": {
-                            "$ref": "#/definitions/models.Series"
-                        }
-                    },
-                    "400": {
-                        "description": "ID proporcionado inválido (no es un número)",
+                        "description": "Usuario autenticado",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.User"
                         }
                     },
-                    "404": {
-                        "description": "Serie no encontrada con el ID proporcionado",
+                    "401": {
+                        "description": "No autenticado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al buscar la serie",
+                        "description": "Error interno del servidor al buscar el usuario",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Actualiza todos los campos de una serie existente identificada por su ID, utilizando los datos proporcionados en el cuerpo de la solicitud.",
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Crea una cuenta nueva e inicia sesión automáticamente, devolviendo un token de sesión.",
                 "consumes": [
                     "application/json"
                 ],
@@ -160,95 +160,120 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Series"
+                    "Auth"
                 ],
-                "summary": "Actualizar una serie existente",
+                "summary": "Registrar un usuario",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "ID de la Serie a actualizar",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Nuevos datos completos para la serie (se usará el ID de la URL, no el del cuerpo si existe)",
-                        "name": "series",
+                        "description": "Nombre de usuario y contraseña",
+                        "name": "credentials",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Series"
+                            "$ref": "#/definitions/models.Credentials"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Serie actualizada exitosamente",
+                    "201": {
+                        "description": "Usuario creado y sesión iniciada",
                         "schema": {
-                            "$ref": "#/definitions/models.Series"
+                            "$ref": "#/definitions/models.AuthResponse"
                         }
                     },
                     "400": {
-                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido en URL)",
+                        "description": "Entrada inválida (JSON mal formado, usuario o contraseña no válidos)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Serie no encontrada con el ID proporcionado",
+                    "409": {
+                        "description": "El nombre de usuario ya está registrado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al actualizar la serie",
+                        "description": "Error interno del servidor al registrar el usuario",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Elimina permanentemente una serie de la base de datos utilizando su ID.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/covers/{token}/{file}": {
+            "get": {
+                "description": "Devuelve la imagen original o una miniatura de una portada subida. Es pública (no requiere autenticación) porque el token es aleatorio e imposible de adivinar, y se puede cachear indefinidamente: cada subida usa un token nuevo.",
                 "produces": [
-                    "application/json"
+                    "image/jpeg",
+                    "image/png",
+                    "image/gif",
+                    "image/webp"
                 ],
                 "tags": [
-                    "Series"
+                    "Covers"
                 ],
-                "summary": "Eliminar una serie",
+                "summary": "Obtener una imagen de portada",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "ID de la Serie a eliminar",
-                        "name": "id",
+                        "type": "string",
+                        "example": "3f2a9c0d8e7b6a5f4e3d2c1b0a9f8e7d",
+                        "description": "Token de la portada (ver 'coverUrl')",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "original.jpg",
+                            "original.png",
+                            "original.gif",
+                            "original.webp",
+                            "medium.jpg",
+                            "small.jpg"
+                        ],
+                        "type": "string",
+                        "description": "Archivo",
+                        "name": "file",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la copia en caché; si coincide se responde 304",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Sin contenido (eliminado exitosamente)"
-                    },
-                    "400": {
-                        "description": "ID proporcionado inválido (no es un número)",
+                    "200": {
+                        "description": "Imagen",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "file"
+                        },
+                        "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "public, max-age=31536000, immutable"
+                            },
+                            "ETag": {
+                                "type": "string",
+                                "description": "Identificador de la imagen"
+                            }
                         }
                     },
+                    "304": {
+                        "description": "La copia en caché sigue vigente"
+                    },
                     "404": {
-                        "description": "Serie no encontrada para eliminar",
+                        "description": "Imagen no encontrada",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al eliminar la serie",
+                        "description": "Error interno del servidor al leer la imagen",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -256,50 +281,73 @@ const docTemplate = `{
                 }
             }
         },
-        "/series/{id}/downvote": {
-            "patch": {
-                "description": "Decrementa en 1 el campo 'ranking' de la serie identificada por su ID.",
-                "consumes": [
-                    "application/json"
+        "/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
                 ],
+                "description": "Descarga todas las series del usuario como archivo adjunto, escrito a medida que se leen (sin cargar la lista completa en memoria).\n- **json** (por defecto): documento con cada serie y sus etiquetas, listas, reseña e historial completo. Es el formato recomendado como respaldo.\n- **csv**: una fila por serie; temporadas, etiquetas y listas separadas con ';'; del historial solo la cantidad de eventos y la fecha del último.\n- **malxml**: XML con el formato de la exportación de MyAnimeList (estado, progreso, puntuación redondeada, notas, etiquetas y fechas de inicio y fin deducidas del historial), que aceptan MyAnimeList, AniList y POST /api/import.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "text/xml"
                 ],
                 "tags": [
-                    "Series Actions"
+                    "Import/Export"
                 ],
-                "summary": "Votar negativamente (Downvote) una serie",
+                "summary": "Exportar la lista completa",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "ID de la Serie a votar negativamente",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "json",
+                            "csv",
+                            "malxml"
+                        ],
+                        "type": "string",
+                        "default": "json",
+                        "description": "Formato del archivo",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Ranking decrementado, devuelve la serie actualizada",
+                        "description": "Exportación (con format=json)",
                         "schema": {
-                            "$ref": "#/definitions/models.Series"
+                            "$ref": "#/definitions/models.ExportDocument"
+                        },
+                        "headers": {
+                            "Content-Disposition": {
+                                "type": "string",
+                                "description": "attachment; filename=\\\"series-AAAA-MM-DD.ext\\"
+                            }
                         }
                     },
                     "400": {
-                        "description": "ID proporcionado inválido",
+                        "description": "Formato desconocido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Serie no encontrada",
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al actualizar el ranking",
+                        "description": "Error interno del servidor al leer las series",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -307,50 +355,98 @@ const docTemplate = `{
                 }
             }
         },
-        "/series/{id}/episode": {
-            "patch": {
-                "description": "Incrementa en 1 el campo 'lastEpisodeWatched' de la serie identificada por ID. No realiza cambios si el último episodio visto ya es igual o mayor al total de episodios.",
+        "/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Importa las entradas de una exportación XML de MyAnimeList (también comprimida, .xml.gz) o de un volcado JSON de AniList (respuesta GraphQL de MediaListCollection o array de entradas). El archivo se envía como cuerpo de la solicitud o en el campo multipart 'file' (hasta 16 MiB). Los estados y el progreso se convierten a los de la aplicación y la puntuación se guarda en la reseña del usuario (conservando sus notas). Las entradas se deduplican por título, sin distinguir mayúsculas: si ya existe una serie con ese título se actualizan su estado y su progreso y se completan los datos descriptivos vacíos (o se omite con onDuplicate=skip), y las entradas repetidas en el archivo se omiten. Cada entrada se guarda por separado: un error en una fila no impide importar las demás. La respuesta detalla el resultado de cada fila.",
                 "consumes": [
-                    "application/json"
+                    "text/xml",
+                    "application/json",
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Series Actions"
+                    "Import/Export"
                 ],
-                "summary": "Incrementar episodio visto",
+                "summary": "Importar una lista de MyAnimeList o AniList",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "ID de la Serie cuyo episodio se incrementará",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "mal",
+                            "anilist"
+                        ],
+                        "type": "string",
+                        "description": "Formato del archivo; si se omite se detecta por el contenido",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "update",
+                            "skip"
+                        ],
+                        "type": "string",
+                        "default": "update",
+                        "description": "Qué hacer si ya existe una serie con el mismo título",
+                        "name": "onDuplicate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Simular la importación sin guardar cambios",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Archivo de exportación (alternativa a enviarlo como cuerpo)",
+                        "name": "file",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Episodio incrementado, devuelve la serie actualizada",
+                        "description": "Resultado de la importación por fila",
                         "schema": {
-                            "$ref": "#/definitions/models.Series"
+                            "$ref": "#/definitions/handlers.ImportReport"
                         }
                     },
                     "400": {
-                        "description": "ID proporcionado inválido",
+                        "description": "Parámetros inválidos o archivo vacío, ilegible o de formato no reconocido",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Serie no encontrada",
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "El archivo supera los 16 MiB o las 10000 entradas",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al incrementar el episodio",
+                        "description": "Error interno del servidor al importar",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
@@ -358,69 +454,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/series/{id}/status": {
-            "patch": {
-                "description": "Actualiza únicamente el campo 'status' de una serie existente identificada por su ID.",
-                "consumes": [
-                    "application/json"
+        "/keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Devuelve las claves de API del usuario autenticado, incluidas las revocadas. El valor secreto de las claves nunca se incluye.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Series Actions"
-                ],
-                "summary": "Actualizar estado de una serie (parcial)",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "example": 1,
-                        "description": "ID de la Serie cuyo estado se actualizará",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Objeto JSON con el nuevo estado",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.StatusUpdate"
-                        }
-                    }
+                    "API Keys"
                 ],
+                "summary": "Listar claves de API",
                 "responses": {
                     "200": {
-                        "description": "Estado actualizado, devuelve la serie completa",
+                        "description": "Lista de claves de API",
                         "schema": {
-                            "$ref": "#/definitions/models.Series"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIKey"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Entrada inválida (ej. JSON mal formado, falta status, ID inválido)",
+                    "401": {
+                        "description": "No autenticado",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Serie no encontrada",
+                    "403": {
+                        "description": "La solicitud se autenticó con una clave de API",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Error interno del servidor al actualizar el estado",
+                        "description": "Error interno del servidor al obtener las claves",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/series/{id}/upvote": {
-            "patch": {
-                "description": "Incrementa en 1 el campo 'ranking' de la serie identificada por su ID.",
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Genera una clave de API para scripts e integraciones. El scope 'read' solo permite consultas; 'write' permite además modificar datos. El valor de la clave se devuelve únicamente en esta respuesta. Envíela en el header X-API-Key o como \"Authorization: Bearer \u003cclave\u003e\".",
                 "consumes": [
                     "application/json"
                 ],
@@ -428,66 +513,4960 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Series Actions"
+                    "API Keys"
                 ],
-                "summary": "Votar positivamente (Upvote) una serie",
+                "summary": "Crear una clave de API",
+                "parameters": [
+                    {
+                        "description": "Nombre y scope de la clave",
+                        "name": "key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Clave creada (incluye el valor secreto)",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyCreated"
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (JSON mal formado, nombre vacío o scope desconocido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "La solicitud se autenticó con una clave de API",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al crear la clave",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/keys/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoca una clave de API del usuario autenticado. A partir de ese momento la clave deja de aceptarse. La operación es idempotente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Revocar una clave de API",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "ID de la clave de API",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Sin contenido (clave revocada)"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "La solicitud se autenticó con una clave de API",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Clave de API no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al revocar la clave",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve las listas personalizadas del usuario autenticado, ordenadas por nombre, con la cantidad de series de cada una. Las series de una lista se obtienen con GET /api/series?list={id}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Listar listas personalizadas",
+                "responses": {
+                    "200": {
+                        "description": "Listas del usuario",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CustomList"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener las listas",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Crea una lista con nombre del usuario autenticado (por ejemplo \"Para ver con amigos\"). El nombre es único por usuario sin distinguir mayúsculas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Crear una lista personalizada",
+                "parameters": [
+                    {
+                        "description": "Nombre y descripción de la lista",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomListInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Lista creada",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomList"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON mal formado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya existe una lista con ese nombre",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Datos inválidos (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al crear la lista",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve el nombre, la descripción y la cantidad de series de una lista del usuario autenticado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Obtener una lista personalizada",
                 "parameters": [
                     {
                         "type": "integer",
                         "example": 1,
-                        "description": "ID de la Serie a votar positivamente",
+                        "description": "ID de la lista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomList"
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lista no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener la lista",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reemplaza el nombre y la descripción de una lista del usuario autenticado; sus series no cambian.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Modificar una lista personalizada",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la lista",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Nuevo nombre y descripción",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomListInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Lista actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.CustomList"
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lista no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya existe una lista con ese nombre",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Datos inválidos (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar la lista",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Elimina una lista del usuario autenticado. Las series de la lista no se eliminan.",
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Eliminar una lista personalizada",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la lista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Lista eliminada"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lista no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al eliminar la lista",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lists/{id}/series/{seriesId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Agrega la serie a la lista personalizada. Es idempotente: si la serie ya estaba no cambia nada.",
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Agregar una serie a una lista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la lista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "description": "ID de la Serie",
+                        "name": "seriesId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Serie agregada"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lista o serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al agregar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Quita la serie de la lista personalizada (la serie no se elimina). Es idempotente: si la serie no estaba no cambia nada.",
+                "tags": [
+                    "Lists"
+                ],
+                "summary": "Quitar una serie de una lista",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la lista",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 3,
+                        "description": "ID de la Serie",
+                        "name": "seriesId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Serie quitada"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Lista o serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al quitar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Obtiene una página de las series del usuario autenticado aplicando filtros, ordenamiento y paginación en el servidor. El total de resultados se devuelve en el header X-Total-Count y los enlaces de navegación en el header Link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Listar series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texto a buscar en el título (coincidencia parcial, sin distinguir mayúsculas)",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Plan to Watch",
+                            "Watching",
+                            "On Hold",
+                            "Completed",
+                            "Dropped"
+                        ],
+                        "type": "string",
+                        "description": "Filtrar por estado exacto",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ranking mínimo (inclusivo)",
+                        "name": "minRanking",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ranking máximo (inclusivo)",
+                        "name": "maxRanking",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "integer"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ID de etiqueta; puede repetirse y se devuelven las series con todas las etiquetas",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID de lista personalizada",
+                        "name": "list",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "title",
+                            "status",
+                            "lastEpisodeWatched",
+                            "totalEpisodes",
+                            "ranking",
+                            "score"
+                        ],
+                        "type": "string",
+                        "description": "Campo de ordenamiento (por defecto 'id', o 'ranking' si solo se indica 'sort'; con 'score' las series sin puntuar van al final)",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Dirección del ordenamiento",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número de página (empieza en 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Cantidad de series por página (máximo 500)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Página de series recuperada exitosamente",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Series"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last (RFC 8288)"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Número total de series que cumplen los filtros"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Query params inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al buscar series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Añade una nueva serie a la base de datos utilizando los datos proporcionados en el cuerpo de la solicitud. El ID es auto-generado por la base de datos. Si se omite el estado se usa 'Plan to Watch'; una serie cuyo último episodio visto alcanza el total se guarda como 'Completed'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Crear una nueva serie",
+                "parameters": [
+                    {
+                        "description": "Datos de la nueva serie a crear (el campo ID será ignorado)",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Serie creada exitosamente (devuelve el objeto completo con el nuevo ID)",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versión de la serie creada"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "JSON mal formado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Datos inválidos (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al guardar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ejecuta en orden, en una única transacción, hasta 500 operaciones sobre las series del usuario: 'create' (con 'series'), 'update' (reemplaza la serie 'id' con 'series'), 'delete' (a la papelera), 'status' (con 'status', como PATCH /series/{id}/status) y 'episode' (suma un episodio visto o, con 'episode', fija el progreso como PUT /series/{id}/episode). Cada operación puede indicar la versión esperada en 'version', como el header If-Match.\nEn modo 'atomic' (por defecto) la primera operación que falla deshace todo el lote: su resultado tiene el error y las demás el código 424. En modo 'bestEffort' cada operación se aplica o se descarta por separado. Los datos inválidos se detectan antes de empezar, de modo que en modo atómico un lote con errores de validación no modifica nada.\nLa respuesta es 200 en ambos casos, con 'committed' y el resultado de cada operación (código HTTP que habría tenido como solicitud individual, la serie resultante o el error).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Ejecutar un lote de operaciones",
+                "parameters": [
+                    {
+                        "description": "Modo y operaciones del lote",
+                        "name": "batch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resultado del lote y de cada operación",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.BatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON mal formado, modo desconocido o lote vacío",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "El lote supera las 500 operaciones",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al ejecutar el lote",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Busca en el título y los títulos alternativos (romaji, nativo, inglés...) de las series del usuario y devuelve las coincidencias de la más a la menos relevante. Coinciden las palabras completas, los comienzos de palabra ('shin' encuentra 'Shingeki no Kyojin') y las palabras con errores de escritura ('atack titan' encuentra 'Attack on Titan'), sin distinguir mayúsculas ni acentos. El orden de las palabras no importa.\nLa búsqueda usa el índice de texto completo del motor (FULLTEXT en MySQL, tsvector en PostgreSQL y FTS5 en SQLite) y un índice de trigramas para los errores de escritura (pg_trgm en PostgreSQL y FTS5 en SQLite; en MySQL se comparan los títulos del usuario). Las series de la papelera no se incluyen.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Buscar series por título",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "attack titan",
+                        "description": "Texto a buscar (hasta 200 caracteres)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Cantidad máxima de resultados (máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series encontradas, de mayor a menor relevancia",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Falta 'q' o los query params son inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al buscar series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/top": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve las series con mayor ranking (votos de todos los usuarios), de mayor a menor. Con scope=mine (por defecto) solo las del usuario autenticado, completas; con scope=all las de todos los usuarios, solo con sus datos públicos (models.PublicSeries), y no se admite 'status'.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Series mejor valoradas",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Cantidad de series (máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "mine",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "mine",
+                        "description": "Series propias o de todos los usuarios",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Plan to Watch",
+                            "Watching",
+                            "On Hold",
+                            "Completed",
+                            "Dropped"
+                        ],
+                        "type": "string",
+                        "description": "Filtrar por estado exacto (solo con scope=mine)",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Series ordenadas por ranking (models.PublicSeries con scope=all)",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Series"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Query params inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al buscar series",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Obtiene los detalles de una serie específica usando su ID numérico proporcionado en la URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Obtener una serie por ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "example": 1,
+                        "description": "ID de la Serie a buscar",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag conocido por el cliente; si coincide con la versión actual se responde 304",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Detalles de la serie encontrados",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Versión actual de la serie"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "La serie no cambió desde el ETag indicado"
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido (no es un número)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada con el ID proporcionado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al buscar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Actualiza todos los campos de una serie existente identificada por su ID, utilizando los datos proporcionados en el cuerpo de la solicitud. El cambio de estado debe ser una transición permitida (ver PATCH /series/{id}/status) y, si el último episodio visto alcanza el total, la serie pasa a 'Completed'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Actualizar una serie existente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie a actualizar",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Nuevos datos completos para la serie (se usará el ID de la URL, no el del cuerpo si existe)",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Serie actualizada exitosamente",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido en URL)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada con el ID proporcionado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Datos inválidos o transición de estado no permitida (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Mueve la serie a la papelera: deja de aparecer en el resto de la API, pero conserva su historial, reseña, votos, etiquetas y listas, y puede restaurarse con POST /series/{id}/restore hasta que se purga (por defecto, 30 días después).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Eliminar una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie a eliminar",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Sin contenido (movida a la papelera)"
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido (no es un número)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada para eliminar",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al eliminar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Modifica solo los campos indicados de una serie. Acepta JSON Merge Patch (RFC 7396, Content-Type application/merge-patch+json o application/json), por ejemplo {\"title\": \"Nuevo título\"}, o JSON Patch (RFC 6902, Content-Type application/json-patch+json), por ejemplo [{\"op\": \"replace\", \"path\": \"/totalEpisodes\", \"value\": 24}]. La serie resultante se valida igual que en PUT (incluidas las transiciones de estado). Los campos id, userId y version no se pueden modificar.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series"
+                ],
+                "summary": "Modificar parcialmente una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie a modificar",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Parche JSON Merge Patch (o un arreglo de operaciones JSON Patch)",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SeriesMergePatch"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Serie modificada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parche mal formado o ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Falló una operación 'test' del JSON Patch",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type no soportado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "El parche no se puede aplicar o la serie resultante es inválida (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al modificar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/cover": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Recibe una imagen JPEG, PNG, GIF o WebP (hasta 5 MiB y 10000 píxeles por lado) en el campo multipart 'cover', genera las miniaturas 'medium' (480 px de ancho) y 'small' (160 px) en JPEG y la guarda como portada de la serie. 'coverUrl' pasa a ser la ruta de la imagen original (/api/covers/{token}/original.{ext}); las miniaturas están en la misma ruta con medium.jpg y small.jpg. La portada anterior se elimina.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Subir la portada",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Imagen de portada",
+                        "name": "cover",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Portada guardada, devuelve la serie completa",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID inválido o cuerpo sin el campo 'cover'",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "La imagen supera los 5 MiB",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "El archivo no es una imagen JPEG, PNG, GIF o WebP",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Dimensiones de la imagen fuera de los límites",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al guardar la portada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Quita la portada de la serie: vacía 'coverUrl' (sea una imagen subida o una URL externa) y elimina las imágenes subidas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Quitar la portada",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Portada quitada, devuelve la serie completa",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al quitar la portada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/downvote": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registra el voto negativo del usuario sobre la serie, propia o de otro usuario. Si ya tenía un voto negativo lo retira y si tenía uno positivo lo reemplaza (un voto por usuario). El ranking es el ranking anterior a los votos (legacyRanking) más los votos positivos menos los negativos de todos los usuarios. Si la serie es de otro usuario solo se devuelven sus datos públicos (models.PublicSeries).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Votar negativamente (Downvote) una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie a votar negativamente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voto actualizado, devuelve la serie con el ranking recalculado",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar el ranking",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/episode": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fija el último episodio visto. 'episode' es absoluto o, si se indica 'season', relativo a esa temporada (requiere 'seasonEpisodes'). Avanzar pasa la serie a 'Watching' (o 'Completed' al llegar al final) y retroceder una serie 'Completed' la devuelve a 'Watching'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Ir a un episodio",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Episodio (absoluto o por temporada)",
+                        "name": "episode",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EpisodeRef"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Progreso actualizado, devuelve la serie actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Episodio o temporada fuera de rango (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar el progreso",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Incrementa en 1 el campo 'lastEpisodeWatched' de la serie identificada por ID. No realiza cambios si el último episodio visto ya es igual o mayor al total de episodios. Ver un episodio pasa la serie a 'Watching' y, al llegar al último episodio, a 'Completed'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Incrementar episodio visto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie cuyo episodio se incrementará",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Episodio incrementado, devuelve la serie actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al incrementar el episodio",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/episode/decrement": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Resta 1 al campo 'lastEpisodeWatched' de la serie identificada por ID (sin bajar de 0). Una serie 'Completed' vuelve a 'Watching'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Decrementar episodio visto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie cuyo episodio se decrementará",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Episodio decrementado, devuelve la serie actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "El progreso resultante supera el total de episodios (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al decrementar el episodio",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/episode/range": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Marca como vistos los episodios 'from'..'to' (inclusivo). Con 'season' los números son relativos a esa temporada y, si se omiten 'from' y 'to', se marca la temporada completa. El rango debe continuar el progreso actual (empezar como mucho en el episodio siguiente al último visto); los episodios ya vistos no cambian nada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Marcar un rango de episodios como vistos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Rango de episodios (absoluto o por temporada)",
+                        "name": "range",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.EpisodeRange"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Progreso actualizado, devuelve la serie actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Rango inválido o que deja episodios sin ver (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar el progreso",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve los cambios de progreso y de estado de la serie, del más reciente al más antiguo. Cada evento indica el último episodio visto y el estado antes y después del cambio. El total se devuelve en el header X-Total-Count y los enlaces de navegación en el header Link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Historial de visualización de una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Número de página (empieza en 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Cantidad de eventos por página (máximo 500)",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Página del historial",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WatchEvent"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "Enlaces first, prev, next y last (RFC 8288)"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Número total de eventos de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID o query params inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener el historial",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/history/undo": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Elimina el último evento del historial de la serie y la devuelve al episodio y estado que tenía antes de ese cambio (limitado al total de episodios actual si este bajó). Puede repetirse para seguir deshaciendo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Deshacer el último cambio de progreso",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cambio deshecho, devuelve la serie actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "La serie no tiene cambios para deshacer",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al deshacer el cambio",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saca la serie de la papelera con su historial, reseña, votos, etiquetas y listas. La versión de la serie aumenta.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Restaurar una serie de la papelera",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Serie restaurada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "La serie no está en la papelera",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al restaurar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/review": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve la puntuación personal y las notas en Markdown del usuario autenticado sobre la serie.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Obtener mi reseña",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reseña del usuario",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada o sin reseña",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener la reseña",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Guarda la puntuación personal (1 a 10, admite medios puntos) y las notas en Markdown del usuario autenticado sobre la serie. Reemplaza la reseña anterior por completo; se requiere al menos uno de los dos campos. La reseña no cambia la versión (ETag) de la serie.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Crear o reemplazar mi reseña",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Puntuación y notas",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReviewInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reseña reemplazada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    },
+                    "201": {
+                        "description": "Reseña creada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Puntuación o notas inválidas (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al guardar la reseña",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Elimina la puntuación y las notas del usuario autenticado sobre la serie.",
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Eliminar mi reseña",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Reseña eliminada"
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada o sin reseña",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al eliminar la reseña",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/rewatch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Empieza a ver de nuevo una serie 'Completed': pasa a 'Watching' y 'lastEpisodeWatched' vuelve a 0. Queda en el historial y se puede deshacer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Volver a ver una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Serie reiniciada, devuelve la serie actualizada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "La serie no está completada (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al reiniciar la serie",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/status": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Actualiza el campo 'status' de una serie existente identificada por su ID. Solo se permiten estas transiciones: 'Plan to Watch' → 'Watching', 'On Hold', 'Completed' o 'Dropped'; 'Watching' → 'On Hold', 'Completed' o 'Dropped'; 'On Hold' → 'Watching', 'Completed' o 'Dropped'; 'Completed' → 'Watching' (conserva el progreso; para empezar de nuevo ver POST /series/{id}/rewatch); 'Dropped' → 'Plan to Watch' o 'Watching'. Al marcarla como 'Completed' el último episodio visto pasa a ser el total de episodios (si se conoce).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Actualizar estado de una serie (parcial)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie cuyo estado se actualizará",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Objeto JSON con el nuevo estado",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estado actualizado, devuelve la serie completa",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Falta el estado, es desconocido o la transición no está permitida (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar el estado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve las etiquetas asignadas a la serie, ordenadas por nombre.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Etiquetas de una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Etiquetas de la serie",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener las etiquetas",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/tags/{tagId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Asigna la etiqueta a la serie. Es idempotente: si la serie ya la tenía no cambia nada.",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Etiquetar una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2,
+                        "description": "ID de la etiqueta",
+                        "name": "tagId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Etiqueta asignada"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie o etiqueta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al asignar la etiqueta",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Quita la etiqueta de la serie. Es idempotente: si la serie no la tenía no cambia nada.",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Quitar una etiqueta de una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "example": 2,
+                        "description": "ID de la etiqueta",
+                        "name": "tagId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Etiqueta quitada"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie o etiqueta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al quitar la etiqueta",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/upvote": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registra el voto positivo del usuario sobre la serie, propia o de otro usuario. Si ya tenía un voto positivo lo retira y si tenía uno negativo lo reemplaza (un voto por usuario). El ranking es el ranking anterior a los votos (legacyRanking) más los votos positivos menos los negativos de todos los usuarios. Si la serie es de otro usuario solo se devuelven sus datos públicos (models.PublicSeries).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Votar positivamente (Upvote) una serie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie a votar positivamente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voto actualizado, devuelve la serie con el ranking recalculado",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar el ranking",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/series/{id}/vote": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve el voto del usuario autenticado sobre la serie (propia o de otro usuario): 1 (positivo), -1 (negativo) o 0 (sin voto).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Obtener mi voto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voto del usuario",
+                        "schema": {
+                            "$ref": "#/definitions/models.VoteValue"
+                        }
+                    },
+                    "400": {
+                        "description": "ID proporcionado inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener el voto",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fija el voto del usuario autenticado sobre la serie, propia o de otro usuario: 1 (positivo), -1 (negativo) o 0 (retirar el voto). A diferencia de upvote/downvote no alterna: repetir el mismo valor no cambia nada. Si la serie es de otro usuario solo se devuelven sus datos públicos (models.PublicSeries).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Series Actions"
+                ],
+                "summary": "Fijar mi voto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la Serie",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag de la versión esperada; si la serie cambió se responde 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Nuevo voto",
+                        "name": "vote",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VoteValue"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Voto actualizado, devuelve la serie con el ranking recalculado",
+                        "schema": {
+                            "$ref": "#/definitions/models.Series"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Nueva versión de la serie"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Serie no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "If-Match no coincide con la versión actual",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Valor de voto inválido (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al actualizar el voto",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve estadísticas del usuario autenticado calculadas en la base de datos: totales, series y progreso medio por estado, episodios vistos por día, semana (desde el lunes) y mes según el historial, tiempo medio para completar una serie y rachas de días consecutivos viendo episodios. Las fechas se agrupan en UTC.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Estadísticas de visualización",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 30,
+                        "description": "Días incluidos en 'episodesPerDay' (máximo 366)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 12,
+                        "description": "Semanas incluidas en 'episodesPerWeek' (máximo 104)",
+                        "name": "weeks",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 12,
+                        "description": "Meses incluidos en 'episodesPerMonth' (máximo 120)",
+                        "name": "months",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estadísticas del usuario",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Query params inválidos",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al calcular las estadísticas",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve las etiquetas (géneros o categorías) del usuario autenticado, ordenadas por nombre, con la cantidad de series de cada una.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Listar etiquetas",
+                "responses": {
+                    "200": {
+                        "description": "Etiquetas del usuario",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Tag"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al obtener las etiquetas",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Crea una etiqueta del usuario autenticado. El nombre es único por usuario sin distinguir mayúsculas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Crear una etiqueta",
+                "parameters": [
+                    {
+                        "description": "Nombre de la etiqueta",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Etiqueta creada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "JSON mal formado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya existe una etiqueta con ese nombre",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Nombre inválido (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al crear la etiqueta",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Cambia el nombre de una etiqueta del usuario autenticado; las series etiquetadas la conservan.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Renombrar una etiqueta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la etiqueta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nuevo nombre",
+                        "name": "tag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Etiqueta renombrada",
+                        "schema": {
+                            "$ref": "#/definitions/models.Tag"
+                        }
+                    },
+                    "400": {
+                        "description": "Entrada inválida (ej. JSON mal formado, ID inválido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Etiqueta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Ya existe una etiqueta con ese nombre",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Nombre inválido (detalle por campo en 'errors')",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al renombrar la etiqueta",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Elimina una etiqueta del usuario autenticado y la quita de todas sus series (las series no se eliminan).",
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Eliminar una etiqueta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 1,
+                        "description": "ID de la etiqueta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Etiqueta eliminada"
+                    },
+                    "400": {
+                        "description": "ID inválido",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Etiqueta no encontrada",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al eliminar la etiqueta",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Devuelve las series eliminadas del usuario, de la más reciente a la más antigua, con la fecha de eliminación y la fecha a partir de la cual se purgan definitivamente.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Trash"
+                ],
+                "summary": "Listar la papelera",
+                "responses": {
+                    "200": {
+                        "description": "Series en la papelera",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TrashedSeries"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "No autenticado",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Permisos insuficientes (scope requerido)",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Error interno del servidor al leer la papelera",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.BatchOperation": {
+            "description": "Operación de un lote: 'create' y 'update' reciben la serie completa en 'series', 'status' el nuevo estado en 'status' y 'episode' suma un episodio visto o, con 'episode', fija el progreso.",
+            "type": "object",
+            "properties": {
+                "episode": {
+                    "description": "Episode fija el último episodio visto en 'episode'; si se omite se suma uno.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EpisodeRef"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID es la serie afectada (todas las operaciones salvo 'create').\nexample: 12",
+                    "type": "integer"
+                },
+                "op": {
+                    "description": "Op es el tipo de operación.\nexample: \"status\"",
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete",
+                        "status",
+                        "episode"
+                    ]
+                },
+                "series": {
+                    "description": "Series son los datos de la serie para 'create' y 'update'.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Series"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status es el nuevo estado para 'status'.\nexample: \"Completed\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                },
+                "version": {
+                    "description": "Version es la versión esperada de la serie, como el header If-Match (opcional).\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BatchRequest": {
+            "description": "Lote de operaciones sobre series ejecutadas en una transacción.",
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Mode es 'atomic' (por defecto: si una operación falla no se aplica\nninguna) o 'bestEffort' (se aplican las que no fallan).\nexample: \"atomic\"",
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "bestEffort"
+                    ]
+                },
+                "operations": {
+                    "description": "Operations son las operaciones, que se ejecutan en orden (hasta 500).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchOperation"
+                    }
+                }
+            }
+        },
+        "handlers.BatchResponse": {
+            "description": "Resultado de un lote de operaciones.",
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "Committed indica si se confirmó la transacción: en modo atómico es falso\nsi falló alguna operación (no se guardó nada).",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "example: 1",
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "Mode es el modo con el que se ejecutó el lote.\nexample: \"atomic\"",
+                    "type": "string"
+                },
+                "results": {
+                    "description": "Results tiene el resultado de cada operación, en el orden del lote.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.BatchResult"
+                    }
+                },
+                "succeeded": {
+                    "description": "Succeeded y Failed cuentan las operaciones aplicadas y las que fallaron\n(sin contar las no aplicadas por el fallo de otra).\nexample: 29",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.BatchResult": {
+            "description": "Resultado de una operación del lote, con el código HTTP que habría tenido como solicitud individual.",
+            "type": "object",
+            "properties": {
+                "error": {
+                    "description": "Error describe por qué la operación falló o no se aplicó.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "ID es la serie afectada (la creada, en 'create').\nexample: 12",
+                    "type": "integer"
+                },
+                "index": {
+                    "description": "Index es la posición (desde 0) de la operación en el lote.\nexample: 0",
+                    "type": "integer"
+                },
+                "op": {
+                    "description": "Op es el tipo de operación.\nexample: \"status\"",
+                    "type": "string"
+                },
+                "series": {
+                    "description": "Series es la serie resultante (salvo en 'delete' o si falló).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Series"
+                        }
+                    ]
+                },
+                "status": {
+                    "description": "Status es el código HTTP del resultado: 200, 201 o 204 si se aplicó,\n4xx o 5xx si falló y 424 si no se aplicó porque falló otra operación del lote atómico.\nexample: 200",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "description": "Estructura estándar para errores de la API con un mensaje descriptivo y, en los errores de validación, el detalle por campo.",
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "Errors detalla los campos inválidos (solo en errores de validación).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FieldError"
+                    }
+                },
+                "message": {
+                    "description": "Message contiene el mensaje descriptivo del error ocurrido.\nexample: \"Serie no encontrada\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.FieldError": {
+            "description": "Error de validación asociado a un campo.",
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Field es el nombre del campo en el JSON de la solicitud.\nexample: \"lastEpisodeWatched\"",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message describe el problema.\nexample: \"no puede ser mayor que 'totalEpisodes' (12)\"",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportReport": {
+            "description": "Resumen y detalle por fila de una importación.",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "example: 100",
+                    "type": "integer"
+                },
+                "dryRun": {
+                    "description": "DryRun indica que no se guardó ningún cambio (solo se simuló la importación).",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "description": "example: 2",
+                    "type": "integer"
+                },
+                "format": {
+                    "description": "Format es el formato del archivo: 'mal' o 'anilist'.\nexample: \"mal\"",
+                    "type": "string"
+                },
+                "rows": {
+                    "description": "Rows tiene el resultado de cada entrada, en el orden del archivo.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ImportRow"
+                    }
+                },
+                "skipped": {
+                    "description": "example: 6",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total, Created, Updated, Skipped y Failed cuentan las filas por resultado.\nexample: 120",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "example: 12",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ImportRow": {
+            "description": "Resultado de una entrada de la importación.",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action es 'created', 'updated', 'skipped' o 'failed'.\nexample: \"updated\"",
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "updated",
+                        "skipped",
+                        "failed"
+                    ]
+                },
+                "errors": {
+                    "description": "Errors detalla los campos inválidos de la entrada, si falló por validación.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.FieldError"
+                    }
+                },
+                "message": {
+                    "description": "Message explica por qué la entrada se omitió o falló.\nexample: \"sin cambios\"",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "Row es la posición (desde 1) de la entrada en el archivo.\nexample: 3",
+                    "type": "integer"
+                },
+                "seriesId": {
+                    "description": "SeriesID es el ID de la serie creada, actualizada u omitida por existir (0 si no hay).\nexample: 12",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Title es el título de la entrada.\nexample: \"Cowboy Bebop\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "description": "Clave de API (sin el valor secreto).",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt es la fecha de creación de la clave.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID es el identificador único de la clave.\nexample: 1",
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "description": "LastUsedAt es la última vez que se usó la clave (aproximada al minuto).",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name es una descripción libre para reconocer la clave.\nexample: \"cron de sincronización\"",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix son los primeros caracteres de la clave, útiles para identificarla.\nexample: \"stk_Ab12Cd34\"",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "description": "RevokedAt indica cuándo se revocó la clave; una clave revocada no se acepta.",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Scope es el permiso de la clave: 'read' (solo lectura) o 'write' (lectura y escritura).\nexample: \"read\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKeyCreate": {
+            "description": "Datos para crear una clave de API.",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Name es una descripción libre de la clave (obligatoria, hasta 100 caracteres).\nexample: \"bot de Discord\"\nrequired: true",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Scope es 'read' o 'write'. Por defecto 'read'.\nexample: \"write\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKeyCreated": {
+            "description": "Clave de API recién creada; el valor de 'key' no se vuelve a mostrar.",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt es la fecha de creación de la clave.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID es el identificador único de la clave.\nexample: 1",
+                    "type": "integer"
+                },
+                "key": {
+                    "description": "Key es el valor secreto de la clave. Guárdelo: no se puede recuperar después.\nexample: \"stk_Ab12Cd34Ef56Gh78Ij90Kl12Mn34Op56Qr78St90Uv1\"",
+                    "type": "string"
+                },
+                "lastUsedAt": {
+                    "description": "LastUsedAt es la última vez que se usó la clave (aproximada al minuto).",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name es una descripción libre para reconocer la clave.\nexample: \"cron de sincronización\"",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Prefix son los primeros caracteres de la clave, útiles para identificarla.\nexample: \"stk_Ab12Cd34\"",
+                    "type": "string"
+                },
+                "revokedAt": {
+                    "description": "RevokedAt indica cuándo se revocó la clave; una clave revocada no se acepta.",
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "Scope es el permiso de la clave: 'read' (solo lectura) o 'write' (lectura y escritura).\nexample: \"read\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AiringStatus": {
+            "type": "string",
+            "enum": [
+                "Not Yet Aired",
+                "Currently Airing",
+                "Finished Airing"
+            ],
+            "x-enum-varnames": [
+                "AiringNotYetAired",
+                "AiringCurrently",
+                "AiringFinished"
+            ]
+        },
+        "models.AuthResponse": {
+            "description": "Token de sesión emitido para el usuario autenticado.",
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "ExpiresAt es el instante en que el token deja de ser válido.",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Token debe enviarse en el header \"Authorization: Bearer \u003ctoken\u003e\".\nexample: \"T2hJbUFUb2tlbkV4YW1wbGVWYWx1ZUZvckRvY3M\"",
+                    "type": "string"
+                },
+                "user": {
+                    "description": "User contiene los datos públicos del usuario autenticado.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Credentials": {
+            "description": "Credenciales de acceso de un usuario.",
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "description": "Password es la contraseña en texto plano (mínimo 8 caracteres).\nexample: \"s3cr3t-pass\"\nrequired: true",
+                    "type": "string"
+                },
+                "username": {
+                    "description": "Username es el nombre de usuario (3 a 50 caracteres: letras, números, '.', '_' o '-').\nexample: \"jonialen\"\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.CustomList": {
+            "description": "Lista personalizada de series.",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt y UpdatedAt son las fechas de creación y última modificación.",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "Description es una descripción libre de la lista (opcional).\nexample: \"Series para el viernes\"",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID es el identificador único de la lista.\nexample: 1",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name es el nombre de la lista, único por usuario (sin distinguir mayúsculas).\nexample: \"Para ver con amigos\"",
+                    "type": "string"
+                },
+                "seriesCount": {
+                    "description": "SeriesCount es la cantidad de series de la lista. La calcula el servidor.\nexample: 3",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CustomListInput": {
+            "description": "Datos de una lista personalizada.",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "description": "Description es una descripción libre (opcional, hasta 1000 caracteres).\nexample: \"Series para el viernes\"",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Name es el nombre de la lista (obligatorio, hasta 100 caracteres).\nexample: \"Para ver con amigos\"\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.EpisodeRange": {
+            "description": "Rango inclusivo de episodios, absoluto o dentro de una temporada.",
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From es el primer episodio del rango.\nexample: 1",
+                    "type": "integer"
+                },
+                "season": {
+                    "description": "Season es la temporada (opcional).\nexample: 2",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "To es el último episodio del rango.\nexample: 25",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EpisodeRef": {
+            "description": "Episodio por número absoluto o relativo a una temporada.",
+            "type": "object",
+            "properties": {
+                "episode": {
+                    "description": "Episode es el número de episodio (0 indica que no se vio ninguno).\nexample: 5",
+                    "type": "integer"
+                },
+                "season": {
+                    "description": "Season es la temporada (opcional); si se indica, Episode es relativo a ella.\nexample: 2",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExportDocument": {
+            "description": "Exportación completa de la lista del usuario.",
+            "type": "object",
+            "properties": {
+                "exportedAt": {
+                    "description": "ExportedAt es el instante de la exportación (UTC).",
+                    "type": "string"
+                },
+                "series": {
+                    "description": "Series son todas las series del usuario, por id ascendente.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SeriesExport"
+                    }
+                }
+            }
+        },
+        "models.MediaType": {
+            "type": "string",
+            "enum": [
+                "TV",
+                "Movie",
+                "OVA",
+                "ONA",
+                "Special"
+            ],
+            "x-enum-varnames": [
+                "MediaTypeTV",
+                "MediaTypeMovie",
+                "MediaTypeOVA",
+                "MediaTypeONA",
+                "MediaTypeSpecial"
+            ]
+        },
+        "models.PeriodCount": {
+            "description": "Episodios vistos en un día, semana (desde el lunes) o mes.",
+            "type": "object",
+            "properties": {
+                "episodes": {
+                    "description": "example: 6",
+                    "type": "integer"
+                },
+                "period": {
+                    "description": "Period es el día o la semana (\"2006-01-02\") o el mes (\"2006-01\").\nexample: \"2024-05-13\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Review": {
+            "description": "Reseña personal de una serie: puntuación de 1 a 10 (admite medios puntos) y notas en Markdown.",
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "Body son las notas o la reseña en formato Markdown (opcional).\nexample: \"La **segunda temporada** es mucho mejor.\"",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "description": "CreatedAt y UpdatedAt son las fechas de creación y última modificación.",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score es la puntuación de 1 a 10 en pasos de 0.5 (opcional).\nexample: 8.5",
+                    "type": "number"
+                },
+                "seriesId": {
+                    "description": "SeriesID es la serie reseñada.\nexample: 1",
+                    "type": "integer"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewInput": {
+            "description": "Puntuación y notas de la reseña; al menos uno de los dos campos es obligatorio.",
+            "type": "object",
+            "properties": {
+                "body": {
+                    "description": "Body son las notas en Markdown.\nexample: \"La **segunda temporada** es mucho mejor.\"",
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score es la puntuación de 1 a 10 en pasos de 0.5; null o ausente para no puntuar.\nexample: 8.5",
+                    "type": "number"
+                }
+            }
+        },
+        "models.SearchResult": {
+            "description": "Serie encontrada por la búsqueda, con su relevancia y el título que coincidió.",
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "airingStatus": {
+                    "description": "AiringStatus es el estado de emisión: 'Not Yet Aired', 'Currently Airing' o\n'Finished Airing' (vacío si no se conoce). No depende del progreso del usuario.\nexample: \"Finished Airing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AiringStatus"
+                        }
+                    ]
+                },
+                "altTitles": {
+                    "description": "AltTitles son los títulos alternativos de la serie (opcional): romaji,\nnativo, inglés, abreviaturas... La búsqueda (GET /api/series/search) los\nconsidera igual que el título.\nexample: [\"Shingeki no Kyojin\",\"進撃の巨人\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "coverUrl": {
+                    "description": "CoverURL es la URL (http o https) de la imagen de portada (opcional). Al\nsubir una portada el servidor la reemplaza por la ruta de la imagen original.\nexample: \"https://cdn.example.com/covers/attack-on-titan.jpg\"",
+                    "type": "string"
+                },
+                "currentEpisode": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "currentSeason": {
+                    "description": "CurrentSeason es la temporada del último episodio visto y CurrentEpisode su\nnúmero dentro de esa temporada. Los calcula el servidor (0 sin temporadas).\nexample: 2",
+                    "type": "integer"
+                },
+                "downvotes": {
+                    "description": "example: 1",
+                    "type": "integer"
+                },
+                "endYear": {
+                    "description": "example: 2023",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID es el identificador único de la serie (Clave primaria, autoincremental).\nexample: 1",
+                    "type": "integer"
+                },
+                "lastEpisodeWatched": {
+                    "description": "LastEpisodeWatched es el número del último episodio que el usuario ha visto.\nexample: 10",
+                    "type": "integer"
+                },
+                "legacyRanking": {
+                    "description": "LegacyRanking es el ranking acumulado antes de que existieran los votos por\nusuario (migración 0008_create_votes); se conserva y se suma a los votos.\nLo asigna el servidor y es 0 en las series creadas después.\nexample: 0",
+                    "type": "integer"
+                },
+                "matchedTitle": {
+                    "description": "MatchedTitle es el título (principal o alternativo) que mejor coincidió.\nexample: \"Shingeki no Kyojin\"",
+                    "type": "string"
+                },
+                "mediaType": {
+                    "description": "MediaType es el formato: 'TV', 'Movie', 'OVA', 'ONA' o 'Special' (vacío si no se conoce).\nexample: \"TV\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MediaType"
+                        }
+                    ]
+                },
+                "ranking": {
+                    "description": "Ranking es la puntuación agregada de los votos (LegacyRanking + Upvotes - Downvotes).\nLa calcula el servidor; se ignora si viene en el cuerpo de la solicitud.\nSe modifica mediante los endpoints de voto (upvote/downvote/vote).\nexample: 8",
+                    "type": "integer"
+                },
+                "score": {
+                    "description": "Score es la relevancia de la serie para la búsqueda, entre 0 y 1 (1 es el\ntítulo exacto). Los resultados se ordenan de mayor a menor.\nexample: 0.87",
+                    "type": "number"
+                },
+                "seasonEpisodes": {
+                    "description": "SeasonEpisodes es la cantidad de episodios de cada temporada (opcional).\nSi se indica, TotalEpisodes se calcula como su suma y el progreso puede\nconsultarse y modificarse por temporada.\nexample: [25,25,24]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "seasons": {
+                    "description": "Seasons es la cantidad de temporadas. Lo calcula el servidor a partir de SeasonEpisodes.\nexample: 3",
+                    "type": "integer"
+                },
+                "startYear": {
+                    "description": "StartYear y EndYear son los años de inicio y fin de emisión (opcionales).\nexample: 2013",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status indica el estado actual de visualización de la serie.\nDebe ser uno de: 'Plan to Watch', 'Watching', 'On Hold', 'Completed', 'Dropped'.\nSi se omite al crear, se usa 'Plan to Watch'.\nexample: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                },
+                "studio": {
+                    "description": "Studio es el estudio de animación o la productora (opcional).\nexample: \"Wit Studio\"",
+                    "type": "string"
+                },
+                "synopsis": {
+                    "description": "Synopsis es la sinopsis de la serie (opcional).\nexample: \"La humanidad vive tras enormes murallas que la protegen de los titanes.\"",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Title es el título de la serie. Es un campo obligatorio.\nexample: \"Attack on Titan\"\nrequired: true",
+                    "type": "string"
+                },
+                "totalEpisodes": {
+                    "description": "TotalEpisodes es el número total de episodios que tiene la serie.\nexample: 24",
+                    "type": "integer"
+                },
+                "upvotes": {
+                    "description": "Upvotes y Downvotes son la cantidad de votos positivos y negativos (un voto por usuario).\nexample: 9",
+                    "type": "integer"
+                },
+                "userId": {
+                    "description": "UserID es el ID del usuario dueño de la serie. Lo asigna el servidor a partir\ndel usuario autenticado; se ignora si viene en el cuerpo de la solicitud.\nexample: 1",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version es el número de versión de la serie; aumenta en cada modificación.\nLo asigna el servidor y se expone también en el header ETag para el control\nde concurrencia optimista (If-Match).\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Series": {
+            "description": "Estructura de datos para una Serie de TV.",
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "airingStatus": {
+                    "description": "AiringStatus es el estado de emisión: 'Not Yet Aired', 'Currently Airing' o\n'Finished Airing' (vacío si no se conoce). No depende del progreso del usuario.\nexample: \"Finished Airing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AiringStatus"
+                        }
+                    ]
+                },
+                "altTitles": {
+                    "description": "AltTitles son los títulos alternativos de la serie (opcional): romaji,\nnativo, inglés, abreviaturas... La búsqueda (GET /api/series/search) los\nconsidera igual que el título.\nexample: [\"Shingeki no Kyojin\",\"進撃の巨人\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "coverUrl": {
+                    "description": "CoverURL es la URL (http o https) de la imagen de portada (opcional). Al\nsubir una portada el servidor la reemplaza por la ruta de la imagen original.\nexample: \"https://cdn.example.com/covers/attack-on-titan.jpg\"",
+                    "type": "string"
+                },
+                "currentEpisode": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "currentSeason": {
+                    "description": "CurrentSeason es la temporada del último episodio visto y CurrentEpisode su\nnúmero dentro de esa temporada. Los calcula el servidor (0 sin temporadas).\nexample: 2",
+                    "type": "integer"
+                },
+                "downvotes": {
+                    "description": "example: 1",
+                    "type": "integer"
+                },
+                "endYear": {
+                    "description": "example: 2023",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID es el identificador único de la serie (Clave primaria, autoincremental).\nexample: 1",
+                    "type": "integer"
+                },
+                "lastEpisodeWatched": {
+                    "description": "LastEpisodeWatched es el número del último episodio que el usuario ha visto.\nexample: 10",
+                    "type": "integer"
+                },
+                "legacyRanking": {
+                    "description": "LegacyRanking es el ranking acumulado antes de que existieran los votos por\nusuario (migración 0008_create_votes); se conserva y se suma a los votos.\nLo asigna el servidor y es 0 en las series creadas después.\nexample: 0",
+                    "type": "integer"
+                },
+                "mediaType": {
+                    "description": "MediaType es el formato: 'TV', 'Movie', 'OVA', 'ONA' o 'Special' (vacío si no se conoce).\nexample: \"TV\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MediaType"
+                        }
+                    ]
+                },
+                "ranking": {
+                    "description": "Ranking es la puntuación agregada de los votos (LegacyRanking + Upvotes - Downvotes).\nLa calcula el servidor; se ignora si viene en el cuerpo de la solicitud.\nSe modifica mediante los endpoints de voto (upvote/downvote/vote).\nexample: 8",
+                    "type": "integer"
+                },
+                "seasonEpisodes": {
+                    "description": "SeasonEpisodes es la cantidad de episodios de cada temporada (opcional).\nSi se indica, TotalEpisodes se calcula como su suma y el progreso puede\nconsultarse y modificarse por temporada.\nexample: [25,25,24]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "seasons": {
+                    "description": "Seasons es la cantidad de temporadas. Lo calcula el servidor a partir de SeasonEpisodes.\nexample: 3",
+                    "type": "integer"
+                },
+                "startYear": {
+                    "description": "StartYear y EndYear son los años de inicio y fin de emisión (opcionales).\nexample: 2013",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status indica el estado actual de visualización de la serie.\nDebe ser uno de: 'Plan to Watch', 'Watching', 'On Hold', 'Completed', 'Dropped'.\nSi se omite al crear, se usa 'Plan to Watch'.\nexample: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                },
+                "studio": {
+                    "description": "Studio es el estudio de animación o la productora (opcional).\nexample: \"Wit Studio\"",
+                    "type": "string"
+                },
+                "synopsis": {
+                    "description": "Synopsis es la sinopsis de la serie (opcional).\nexample: \"La humanidad vive tras enormes murallas que la protegen de los titanes.\"",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Title es el título de la serie. Es un campo obligatorio.\nexample: \"Attack on Titan\"\nrequired: true",
+                    "type": "string"
+                },
+                "totalEpisodes": {
+                    "description": "TotalEpisodes es el número total de episodios que tiene la serie.\nexample: 24",
+                    "type": "integer"
+                },
+                "upvotes": {
+                    "description": "Upvotes y Downvotes son la cantidad de votos positivos y negativos (un voto por usuario).\nexample: 9",
+                    "type": "integer"
+                },
+                "userId": {
+                    "description": "UserID es el ID del usuario dueño de la serie. Lo asigna el servidor a partir\ndel usuario autenticado; se ignora si viene en el cuerpo de la solicitud.\nexample: 1",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version es el número de versión de la serie; aumenta en cada modificación.\nLo asigna el servidor y se expone también en el header ETag para el control\nde concurrencia optimista (If-Match).\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeriesExport": {
+            "description": "Serie exportada con sus etiquetas, listas, reseña e historial de visualización.",
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "airingStatus": {
+                    "description": "AiringStatus es el estado de emisión: 'Not Yet Aired', 'Currently Airing' o\n'Finished Airing' (vacío si no se conoce). No depende del progreso del usuario.\nexample: \"Finished Airing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AiringStatus"
+                        }
+                    ]
+                },
+                "altTitles": {
+                    "description": "AltTitles son los títulos alternativos de la serie (opcional): romaji,\nnativo, inglés, abreviaturas... La búsqueda (GET /api/series/search) los\nconsidera igual que el título.\nexample: [\"Shingeki no Kyojin\",\"進撃の巨人\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "coverUrl": {
+                    "description": "CoverURL es la URL (http o https) de la imagen de portada (opcional). Al\nsubir una portada el servidor la reemplaza por la ruta de la imagen original.\nexample: \"https://cdn.example.com/covers/attack-on-titan.jpg\"",
+                    "type": "string"
+                },
+                "currentEpisode": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "currentSeason": {
+                    "description": "CurrentSeason es la temporada del último episodio visto y CurrentEpisode su\nnúmero dentro de esa temporada. Los calcula el servidor (0 sin temporadas).\nexample: 2",
+                    "type": "integer"
+                },
+                "downvotes": {
+                    "description": "example: 1",
+                    "type": "integer"
+                },
+                "endYear": {
+                    "description": "example: 2023",
+                    "type": "integer"
+                },
+                "history": {
+                    "description": "History es el historial de visualización, del evento más antiguo al más reciente.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WatchEvent"
+                    }
+                },
+                "id": {
+                    "description": "ID es el identificador único de la serie (Clave primaria, autoincremental).\nexample: 1",
+                    "type": "integer"
+                },
+                "lastEpisodeWatched": {
+                    "description": "LastEpisodeWatched es el número del último episodio que el usuario ha visto.\nexample: 10",
+                    "type": "integer"
+                },
+                "legacyRanking": {
+                    "description": "LegacyRanking es el ranking acumulado antes de que existieran los votos por\nusuario (migración 0008_create_votes); se conserva y se suma a los votos.\nLo asigna el servidor y es 0 en las series creadas después.\nexample: 0",
+                    "type": "integer"
+                },
+                "lists": {
+                    "description": "Lists son los nombres de las listas personalizadas que contienen la serie.\nexample: [\"Favoritas\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "mediaType": {
+                    "description": "MediaType es el formato: 'TV', 'Movie', 'OVA', 'ONA' o 'Special' (vacío si no se conoce).\nexample: \"TV\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MediaType"
+                        }
+                    ]
+                },
+                "ranking": {
+                    "description": "Ranking es la puntuación agregada de los votos (LegacyRanking + Upvotes - Downvotes).\nLa calcula el servidor; se ignora si viene en el cuerpo de la solicitud.\nSe modifica mediante los endpoints de voto (upvote/downvote/vote).\nexample: 8",
+                    "type": "integer"
+                },
+                "review": {
+                    "description": "Review es la reseña del usuario (null si no tiene).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Review"
+                        }
+                    ]
+                },
+                "seasonEpisodes": {
+                    "description": "SeasonEpisodes es la cantidad de episodios de cada temporada (opcional).\nSi se indica, TotalEpisodes se calcula como su suma y el progreso puede\nconsultarse y modificarse por temporada.\nexample: [25,25,24]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Ranking incrementado, devuelve la serie actualizada",
-                        "schema": {
-                            "$ref": "#/definitions/models.Series"
+                },
+                "seasons": {
+                    "description": "Seasons es la cantidad de temporadas. Lo calcula el servidor a partir de SeasonEpisodes.\nexample: 3",
+                    "type": "integer"
+                },
+                "startYear": {
+                    "description": "StartYear y EndYear son los años de inicio y fin de emisión (opcionales).\nexample: 2013",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status indica el estado actual de visualización de la serie.\nDebe ser uno de: 'Plan to Watch', 'Watching', 'On Hold', 'Completed', 'Dropped'.\nSi se omite al crear, se usa 'Plan to Watch'.\nexample: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
                         }
-                    },
-                    "400": {
-                        "description": "ID proporcionado inválido",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                    ]
+                },
+                "studio": {
+                    "description": "Studio es el estudio de animación o la productora (opcional).\nexample: \"Wit Studio\"",
+                    "type": "string"
+                },
+                "synopsis": {
+                    "description": "Synopsis es la sinopsis de la serie (opcional).\nexample: \"La humanidad vive tras enormes murallas que la protegen de los titanes.\"",
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Tags son los nombres de las etiquetas de la serie, en orden alfabético.\nexample: [\"Isekai\",\"Fantasía\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "description": "Title es el título de la serie. Es un campo obligatorio.\nexample: \"Attack on Titan\"\nrequired: true",
+                    "type": "string"
+                },
+                "totalEpisodes": {
+                    "description": "TotalEpisodes es el número total de episodios que tiene la serie.\nexample: 24",
+                    "type": "integer"
+                },
+                "upvotes": {
+                    "description": "Upvotes y Downvotes son la cantidad de votos positivos y negativos (un voto por usuario).\nexample: 9",
+                    "type": "integer"
+                },
+                "userId": {
+                    "description": "UserID es el ID del usuario dueño de la serie. Lo asigna el servidor a partir\ndel usuario autenticado; se ignora si viene en el cuerpo de la solicitud.\nexample: 1",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version es el número de versión de la serie; aumenta en cada modificación.\nLo asigna el servidor y se expone también en el header ETag para el control\nde concurrencia optimista (If-Match).\nexample: 3",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeriesMergePatch": {
+            "description": "Campos modificables con JSON Merge Patch; los omitidos no cambian.",
+            "type": "object",
+            "properties": {
+                "airingStatus": {
+                    "description": "example: \"Finished Airing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AiringStatus"
                         }
-                    },
-                    "404": {
-                        "description": "Serie no encontrada",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                    ]
+                },
+                "altTitles": {
+                    "description": "example: [\"Shingeki no Kyojin\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "coverUrl": {
+                    "description": "example: \"https://cdn.example.com/covers/attack-on-titan.jpg\"",
+                    "type": "string"
+                },
+                "endYear": {
+                    "description": "example: 2023",
+                    "type": "integer"
+                },
+                "lastEpisodeWatched": {
+                    "description": "example: 10",
+                    "type": "integer"
+                },
+                "mediaType": {
+                    "description": "example: \"TV\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MediaType"
                         }
-                    },
-                    "500": {
-                        "description": "Error interno del servidor al actualizar el ranking",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                    ]
+                },
+                "seasonEpisodes": {
+                    "description": "example: [25,25,24]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "startYear": {
+                    "description": "example: 2013",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "example: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
                         }
+                    ]
+                },
+                "studio": {
+                    "description": "example: \"Wit Studio\"",
+                    "type": "string"
+                },
+                "synopsis": {
+                    "description": "example: \"La humanidad vive tras enormes murallas que la protegen de los titanes.\"",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "example: \"Attack on Titan\"",
+                    "type": "string"
+                },
+                "totalEpisodes": {
+                    "description": "example: 24",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SeriesStatus": {
+            "type": "string",
+            "enum": [
+                "Plan to Watch",
+                "Watching",
+                "On Hold",
+                "Completed",
+                "Dropped"
+            ],
+            "x-enum-varnames": [
+                "StatusPlanToWatch",
+                "StatusWatching",
+                "StatusOnHold",
+                "StatusCompleted",
+                "StatusDropped"
+            ]
+        },
+        "models.Stats": {
+            "description": "Estadísticas de visualización del usuario autenticado.",
+            "type": "object",
+            "properties": {
+                "averageDaysToFinish": {
+                    "description": "AverageDaysToFinish es el tiempo medio, en días, entre el primer cambio de\nprogreso de una serie y su paso a 'Completed'. Es null si no se completó ninguna.\nexample: 12.5",
+                    "type": "number"
+                },
+                "byStatus": {
+                    "description": "ByStatus agrupa las series por estado.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusStats"
+                    }
+                },
+                "episodesPerDay": {
+                    "description": "EpisodesPerDay, EpisodesPerWeek y EpisodesPerMonth son los episodios vistos\nen cada período reciente, del más antiguo al más reciente (incluye los períodos sin actividad).",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PeriodCount"
+                    }
+                },
+                "episodesPerMonth": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PeriodCount"
+                    }
+                },
+                "episodesPerWeek": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PeriodCount"
                     }
+                },
+                "streaks": {
+                    "description": "Streaks son las rachas de días consecutivos viendo al menos un episodio.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.StreakStats"
+                        }
+                    ]
+                },
+                "totals": {
+                    "description": "Totals son los totales generales.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.StatsTotals"
+                        }
+                    ]
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.ErrorResponse": {
-            "description": "Estructura estándar para errores de la API con un mensaje descriptivo.",
+        },
+        "models.StatsTotals": {
+            "description": "Totales de series, episodios y eventos del historial.",
             "type": "object",
             "properties": {
-                "message": {
-                    "description": "Message contiene el mensaje descriptivo del error ocurrido.\nexample: \"Serie no encontrada\"",
+                "episodesWatched": {
+                    "description": "EpisodesWatched es la suma del progreso actual (lastEpisodeWatched) de todas las series.\nexample: 830",
+                    "type": "integer"
+                },
+                "events": {
+                    "description": "Events es la cantidad de eventos del historial.\nexample: 315",
+                    "type": "integer"
+                },
+                "historyEpisodes": {
+                    "description": "HistoryEpisodes es la cantidad de episodios vistos según el historial (incluye los vueltos a ver).\nexample: 910",
+                    "type": "integer"
+                },
+                "series": {
+                    "description": "Series es la cantidad de series.\nexample: 42",
+                    "type": "integer"
+                },
+                "totalEpisodes": {
+                    "description": "TotalEpisodes es la suma de los episodios conocidos (totalEpisodes) de todas las series.\nexample: 1200",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StatusStats": {
+            "description": "Estadísticas de las series en un estado.",
+            "type": "object",
+            "properties": {
+                "completionRate": {
+                    "description": "CompletionRate es el progreso medio (0 a 1, lastEpisodeWatched / totalEpisodes)\nde las series del estado con total de episodios conocido. Es null si no hay ninguna.\nexample: 0.4",
+                    "type": "number"
+                },
+                "series": {
+                    "description": "Series es la cantidad de series en el estado.\nexample: 7",
+                    "type": "integer"
+                },
+                "share": {
+                    "description": "Share es la fracción (0 a 1) del total de series que está en el estado.\nexample: 0.25",
+                    "type": "number"
+                },
+                "status": {
+                    "description": "example: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.StatusUpdate": {
+            "description": "Estructura para la actualización parcial del estado de una serie.",
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "Status es el nuevo estado que se asignará a la serie. Campo obligatorio.\nexample: \"Completed\"\nrequired: true",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Streak": {
+            "description": "Racha de días consecutivos (UTC) viendo episodios.",
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "end": {
+                    "description": "example: \"2024-05-13\"",
+                    "type": "string"
+                },
+                "start": {
+                    "description": "example: \"2024-05-09\"",
                     "type": "string"
                 }
             }
         },
-        "models.Series": {
-            "description": "Estructura de datos para una Serie de TV.",
+        "models.StreakStats": {
+            "description": "Rachas de visualización.",
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "Current es la racha que termina hoy o ayer (null si no hay ninguna en curso).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Streak"
+                        }
+                    ]
+                },
+                "longest": {
+                    "description": "Longest es la racha más larga (null si no hay historial).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Streak"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.Tag": {
+            "description": "Etiqueta o género del usuario.",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt es la fecha de creación de la etiqueta.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID es el identificador único de la etiqueta.\nexample: 1",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Name es el nombre de la etiqueta, único por usuario (sin distinguir mayúsculas).\nexample: \"Isekai\"",
+                    "type": "string"
+                },
+                "seriesCount": {
+                    "description": "SeriesCount es la cantidad de series con la etiqueta. La calcula el servidor.\nexample: 4",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TagInput": {
+            "description": "Datos de una etiqueta.",
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Name es el nombre de la etiqueta (obligatorio, hasta 50 caracteres).\nexample: \"Isekai\"\nrequired: true",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TrashedSeries": {
+            "description": "Serie en la papelera; puede restaurarse hasta 'purgeAt'.",
             "type": "object",
             "required": [
                 "title"
             ],
             "properties": {
+                "airingStatus": {
+                    "description": "AiringStatus es el estado de emisión: 'Not Yet Aired', 'Currently Airing' o\n'Finished Airing' (vacío si no se conoce). No depende del progreso del usuario.\nexample: \"Finished Airing\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AiringStatus"
+                        }
+                    ]
+                },
+                "altTitles": {
+                    "description": "AltTitles son los títulos alternativos de la serie (opcional): romaji,\nnativo, inglés, abreviaturas... La búsqueda (GET /api/series/search) los\nconsidera igual que el título.\nexample: [\"Shingeki no Kyojin\",\"進撃の巨人\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "coverUrl": {
+                    "description": "CoverURL es la URL (http o https) de la imagen de portada (opcional). Al\nsubir una portada el servidor la reemplaza por la ruta de la imagen original.\nexample: \"https://cdn.example.com/covers/attack-on-titan.jpg\"",
+                    "type": "string"
+                },
+                "currentEpisode": {
+                    "description": "example: 5",
+                    "type": "integer"
+                },
+                "currentSeason": {
+                    "description": "CurrentSeason es la temporada del último episodio visto y CurrentEpisode su\nnúmero dentro de esa temporada. Los calcula el servidor (0 sin temporadas).\nexample: 2",
+                    "type": "integer"
+                },
+                "deletedAt": {
+                    "description": "DeletedAt es el momento en que la serie se movió a la papelera (UTC).",
+                    "type": "string"
+                },
+                "downvotes": {
+                    "description": "example: 1",
+                    "type": "integer"
+                },
+                "endYear": {
+                    "description": "example: 2023",
+                    "type": "integer"
+                },
                 "id": {
                     "description": "ID es el identificador único de la serie (Clave primaria, autoincremental).\nexample: 1",
                     "type": "integer"
@@ -496,12 +5475,55 @@ const docTemplate = `{
                     "description": "LastEpisodeWatched es el número del último episodio que el usuario ha visto.\nexample: 10",
                     "type": "integer"
                 },
+                "legacyRanking": {
+                    "description": "LegacyRanking es el ranking acumulado antes de que existieran los votos por\nusuario (migración 0008_create_votes); se conserva y se suma a los votos.\nLo asigna el servidor y es 0 en las series creadas después.\nexample: 0",
+                    "type": "integer"
+                },
+                "mediaType": {
+                    "description": "MediaType es el formato: 'TV', 'Movie', 'OVA', 'ONA' o 'Special' (vacío si no se conoce).\nexample: \"TV\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.MediaType"
+                        }
+                    ]
+                },
+                "purgeAt": {
+                    "description": "PurgeAt es el momento a partir del cual la serie se elimina definitivamente (UTC).",
+                    "type": "string"
+                },
                 "ranking": {
-                    "description": "Ranking es una puntuación o valoración asignada a la serie por el usuario.\nPuede ser modificada mediante los endpoints de upvote/downvote.\nexample: 8",
+                    "description": "Ranking es la puntuación agregada de los votos (LegacyRanking + Upvotes - Downvotes).\nLa calcula el servidor; se ignora si viene en el cuerpo de la solicitud.\nSe modifica mediante los endpoints de voto (upvote/downvote/vote).\nexample: 8",
+                    "type": "integer"
+                },
+                "seasonEpisodes": {
+                    "description": "SeasonEpisodes es la cantidad de episodios de cada temporada (opcional).\nSi se indica, TotalEpisodes se calcula como su suma y el progreso puede\nconsultarse y modificarse por temporada.\nexample: [25,25,24]",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "seasons": {
+                    "description": "Seasons es la cantidad de temporadas. Lo calcula el servidor a partir de SeasonEpisodes.\nexample: 3",
+                    "type": "integer"
+                },
+                "startYear": {
+                    "description": "StartYear y EndYear son los años de inicio y fin de emisión (opcionales).\nexample: 2013",
                     "type": "integer"
                 },
                 "status": {
-                    "description": "Status indica el estado actual de visualización de la serie.\nDebe ser uno de: 'Plan to Watch', 'Watching', 'Completed', 'Dropped'.\nexample: \"Watching\"",
+                    "description": "Status indica el estado actual de visualización de la serie.\nDebe ser uno de: 'Plan to Watch', 'Watching', 'On Hold', 'Completed', 'Dropped'.\nSi se omite al crear, se usa 'Plan to Watch'.\nexample: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                },
+                "studio": {
+                    "description": "Studio es el estudio de animación o la productora (opcional).\nexample: \"Wit Studio\"",
+                    "type": "string"
+                },
+                "synopsis": {
+                    "description": "Synopsis es la sinopsis de la serie (opcional).\nexample: \"La humanidad vive tras enormes murallas que la protegen de los titanes.\"",
                     "type": "string"
                 },
                 "title": {
@@ -511,22 +5533,114 @@ const docTemplate = `{
                 "totalEpisodes": {
                     "description": "TotalEpisodes es el número total de episodios que tiene la serie.\nexample: 24",
                     "type": "integer"
+                },
+                "upvotes": {
+                    "description": "Upvotes y Downvotes son la cantidad de votos positivos y negativos (un voto por usuario).\nexample: 9",
+                    "type": "integer"
+                },
+                "userId": {
+                    "description": "UserID es el ID del usuario dueño de la serie. Lo asigna el servidor a partir\ndel usuario autenticado; se ignora si viene en el cuerpo de la solicitud.\nexample: 1",
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version es el número de versión de la serie; aumenta en cada modificación.\nLo asigna el servidor y se expone también en el header ETag para el control\nde concurrencia optimista (If-Match).\nexample: 3",
+                    "type": "integer"
                 }
             }
         },
-        "models.StatusUpdate": {
-            "description": "Estructura para la actualización parcial del estado de una serie.",
+        "models.User": {
+            "description": "Cuenta de usuario (la contraseña nunca se incluye en las respuestas).",
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "description": "CreatedAt es la fecha de registro del usuario.",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "ID es el identificador único del usuario.\nexample: 1",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "Username es el nombre de usuario, único en el sistema.\nexample: \"jonialen\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.VoteValue": {
+            "description": "Voto del usuario: 1 (positivo), -1 (negativo) o 0 (sin voto).",
             "type": "object",
-            "required": [
-                "status"
-            ],
             "properties": {
+                "value": {
+                    "description": "example: 1",
+                    "type": "integer",
+                    "enum": [
+                        -1,
+                        0,
+                        1
+                    ]
+                }
+            }
+        },
+        "models.WatchEvent": {
+            "description": "Evento del historial de visualización de una serie.",
+            "type": "object",
+            "properties": {
+                "episode": {
+                    "description": "Episode es el último episodio visto después del cambio.\nexample: 11",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID es el identificador único del evento.\nexample: 15",
+                    "type": "integer"
+                },
+                "previousEpisode": {
+                    "description": "PreviousEpisode es el último episodio visto antes del cambio.\nexample: 10",
+                    "type": "integer"
+                },
+                "previousStatus": {
+                    "description": "PreviousStatus es el estado de la serie antes del cambio.\nexample: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                },
+                "seriesId": {
+                    "description": "SeriesID es la serie modificada.\nexample: 1",
+                    "type": "integer"
+                },
                 "status": {
-                    "description": "Status es el nuevo estado que se asignará a la serie. Campo obligatorio.\nexample: \"Completed\"\nrequired: true",
+                    "description": "Status es el estado de la serie después del cambio.\nexample: \"Watching\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesStatus"
+                        }
+                    ]
+                },
+                "userId": {
+                    "description": "UserID es el usuario que hizo el cambio (el dueño de la serie).\nexample: 1",
+                    "type": "integer"
+                },
+                "watchedAt": {
+                    "description": "WatchedAt es el instante del cambio (UTC).",
                     "type": "string"
                 }
             }
         }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "description": "Clave de API creada en /keys (prefijo \"stk_\").",
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
+        "BearerAuth": {
+            "description": "Token de sesión (obtenido en /auth/login o /auth/register), JWT firmado o clave de API, con el formato \"Bearer \u003ctoken\u003e\".",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        }
     }
 }`
 
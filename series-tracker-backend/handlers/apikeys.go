@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"lab6/auth"
+	"lab6/models"
+	"lab6/repository"
+)
+
+// maxAPIKeyNameLength es la longitud máxima del nombre de una clave de API.
+const maxAPIKeyNameLength = 100
+
+// APIKeyHandler agrupa los handlers de gestión de claves de API del usuario.
+type APIKeyHandler struct {
+	keys repository.APIKeyRepository
+}
+
+// NewAPIKeyHandler crea un APIKeyHandler que usa el repositorio indicado.
+func NewAPIKeyHandler(keys repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys}
+}
+
+// ListAPIKeys godoc
+// @Summary      Listar claves de API
+// @Description  Devuelve las claves de API del usuario autenticado, incluidas las revocadas. El valor secreto de las claves nunca se incluye.
+// @Tags         API Keys
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {array} models.APIKey "Lista de claves de API"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "La solicitud se autenticó con una clave de API"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener las claves"
+// @Router       /keys [get]
+func (h *APIKeyHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	keys, err := h.keys.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error obteniendo las claves de API: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+// CreateAPIKey godoc
+// @Summary      Crear una clave de API
+// @Description  Genera una clave de API para scripts e integraciones. El scope 'read' solo permite consultas; 'write' permite además modificar datos. El valor de la clave se devuelve únicamente en esta respuesta. Envíela en el header X-API-Key o como "Authorization: Bearer <clave>".
+// @Tags         API Keys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        key body models.APIKeyCreate true "Nombre y scope de la clave"
+// @Success      201 {object} models.APIKeyCreated "Clave creada (incluye el valor secreto)"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (JSON mal formado, nombre vacío o scope desconocido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "La solicitud se autenticó con una clave de API"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al crear la clave"
+// @Router       /keys [post]
+func (h *APIKeyHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var input models.APIKeyCreate
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" || utf8.RuneCountInString(input.Name) > maxAPIKeyNameLength {
+		writeError(w, http.StatusBadRequest, "El campo 'name' es obligatorio y admite hasta 100 caracteres")
+		return
+	}
+	if input.Scope == "" {
+		input.Scope = auth.ScopeRead
+	}
+	if input.Scope != auth.ScopeRead && input.Scope != auth.ScopeWrite {
+		writeError(w, http.StatusBadRequest, "El campo 'scope' debe ser 'read' o 'write'")
+		return
+	}
+
+	key, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error generando la clave de API: "+err.Error())
+		return
+	}
+
+	apiKey := models.APIKey{
+		UserID:    userID,
+		Name:      input.Name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scope:     input.Scope,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := h.keys.CreateAPIKey(r.Context(), &apiKey); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error creando la clave de API: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, models.APIKeyCreated{APIKey: apiKey, Key: key})
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revocar una clave de API
+// @Description  Revoca una clave de API del usuario autenticado. A partir de ese momento la clave deja de aceptarse. La operación es idempotente.
+// @Tags         API Keys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id path int true "ID de la clave de API" Format(int64)
+// @Success      204 "Sin contenido (clave revocada)"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "La solicitud se autenticó con una clave de API"
+// @Failure      404 {object} ErrorResponse "Clave de API no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al revocar la clave"
+// @Router       /keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.keys.RevokeAPIKey(r.Context(), userID, id); err != nil {
+		writeRepositoryError(w, err, "Clave de API no encontrada", "Error revocando la clave de API: ")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
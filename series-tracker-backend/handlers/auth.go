@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"lab6/auth"
+	"lab6/models"
+	"lab6/repository"
+)
+
+// usernamePattern define los nombres de usuario válidos.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{3,50}$`)
+
+const (
+	// minPasswordLength es la longitud mínima de una contraseña.
+	minPasswordLength = 8
+	// maxPasswordLength es el límite de bcrypt: los bytes adicionales se ignorarían.
+	maxPasswordLength = 72
+)
+
+// AuthHandler agrupa los handlers de registro e inicio/cierre de sesión.
+// La autenticación de las demás rutas la realiza AuthMiddleware.
+type AuthHandler struct {
+	users      repository.UserRepository
+	sessions   repository.SessionRepository
+	sessionTTL time.Duration
+}
+
+// NewAuthHandler crea un AuthHandler. Las sesiones emitidas expiran tras sessionTTL.
+func NewAuthHandler(users repository.UserRepository, sessions repository.SessionRepository, sessionTTL time.Duration) *AuthHandler {
+	return &AuthHandler{users: users, sessions: sessions, sessionTTL: sessionTTL}
+}
+
+// startSession crea una sesión nueva para el usuario y escribe la respuesta con el token.
+func (h *AuthHandler) startSession(w http.ResponseWriter, r *http.Request, user models.User, statusCode int) {
+	token, hash, err := auth.NewToken()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error generando el token: "+err.Error())
+		return
+	}
+
+	session := models.Session{
+		UserID:    user.ID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(h.sessionTTL),
+	}
+	if err := h.sessions.CreateSession(r.Context(), &session); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error creando la sesión: "+err.Error())
+		return
+	}
+
+	writeJSON(w, statusCode, models.AuthResponse{Token: token, ExpiresAt: session.ExpiresAt, User: user})
+}
+
+// Register godoc
+// @Summary      Registrar un usuario
+// @Description  Crea una cuenta nueva e inicia sesión automáticamente, devolviendo un token de sesión.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials body models.Credentials true "Nombre de usuario y contraseña"
+// @Success      201 {object} models.AuthResponse "Usuario creado y sesión iniciada"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (JSON mal formado, usuario o contraseña no válidos)"
+// @Failure      409 {object} ErrorResponse "El nombre de usuario ya está registrado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al registrar el usuario"
+// @Router       /auth/register [post]
+func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
+	var creds models.Credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+
+	creds.Username = strings.TrimSpace(creds.Username)
+	if !usernamePattern.MatchString(creds.Username) {
+		writeError(w, http.StatusBadRequest, "El campo 'username' debe tener entre 3 y 50 caracteres (letras, números, '.', '_' o '-')")
+		return
+	}
+	if len(creds.Password) < minPasswordLength || len(creds.Password) > maxPasswordLength {
+		writeError(w, http.StatusBadRequest, "El campo 'password' debe tener entre 8 y 72 caracteres")
+		return
+	}
+
+	hash, err := auth.HashPassword(creds.Password)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error procesando la contraseña: "+err.Error())
+		return
+	}
+
+	user := models.User{Username: creds.Username, PasswordHash: hash}
+	if err := h.users.CreateUser(r.Context(), &user); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			writeError(w, http.StatusConflict, "El nombre de usuario ya está registrado")
+		} else {
+			writeError(w, http.StatusInternalServerError, "Error registrando el usuario: "+err.Error())
+		}
+		return
+	}
+
+	h.startSession(w, r, user, http.StatusCreated)
+}
+
+// Login godoc
+// @Summary      Iniciar sesión
+// @Description  Verifica las credenciales y devuelve un token de sesión para usar en el header Authorization.
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        credentials body models.Credentials true "Nombre de usuario y contraseña"
+// @Success      200 {object} models.AuthResponse "Sesión iniciada"
+// @Failure      400 {object} ErrorResponse "Cuerpo de la solicitud inválido"
+// @Failure      401 {object} ErrorResponse "Credenciales incorrectas"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al iniciar sesión"
+// @Router       /auth/login [post]
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var creds models.Credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+
+	user, err := h.users.GetUserByUsername(r.Context(), strings.TrimSpace(creds.Username))
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		writeError(w, http.StatusInternalServerError, "Error buscando el usuario: "+err.Error())
+		return
+	}
+	// Mismo mensaje (y el mismo costo de bcrypt) para usuario inexistente y contraseña incorrecta
+	if err != nil {
+		auth.RejectPassword(creds.Password)
+		writeUnauthorized(w, "Usuario o contraseña incorrectos")
+		return
+	}
+	if !auth.CheckPassword(user.PasswordHash, creds.Password) {
+		writeUnauthorized(w, "Usuario o contraseña incorrectos")
+		return
+	}
+
+	h.startSession(w, r, user, http.StatusOK)
+}
+
+// PurgeSessions elimina las sesiones expiradas, que ya no autentican pero
+// seguirían ocupando la tabla de sesiones.
+func (h *AuthHandler) PurgeSessions(ctx context.Context) error {
+	purged, err := h.sessions.DeleteExpiredSessions(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("purgando las sesiones expiradas: %w", err)
+	}
+	if purged > 0 {
+		log.Printf("Sesiones expiradas eliminadas: %d", purged)
+	}
+	return nil
+}
+
+// Logout godoc
+// @Summary      Cerrar sesión
+// @Description  Invalida el token de sesión usado en la solicitud.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      204 "Sin contenido (sesión cerrada)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al cerrar la sesión"
+// @Router       /auth/logout [post]
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	token, ok := auth.BearerToken(r)
+	if !ok {
+		writeUnauthorized(w, "Se requiere autenticación")
+		return
+	}
+	if err := h.sessions.DeleteSession(r.Context(), auth.HashToken(token)); err != nil {
+		writeError(w, http.StatusInternalServerError, "Error cerrando la sesión: "+err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me godoc
+// @Summary      Usuario actual
+// @Description  Devuelve los datos del usuario autenticado.
+// @Tags         Auth
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200 {object} models.User "Usuario autenticado"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al buscar el usuario"
+// @Router       /auth/me [get]
+func (h *AuthHandler) Me(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+	user, err := h.users.GetUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeUnauthorized(w, "El usuario de la sesión ya no existe")
+		} else {
+			writeError(w, http.StatusInternalServerError, "Error buscando el usuario: "+err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, user)
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// maxBatchOperations limita la cantidad de operaciones de un lote.
+const maxBatchOperations = 500
+
+// Operaciones admitidas en "POST /api/series/batch".
+const (
+	batchCreate  = "create"
+	batchUpdate  = "update"
+	batchDelete  = "delete"
+	batchStatus  = "status"
+	batchEpisode = "episode"
+)
+
+// Modos de ejecución de un lote.
+const (
+	batchAtomic     = "atomic"
+	batchBestEffort = "bestEffort"
+)
+
+// errBatchAborted detiene un lote atómico tras la primera operación fallida,
+// para que la transacción se deshaga.
+var errBatchAborted = errors.New("lote cancelado")
+
+// BatchOperation es una operación de un lote.
+// @Description Operación de un lote: 'create' y 'update' reciben la serie completa en 'series', 'status' el nuevo estado en 'status' y 'episode' suma un episodio visto o, con 'episode', fija el progreso.
+type BatchOperation struct {
+	// Op es el tipo de operación.
+	// example: "status"
+	Op string `json:"op" enums:"create,update,delete,status,episode"`
+	// ID es la serie afectada (todas las operaciones salvo 'create').
+	// example: 12
+	ID int `json:"id,omitempty"`
+	// Version es la versión esperada de la serie, como el header If-Match (opcional).
+	// example: 3
+	Version *int `json:"version,omitempty"`
+	// Series son los datos de la serie para 'create' y 'update'.
+	Series *models.Series `json:"series,omitempty"`
+	// Status es el nuevo estado para 'status'.
+	// example: "Completed"
+	Status models.SeriesStatus `json:"status,omitempty"`
+	// Episode fija el último episodio visto en 'episode'; si se omite se suma uno.
+	Episode *models.EpisodeRef `json:"episode,omitempty"`
+}
+
+// BatchRequest es el cuerpo de "POST /api/series/batch".
+// @Description Lote de operaciones sobre series ejecutadas en una transacción.
+type BatchRequest struct {
+	// Mode es 'atomic' (por defecto: si una operación falla no se aplica
+	// ninguna) o 'bestEffort' (se aplican las que no fallan).
+	// example: "atomic"
+	Mode string `json:"mode,omitempty" enums:"atomic,bestEffort"`
+	// Operations son las operaciones, que se ejecutan en orden (hasta 500).
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult es el resultado de una operación del lote.
+// @Description Resultado de una operación del lote, con el código HTTP que habría tenido como solicitud individual.
+type BatchResult struct {
+	// Index es la posición (desde 0) de la operación en el lote.
+	// example: 0
+	Index int `json:"index"`
+	// Op es el tipo de operación.
+	// example: "status"
+	Op string `json:"op"`
+	// ID es la serie afectada (la creada, en 'create').
+	// example: 12
+	ID int `json:"id,omitempty"`
+	// Status es el código HTTP del resultado: 200, 201 o 204 si se aplicó,
+	// 4xx o 5xx si falló y 424 si no se aplicó porque falló otra operación del lote atómico.
+	// example: 200
+	Status int `json:"status"`
+	// Series es la serie resultante (salvo en 'delete' o si falló).
+	Series *models.Series `json:"series,omitempty"`
+	// Error describe por qué la operación falló o no se aplicó.
+	Error *ErrorResponse `json:"error,omitempty"`
+}
+
+// BatchResponse es la respuesta de "POST /api/series/batch".
+// @Description Resultado de un lote de operaciones.
+type BatchResponse struct {
+	// Mode es el modo con el que se ejecutó el lote.
+	// example: "atomic"
+	Mode string `json:"mode"`
+	// Committed indica si se confirmó la transacción: en modo atómico es falso
+	// si falló alguna operación (no se guardó nada).
+	Committed bool `json:"committed"`
+	// Succeeded y Failed cuentan las operaciones aplicadas y las que fallaron
+	// (sin contar las no aplicadas por el fallo de otra).
+	// example: 29
+	Succeeded int `json:"succeeded"`
+	// example: 1
+	Failed int `json:"failed"`
+	// Results tiene el resultado de cada operación, en el orden del lote.
+	Results []BatchResult `json:"results"`
+}
+
+// BatchSeries godoc
+// @Summary      Ejecutar un lote de operaciones
+// @Description  Ejecuta en orden, en una única transacción, hasta 500 operaciones sobre las series del usuario: 'create' (con 'series'), 'update' (reemplaza la serie 'id' con 'series'), 'delete' (a la papelera), 'status' (con 'status', como PATCH /series/{id}/status) y 'episode' (suma un episodio visto o, con 'episode', fija el progreso como PUT /series/{id}/episode). Cada operación puede indicar la versión esperada en 'version', como el header If-Match.
+// @Description  En modo 'atomic' (por defecto) la primera operación que falla deshace todo el lote: su resultado tiene el error y las demás el código 424. En modo 'bestEffort' cada operación se aplica o se descarta por separado. Los datos inválidos se detectan antes de empezar, de modo que en modo atómico un lote con errores de validación no modifica nada.
+// @Description  La respuesta es 200 en ambos casos, con 'committed' y el resultado de cada operación (código HTTP que habría tenido como solicitud individual, la serie resultante o el error).
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        batch body BatchRequest true "Modo y operaciones del lote"
+// @Success      200 {object} BatchResponse "Resultado del lote y de cada operación"
+// @Failure      400 {object} ErrorResponse "JSON mal formado, modo desconocido o lote vacío"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      413 {object} ErrorResponse "El lote supera las 500 operaciones"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al ejecutar el lote"
+// @Router       /series/batch [post]
+func (h *SeriesHandler) BatchSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = batchAtomic
+	}
+	if req.Mode != batchAtomic && req.Mode != batchBestEffort {
+		writeError(w, http.StatusBadRequest, "Modo desconocido: se admite '"+batchAtomic+"' o '"+batchBestEffort+"'")
+		return
+	}
+	if len(req.Operations) == 0 {
+		writeError(w, http.StatusBadRequest, "El lote no tiene operaciones")
+		return
+	}
+	if len(req.Operations) > maxBatchOperations {
+		writeError(w, http.StatusRequestEntityTooLarge, "El lote supera el máximo de "+strconv.Itoa(maxBatchOperations)+" operaciones")
+		return
+	}
+
+	resp := BatchResponse{Mode: req.Mode, Results: make([]BatchResult, len(req.Operations))}
+	valid := true
+	for i, op := range req.Operations {
+		resp.Results[i] = BatchResult{Index: i, Op: op.Op, ID: op.ID}
+		if errs := validateBatchOperation(&req.Operations[i]); len(errs) > 0 {
+			resp.Results[i].fail(http.StatusUnprocessableEntity, validationErrorResponse(errs))
+			valid = false
+		}
+	}
+
+	atomic := req.Mode == batchAtomic
+	err := errBatchAborted
+	if valid || !atomic {
+		err = h.repo.Transaction(r.Context(), func(tx repository.SeriesRepository) error {
+			for i, op := range req.Operations {
+				result := &resp.Results[i]
+				if result.Error != nil {
+					continue
+				}
+				apply := func(repo repository.SeriesRepository) error {
+					return applyBatchOperation(r.Context(), repo, userID, op, result)
+				}
+				// En modo bestEffort cada operación va en una transacción anidada
+				// (SAVEPOINT) para poder descartarla sin afectar a las demás
+				var err error
+				if atomic {
+					err = apply(tx)
+				} else {
+					err = tx.Transaction(r.Context(), apply)
+				}
+				if err != nil {
+					result.Series = nil
+					result.fail(repositoryErrorResponse(err, "Serie no encontrada", "Error ejecutando la operación: "))
+					if atomic {
+						return errBatchAborted
+					}
+				}
+			}
+			return nil
+		})
+	}
+
+	switch {
+	case err == nil:
+		resp.Committed = true
+	case errors.Is(err, errBatchAborted):
+		// Nada se guardó: las operaciones sin error no se aplicaron
+		for i := range resp.Results {
+			if result := &resp.Results[i]; result.Error == nil {
+				result.Series = nil
+				result.fail(http.StatusFailedDependency, ErrorResponse{Message: "No se aplicó porque otra operación del lote falló"})
+			}
+		}
+	default:
+		writeError(w, http.StatusInternalServerError, "Error ejecutando el lote: "+err.Error())
+		return
+	}
+
+	for _, result := range resp.Results {
+		switch {
+		case result.Error == nil:
+			resp.Succeeded++
+		case result.Status != http.StatusFailedDependency:
+			resp.Failed++
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// fail marca el resultado como fallido con el código y el error indicados.
+func (res *BatchResult) fail(statusCode int, body ErrorResponse) {
+	res.Status, res.Error = statusCode, &body
+}
+
+// validateBatchOperation valida y normaliza op antes de ejecutar el lote, con
+// las mismas reglas que la solicitud individual equivalente.
+func validateBatchOperation(op *BatchOperation) []FieldError {
+	var v validator
+	switch op.Op {
+	case batchCreate:
+		if op.Series == nil {
+			v.add("series", "es obligatorio en 'create'")
+			break
+		}
+		return validateSeries(op.Series, true)
+	case batchUpdate:
+		v.check(op.ID > 0, "id", "es obligatorio en 'update'")
+		if op.Series == nil {
+			v.add("series", "es obligatorio en 'update'")
+			break
+		}
+		v.errors = append(v.errors, validateSeries(op.Series, false)...)
+	case batchDelete, batchEpisode:
+		v.check(op.ID > 0, "id", fmt.Sprintf("es obligatorio en '%s'", op.Op))
+	case batchStatus:
+		v.check(op.ID > 0, "id", "es obligatorio en 'status'")
+		v.errors = append(v.errors, validateStatusUpdate(models.StatusUpdate{Status: op.Status})...)
+	default:
+		v.add("op", "debe ser 'create', 'update', 'delete', 'status' o 'episode'")
+	}
+	return v.errors
+}
+
+// applyBatchOperation ejecuta op (ya validada) con repo y completa result.
+func applyBatchOperation(ctx context.Context, repo repository.SeriesRepository, userID int, op BatchOperation, result *BatchResult) error {
+	if op.Version != nil {
+		ctx = repository.WithIfMatch(ctx, *op.Version)
+	}
+
+	var serie models.Series
+	var err error
+	switch op.Op {
+	case batchCreate:
+		serie = *op.Series
+		serie.ApplyProgressRules()
+		serie.ID = 0
+		serie.UserID = userID
+		if err := repo.Create(ctx, &serie); err != nil {
+			return err
+		}
+		result.ID, result.Status, result.Series = serie.ID, http.StatusCreated, &serie
+		return nil
+	case batchUpdate:
+		serie = *op.Series
+		serie.ID = op.ID
+		serie.UserID = userID
+		err = repo.Update(ctx, &serie)
+	case batchDelete:
+		if err := repo.Delete(ctx, userID, op.ID); err != nil {
+			return err
+		}
+		result.Status = http.StatusNoContent
+		return nil
+	case batchStatus:
+		serie, err = repo.UpdateStatus(ctx, userID, op.ID, op.Status)
+	case batchEpisode:
+		if op.Episode != nil {
+			serie, err = repo.SetEpisode(ctx, userID, op.ID, *op.Episode)
+		} else {
+			serie, err = repo.IncrementEpisode(ctx, userID, op.ID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	result.Status, result.Series = http.StatusOK, &serie
+	return nil
+}
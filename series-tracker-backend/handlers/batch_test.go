@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+
+	"lab6/migrations"
+	"lab6/models"
+	"lab6/repository"
+)
+
+// batchFixture crea las series sobre las que operan los lotes de prueba y
+// devuelve el cuerpo de un lote cuya tercera operación falla (serie
+// inexistente), seguido de las operaciones extra.
+func batchFixture(t *testing.T, repo repository.SeriesRepository, mode string, extra ...string) (watching, other models.Series, body string) {
+	t.Helper()
+	watching = createSeries(t, repo, testUserID, models.Series{Title: "Mushishi", Status: models.StatusWatching, LastEpisodeWatched: 3, TotalEpisodes: 26})
+	other = createSeries(t, repo, testUserID, models.Series{Title: "Trigun", TotalEpisodes: 26})
+	body = fmt.Sprintf(`{"mode": %q, "operations": [
+		{"op": "create", "series": {"title": "Frieren", "totalEpisodes": 28}},
+		{"op": "status", "id": %d, "status": "Completed"},
+		{"op": "episode", "id": 999},
+		{"op": "delete", "id": %d}%s
+	]}`, mode, watching.ID, other.ID, strings.Join(append([]string{""}, extra...), ",\n\t\t"))
+	return watching, other, body
+}
+
+// checkBatchStatuses compara el código de cada resultado del lote.
+func checkBatchStatuses(t *testing.T, results []BatchResult, want ...int) {
+	t.Helper()
+	if len(results) != len(want) {
+		t.Fatalf("se obtuvieron %d resultados, se esperaban %d", len(results), len(want))
+	}
+	for i, status := range want {
+		if result := results[i]; result.Index != i || result.Status != status {
+			t.Errorf("resultado %d = %+v, se esperaba el código %d", i, result, status)
+		}
+	}
+}
+
+func TestBatchSeriesAtomic(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	watching, other, body := batchFixture(t, repo, batchAtomic)
+
+	rec := do(t, router, http.MethodPost, "/series/batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var resp BatchResponse
+	decode(t, rec, &resp)
+	if resp.Mode != batchAtomic || resp.Committed || resp.Succeeded != 0 || resp.Failed != 1 {
+		t.Errorf("resumen inesperado: %+v", resp)
+	}
+	// Las operaciones anteriores y posteriores a la fallida quedan sin aplicar
+	checkBatchStatuses(t, resp.Results, http.StatusFailedDependency, http.StatusFailedDependency, http.StatusNotFound, http.StatusFailedDependency)
+	for i, result := range resp.Results {
+		if result.Series != nil || result.Error == nil {
+			t.Errorf("resultado %d = %+v, se esperaba un error sin serie", i, result)
+		}
+	}
+
+	ctx := context.Background()
+	all, _, err := repo.List(ctx, testUserID, models.SeriesQuery{})
+	if err != nil {
+		t.Fatalf("listando las series: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("hay %d series, se esperaban 2 (ni la creada ni la eliminada deben aplicarse)", len(all))
+	}
+	if got, err := repo.Get(ctx, testUserID, watching.ID); err != nil || got.Status != models.StatusWatching || got.Version != watching.Version {
+		t.Errorf("la serie %d cambió: %+v, %v", watching.ID, got, err)
+	}
+	if _, err := repo.Get(ctx, testUserID, other.ID); err != nil {
+		t.Errorf("la serie %d se eliminó: %v", other.ID, err)
+	}
+}
+
+func TestBatchSeriesAtomicValidation(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	watching := createSeries(t, repo, testUserID, models.Series{Title: "Mushishi", Status: models.StatusWatching})
+
+	// Los datos inválidos se detectan antes de ejecutar ninguna operación
+	body := fmt.Sprintf(`{"operations": [
+		{"op": "status", "id": %d, "status": "Completed"},
+		{"op": "create", "series": {"title": ""}},
+		{"op": "rename", "id": %d}
+	]}`, watching.ID, watching.ID)
+	rec := do(t, router, http.MethodPost, "/series/batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var resp BatchResponse
+	decode(t, rec, &resp)
+	if resp.Committed || resp.Failed != 2 {
+		t.Errorf("resumen inesperado: %+v", resp)
+	}
+	checkBatchStatuses(t, resp.Results, http.StatusFailedDependency, http.StatusUnprocessableEntity, http.StatusUnprocessableEntity)
+	if got, _ := repo.Get(context.Background(), testUserID, watching.ID); got.Status != models.StatusWatching {
+		t.Errorf("estado = %q, el lote no debía aplicarse", got.Status)
+	}
+
+	for _, body := range []string{`{"mode": "todo", "operations": [{"op": "delete", "id": 1}]}`, `{"operations": []}`, `{`} {
+		if rec := do(t, router, http.MethodPost, "/series/batch", body); rec.Code != http.StatusBadRequest {
+			t.Errorf("lote %s: status = %d, se esperaba 400", body, rec.Code)
+		}
+	}
+}
+
+func TestBatchSeriesBestEffort(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	testBatchBestEffort(t, repo, router)
+}
+
+// TestBatchSeriesBestEffortSQLite ejecuta el lote bestEffort sobre GORM, donde
+// cada operación va en un SAVEPOINT. La creación de "Falla" inserta la fila y
+// luego falla: solo el SAVEPOINT la deshace, sin afectar a las demás operaciones
+// que se confirman con la transacción exterior.
+func TestBatchSeriesBestEffortSQLite(t *testing.T) {
+	db := newSQLiteDB(t)
+	err := db.Callback().Create().After("gorm:create").Register("test:fallo_tras_insertar", func(tx *gorm.DB) {
+		if s, ok := tx.Statement.Dest.(*models.Series); ok && s.Title == "Falla" && tx.RowsAffected > 0 {
+			tx.AddError(errors.New("fallo simulado tras insertar"))
+		}
+	})
+	if err != nil {
+		t.Fatalf("registrando el callback: %v", err)
+	}
+	user := models.User{Username: "ana", PasswordHash: "x"}
+	if err := repository.NewGormUserRepository(db).CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("creando el usuario: %v", err)
+	}
+	if user.ID != testUserID {
+		t.Fatalf("el usuario tiene el ID %d, se esperaba %d", user.ID, testUserID)
+	}
+	repo := repository.NewGormSeriesRepository(db)
+	resp := testBatchBestEffort(t, repo, newTestRouter(repo),
+		`{"op": "create", "series": {"title": "Falla"}}`)
+	if result := resp.Results[4]; result.Status != http.StatusInternalServerError {
+		t.Errorf("resultado 4 = %+v, se esperaba 500", result)
+	}
+
+	var count int64
+	if err := db.Model(&models.Series{}).Where("title = ?", "Falla").Count(&count).Error; err != nil {
+		t.Fatalf("contando las series: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("hay %d series 'Falla': el SAVEPOINT debía deshacer la inserción", count)
+	}
+}
+
+// testBatchBestEffort ejecuta en modo bestEffort el lote de batchFixture más
+// las operaciones extra y comprueba que se aplican todas las que no fallan.
+func testBatchBestEffort(t *testing.T, repo repository.SeriesRepository, router http.Handler, extra ...string) BatchResponse {
+	t.Helper()
+	watching, other, body := batchFixture(t, repo, batchBestEffort, extra...)
+
+	rec := do(t, router, http.MethodPost, "/series/batch", body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var resp BatchResponse
+	decode(t, rec, &resp)
+	if resp.Mode != batchBestEffort || !resp.Committed || resp.Succeeded != 3 || resp.Failed != 1+len(extra) {
+		t.Errorf("resumen inesperado: %+v", resp)
+	}
+	if len(resp.Results) != 4+len(extra) {
+		t.Fatalf("se obtuvieron %d resultados, se esperaban %d", len(resp.Results), 4+len(extra))
+	}
+	checkBatchStatuses(t, resp.Results[:4], http.StatusCreated, http.StatusOK, http.StatusNotFound, http.StatusNoContent)
+
+	ctx := context.Background()
+	created := resp.Results[0]
+	if got, err := repo.Get(ctx, testUserID, created.ID); err != nil || got.Title != "Frieren" {
+		t.Errorf("la serie creada = %+v, %v", got, err)
+	}
+	if got, err := repo.Get(ctx, testUserID, watching.ID); err != nil || got.Status != models.StatusCompleted {
+		t.Errorf("la serie %d = %+v, %v; se esperaba 'Completed'", watching.ID, got, err)
+	}
+	if _, err := repo.Get(ctx, testUserID, other.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("la serie %d sigue disponible (%v), se esperaba eliminada", other.ID, err)
+	}
+	return resp
+}
+
+// newSQLiteDB abre una base SQLite temporal con las migraciones aplicadas.
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+		filepath.Join(t.TempDir(), "test.db"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("abriendo SQLite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("obteniendo la conexión: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatalf("cargando migraciones: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("aplicando migraciones: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"lab6/covers"
+	"lab6/storage"
+)
+
+// coverField es el nombre del campo multipart con la imagen de portada.
+const coverField = "cover"
+
+// coverCacheControl permite cachear las imágenes indefinidamente: cada subida
+// usa un token nuevo, por lo que el contenido de una ruta nunca cambia.
+const coverCacheControl = "public, max-age=31536000, immutable"
+
+// UploadSeriesCover godoc
+// @Summary      Subir la portada
+// @Description  Recibe una imagen JPEG, PNG, GIF o WebP (hasta 5 MiB y 10000 píxeles por lado) en el campo multipart 'cover', genera las miniaturas 'medium' (480 px de ancho) y 'small' (160 px) en JPEG y la guarda como portada de la serie. 'coverUrl' pasa a ser la ruta de la imagen original (/api/covers/{token}/original.{ext}); las miniaturas están en la misma ruta con medium.jpg y small.jpg. La portada anterior se elimina.
+// @Tags         Covers
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Param        cover formData file true "Imagen de portada"
+// @Success      200 {object} models.Series "Portada guardada, devuelve la serie completa"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "ID inválido o cuerpo sin el campo 'cover'"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      413 {object} ErrorResponse "La imagen supera los 5 MiB"
+// @Failure      415 {object} ErrorResponse "El archivo no es una imagen JPEG, PNG, GIF o WebP"
+// @Failure      422 {object} ErrorResponse "Dimensiones de la imagen fuera de los límites"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al guardar la portada"
+// @Router       /series/{id}/cover [post]
+func (h *SeriesHandler) UploadSeriesCover(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	data, ok := readCoverUpload(w, r)
+	if !ok {
+		return
+	}
+	images, err := covers.Process(data)
+	if errors.Is(err, covers.ErrUnsupportedType) {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	if err != nil {
+		writeValidationError(w, []FieldError{{Field: coverField, Message: err.Error()}})
+		return
+	}
+
+	// Comprobar la serie antes de guardar las imágenes para no dejar blobs huérfanos en el caso habitual
+	if _, err := h.repo.Get(r.Context(), userID, id); err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error obteniendo la serie: ")
+		return
+	}
+
+	token, err := covers.NewToken()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error generando el token de la portada: "+err.Error())
+		return
+	}
+	for _, img := range images {
+		if err := h.blobs.Put(r.Context(), covers.Key(token, img.File()), img.Data, img.ContentType); err != nil {
+			h.deleteCover(r.Context(), token)
+			writeError(w, http.StatusInternalServerError, "Error guardando la portada: "+err.Error())
+			return
+		}
+	}
+
+	serie, previous, err := h.repo.SetCover(r.Context(), userID, id, token, covers.URL(token, images[0].File()))
+	if err != nil {
+		h.deleteCover(r.Context(), token)
+		writeRepositoryError(w, err, "Serie no encontrada", "Error guardando la portada: ")
+		return
+	}
+	h.deleteCover(r.Context(), previous)
+
+	writeSeries(w, http.StatusOK, serie)
+}
+
+// readCoverUpload lee el contenido del campo multipart 'cover' sin guardarlo en
+// disco. Si el cuerpo no es válido o supera covers.MaxBytes escribe el error
+// correspondiente (400 o 413) y devuelve false.
+func readCoverUpload(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	// Margen para los encabezados multipart y otros campos
+	r.Body = http.MaxBytesReader(w, r.Body, covers.MaxBytes+64<<10)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Se esperaba un cuerpo multipart/form-data con el campo '"+coverField+"'")
+		return nil, false
+	}
+
+	tooLarge := "La imagen supera el máximo de " + strconv.Itoa(covers.MaxBytes>>20) + " MiB"
+	for {
+		part, err := reader.NextPart()
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, io.EOF):
+			writeError(w, http.StatusBadRequest, "Falta el campo '"+coverField+"' con la imagen")
+			return nil, false
+		case errors.As(err, &maxBytesErr):
+			writeError(w, http.StatusRequestEntityTooLarge, tooLarge)
+			return nil, false
+		case err != nil:
+			writeError(w, http.StatusBadRequest, "Cuerpo multipart inválido: "+err.Error())
+			return nil, false
+		}
+		if part.FormName() != coverField {
+			part.Close()
+			continue
+		}
+
+		data, err := io.ReadAll(io.LimitReader(part, covers.MaxBytes+1))
+		part.Close()
+		switch {
+		case errors.As(err, &maxBytesErr) || len(data) > covers.MaxBytes:
+			writeError(w, http.StatusRequestEntityTooLarge, tooLarge)
+			return nil, false
+		case err != nil:
+			writeError(w, http.StatusBadRequest, "Error leyendo la imagen: "+err.Error())
+			return nil, false
+		case len(data) == 0:
+			writeError(w, http.StatusBadRequest, "El campo '"+coverField+"' está vacío")
+			return nil, false
+		}
+		return data, true
+	}
+}
+
+// DeleteSeriesCover godoc
+// @Summary      Quitar la portada
+// @Description  Quita la portada de la serie: vacía 'coverUrl' (sea una imagen subida o una URL externa) y elimina las imágenes subidas.
+// @Tags         Covers
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      200 {object} models.Series "Portada quitada, devuelve la serie completa"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al quitar la portada"
+// @Router       /series/{id}/cover [delete]
+func (h *SeriesHandler) DeleteSeriesCover(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	serie, previous, err := h.repo.SetCover(r.Context(), userID, id, "", "")
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error quitando la portada: ")
+		return
+	}
+	h.deleteCover(r.Context(), previous)
+
+	writeSeries(w, http.StatusOK, serie)
+}
+
+// GetCoverImage godoc
+// @Summary      Obtener una imagen de portada
+// @Description  Devuelve la imagen original o una miniatura de una portada subida. Es pública (no requiere autenticación) porque el token es aleatorio e imposible de adivinar, y se puede cachear indefinidamente: cada subida usa un token nuevo.
+// @Tags         Covers
+// @Produce      image/jpeg
+// @Produce      image/png
+// @Produce      image/gif
+// @Produce      image/webp
+// @Param        token path string true "Token de la portada (ver 'coverUrl')" example(3f2a9c0d8e7b6a5f4e3d2c1b0a9f8e7d)
+// @Param        file path string true "Archivo" Enums(original.jpg, original.png, original.gif, original.webp, medium.jpg, small.jpg)
+// @Param        If-None-Match header string false "ETag de la copia en caché; si coincide se responde 304"
+// @Success      200 {file} binary "Imagen"
+// @Success      304 "La copia en caché sigue vigente"
+// @Header       200 {string} ETag "Identificador de la imagen"
+// @Header       200 {string} Cache-Control "public, max-age=31536000, immutable"
+// @Failure      404 {object} ErrorResponse "Imagen no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al leer la imagen"
+// @Router       /covers/{token}/{file} [get]
+func (h *SeriesHandler) GetCoverImage(w http.ResponseWriter, r *http.Request) {
+	token, file := chi.URLParam(r, "token"), chi.URLParam(r, "file")
+	if !covers.ValidPath(token, file) {
+		writeError(w, http.StatusNotFound, "Imagen no encontrada")
+		return
+	}
+
+	etag := `"` + token + "-" + file + `"`
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", coverCacheControl)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	body, info, err := h.blobs.Get(r.Context(), covers.Key(token, file))
+	if errors.Is(err, storage.ErrNotFound) {
+		writeError(w, http.StatusNotFound, "Imagen no encontrada")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error leyendo la imagen: "+err.Error())
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", info.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", coverCacheControl)
+	if info.Size > 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if !info.ModTime.IsZero() {
+		w.Header().Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, body)
+}
+
+// etagMatches indica si la lista de ETags del header If-None-Match incluye etag o "*".
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteCover elimina las imágenes de la portada token (si no está vacío). Los
+// errores solo se registran: la portada ya no está referenciada y un blob
+// huérfano no afecta a la API. No se usa la cancelación de ctx para que la
+// limpieza termine aunque el cliente se desconecte.
+func (h *SeriesHandler) deleteCover(ctx context.Context, token string) {
+	deleteCoverBlobs(ctx, h.blobs, token)
+}
+
+// deleteCoverBlobs elimina de blobs las imágenes de la portada token (ver deleteCover).
+func deleteCoverBlobs(ctx context.Context, blobs storage.BlobStore, token string) {
+	if token == "" {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range covers.Keys(token) {
+		if err := blobs.Delete(ctx, key); err != nil {
+			log.Printf("Error eliminando la imagen de portada %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"lab6/models"
+)
+
+// DecrementSeriesEpisode godoc
+// @Summary      Decrementar episodio visto
+// @Description  Resta 1 al campo 'lastEpisodeWatched' de la serie identificada por ID (sin bajar de 0). Una serie 'Completed' vuelve a 'Watching'.
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie cuyo episodio se decrementará" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      200 {object} models.Series "Episodio decrementado, devuelve la serie actualizada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "El progreso resultante supera el total de episodios (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al decrementar el episodio"
+// @Router       /series/{id}/episode/decrement [patch]
+func (h *SeriesHandler) DecrementSeriesEpisode(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	serie, err := h.repo.DecrementEpisode(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error decrementando el episodio: ")
+		return
+	}
+
+	writeSeries(w, http.StatusOK, serie)
+}
+
+// RewatchSeries godoc
+// @Summary      Volver a ver una serie
+// @Description  Empieza a ver de nuevo una serie 'Completed': pasa a 'Watching' y 'lastEpisodeWatched' vuelve a 0. Queda en el historial y se puede deshacer.
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      200 {object} models.Series "Serie reiniciada, devuelve la serie actualizada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "La serie no está completada (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al reiniciar la serie"
+// @Router       /series/{id}/rewatch [post]
+func (h *SeriesHandler) RewatchSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	serie, err := h.repo.Rewatch(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error reiniciando la serie: ")
+		return
+	}
+
+	writeSeries(w, http.StatusOK, serie)
+}
+
+// SetSeriesEpisode godoc
+// @Summary      Ir a un episodio
+// @Description  Fija el último episodio visto. 'episode' es absoluto o, si se indica 'season', relativo a esa temporada (requiere 'seasonEpisodes'). Avanzar pasa la serie a 'Watching' (o 'Completed' al llegar al final) y retroceder una serie 'Completed' la devuelve a 'Watching'.
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Param        episode body models.EpisodeRef true "Episodio (absoluto o por temporada)"
+// @Success      200 {object} models.Series "Progreso actualizado, devuelve la serie actualizada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "Episodio o temporada fuera de rango (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar el progreso"
+// @Router       /series/{id}/episode [put]
+func (h *SeriesHandler) SetSeriesEpisode(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var ref models.EpisodeRef
+	if err := json.NewDecoder(r.Body).Decode(&ref); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+
+	serie, err := h.repo.SetEpisode(r.Context(), userID, id, ref)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error actualizando el progreso: ")
+		return
+	}
+
+	writeSeries(w, http.StatusOK, serie)
+}
+
+// WatchSeriesRange godoc
+// @Summary      Marcar un rango de episodios como vistos
+// @Description  Marca como vistos los episodios 'from'..'to' (inclusivo). Con 'season' los números son relativos a esa temporada y, si se omiten 'from' y 'to', se marca la temporada completa. El rango debe continuar el progreso actual (empezar como mucho en el episodio siguiente al último visto); los episodios ya vistos no cambian nada.
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Param        range body models.EpisodeRange true "Rango de episodios (absoluto o por temporada)"
+// @Success      200 {object} models.Series "Progreso actualizado, devuelve la serie actualizada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "Rango inválido o que deja episodios sin ver (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar el progreso"
+// @Router       /series/{id}/episode/range [post]
+func (h *SeriesHandler) WatchSeriesRange(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var rng models.EpisodeRange
+	if err := json.NewDecoder(r.Body).Decode(&rng); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+
+	serie, err := h.repo.WatchRange(r.Context(), userID, id, rng)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error actualizando el progreso: ")
+		return
+	}
+
+	writeSeries(w, http.StatusOK, serie)
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// seriesETag devuelve el ETag (fuerte) de una serie, derivado de su versión.
+func seriesETag(s models.Series) string {
+	return `"` + strconv.Itoa(s.Version) + `"`
+}
+
+// writeSeries escribe la serie como JSON junto con su header ETag.
+func writeSeries(w http.ResponseWriter, statusCode int, s models.Series) {
+	w.Header().Set("ETag", seriesETag(s))
+	writeJSON(w, statusCode, s)
+}
+
+// parseETagVersions extrae las versiones de una lista de ETags como la de los
+// headers If-Match o If-None-Match. Los ETags débiles (W/"...") o con otro
+// formato se ignoran, ya que nunca coinciden con los de una serie.
+func parseETagVersions(header string) []int {
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+			continue
+		}
+		if version, err := strconv.Atoi(tag[1 : len(tag)-1]); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// IfMatch es un middleware que traslada el header If-Match de las solicitudes
+// de modificación (POST, PUT, PATCH, DELETE) al contexto mediante
+// repository.WithIfMatch, de modo que el repositorio rechace la operación con
+// ErrPreconditionFailed (412) si la serie cambió desde que el cliente la obtuvo.
+// "If-Match: *" o la ausencia del header no imponen condiciones. Solo debe
+// montarse en las rutas que modifican una serie concreta (/series/{id}...) y
+// cuyo repositorio comprueba la versión; en las demás el header se ignoraría.
+func IfMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := strings.TrimSpace(r.Header.Get("If-Match"))
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			if header != "" && header != "*" {
+				ctx := repository.WithIfMatch(r.Context(), parseETagVersions(header)...)
+				r = r.WithContext(ctx)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
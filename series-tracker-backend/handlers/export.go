@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lab6/models"
+	"lab6/repository"
+	"lab6/transfer"
+)
+
+// exportWriteTimeout es el plazo de escritura que se concede por cada serie
+// exportada: la respuesta puede durar más que el WriteTimeout del servidor.
+const exportWriteTimeout = 10 * time.Second
+
+// ExportHandler agrupa los handlers de exportación de la lista.
+type ExportHandler struct {
+	export repository.ExportRepository
+}
+
+// NewExportHandler crea un ExportHandler que usa el repositorio indicado.
+func NewExportHandler(export repository.ExportRepository) *ExportHandler {
+	return &ExportHandler{export: export}
+}
+
+// ExportSeries godoc
+// @Summary      Exportar la lista completa
+// @Description  Descarga todas las series del usuario como archivo adjunto, escrito a medida que se leen (sin cargar la lista completa en memoria).
+// @Description  - **json** (por defecto): documento con cada serie y sus etiquetas, listas, reseña e historial completo. Es el formato recomendado como respaldo.
+// @Description  - **csv**: una fila por serie; temporadas, etiquetas y listas separadas con ';'; del historial solo la cantidad de eventos y la fecha del último.
+// @Description  - **malxml**: XML con el formato de la exportación de MyAnimeList (estado, progreso, puntuación redondeada, notas, etiquetas y fechas de inicio y fin deducidas del historial), que aceptan MyAnimeList, AniList y POST /api/import.
+// @Tags         Import/Export
+// @Produce      json
+// @Produce      text/csv
+// @Produce      xml
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        format query string false "Formato del archivo" Enums(json, csv, malxml) default(json)
+// @Success      200 {object} models.ExportDocument "Exportación (con format=json)"
+// @Header       200 {string} Content-Disposition "attachment; filename=\"series-AAAA-MM-DD.ext\""
+// @Failure      400 {object} ErrorResponse "Formato desconocido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al leer las series"
+// @Router       /export [get]
+func (h *ExportHandler) ExportSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	name := strings.ToLower(r.URL.Query().Get("format"))
+	if name == "" {
+		name = transfer.FormatJSON
+	}
+	format, ok := transfer.ExportFormats[name]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Formato desconocido: se admite 'json', 'csv' o 'malxml'")
+		return
+	}
+
+	// El encabezado se escribe con la primera serie, de modo que un error en la
+	// primera consulta todavía puede responderse con un 500.
+	exportedAt := time.Now().UTC()
+	controller := http.NewResponseController(w)
+	var writer transfer.Writer
+	begin := func() error {
+		w.Header().Set("Content-Type", format.ContentType)
+		w.Header().Set("Content-Disposition", `attachment; filename="series-`+exportedAt.Format(time.DateOnly)+format.Ext+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		writer = format.NewWriter(w, exportedAt)
+		return writer.Begin()
+	}
+
+	err := h.export.ExportSeries(r.Context(), userID, func(s models.SeriesExport) error {
+		// Puede fallar si el ResponseWriter no lo admite; entonces rige el timeout del servidor
+		controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		if writer == nil {
+			if err := begin(); err != nil {
+				return err
+			}
+		}
+		return writer.Write(s)
+	})
+	if err == nil && writer == nil {
+		err = begin()
+	}
+	if err == nil {
+		err = writer.End()
+	}
+	if err != nil {
+		if writer == nil {
+			writeError(w, http.StatusInternalServerError, "Error exportando las series: "+err.Error())
+			return
+		}
+		// La respuesta ya empezó: el cliente recibe un archivo incompleto
+		log.Printf("Error exportando las series del usuario %d: %v", userID, err)
+	}
+}
@@ -4,24 +4,28 @@ package handlers
 
 import (
 	"encoding/json"
-	"errors" // Para usar gorm.ErrRecordNotFound
+	"errors" // Para comparar con repository.ErrNotFound
 	"net/http"
+	"slices"
 	"strconv" // Para convertir ID de string a int
 
 	"github.com/go-chi/chi/v5"
-	"gorm.io/gorm" // Para gorm.Expr y gorm.ErrRecordNotFound
 
 	"lab6/models"     // Asegúrate que la ruta de importación sea correcta
 	"lab6/repository" // Asegúrate que la ruta de importación sea correcta
+	"lab6/storage"
 )
 
 // ErrorResponse define una estructura estándar para respuestas de error JSON.
 // Se utiliza para dar formato consistente a los errores de la API en las respuestas HTTP.
-// @Description Estructura estándar para errores de la API con un mensaje descriptivo.
+// @Description Estructura estándar para errores de la API con un mensaje descriptivo y, en los errores de validación, el detalle por campo.
 type ErrorResponse struct {
 	// Message contiene el mensaje descriptivo del error ocurrido.
 	// example: "Serie no encontrada"
 	Message string `json:"message"`
+
+	// Errors detalla los campos inválidos (solo en errores de validación).
+	Errors []FieldError `json:"errors,omitempty"`
 }
 
 // writeError es una función helper para escribir errores JSON estandarizados.
@@ -31,26 +35,130 @@ func writeError(w http.ResponseWriter, statusCode int, message string) {
 	json.NewEncoder(w).Encode(ErrorResponse{Message: message})
 }
 
+// writeJSON es una función helper para escribir respuestas JSON exitosas.
+func writeJSON(w http.ResponseWriter, statusCode int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeRepositoryError traduce un error del repositorio a la respuesta HTTP adecuada:
+// 404 con notFoundMessage si la serie no existe, 409 si no hay cambios que
+// deshacer, 412 si no se cumple If-Match, 422 si el cambio de estado o de progreso no es válido, o 500 con el prefijo indicado en otro caso.
+func writeRepositoryError(w http.ResponseWriter, err error, notFoundMessage, prefix string) {
+	statusCode, body := repositoryErrorResponse(err, notFoundMessage, prefix)
+	writeJSON(w, statusCode, body)
+}
+
+// repositoryErrorResponse devuelve el código HTTP y el cuerpo de error que
+// corresponden a un error del repositorio (ver writeRepositoryError).
+func repositoryErrorResponse(err error, notFoundMessage, prefix string) (int, ErrorResponse) {
+	if errors.Is(err, repository.ErrNotFound) {
+		return http.StatusNotFound, ErrorResponse{Message: notFoundMessage}
+	}
+	if errors.Is(err, repository.ErrNoReview) {
+		return http.StatusNotFound, ErrorResponse{Message: "La serie no tiene reseña"}
+	}
+	if errors.Is(err, repository.ErrNoHistory) {
+		return http.StatusConflict, ErrorResponse{Message: "La serie no tiene cambios para deshacer"}
+	}
+	if errors.Is(err, repository.ErrPreconditionFailed) {
+		return http.StatusPreconditionFailed, ErrorResponse{Message: "La serie fue modificada por otra solicitud (If-Match no coincide): vuelva a obtenerla e intente de nuevo"}
+	}
+	var statusErr *models.StatusError
+	if errors.As(err, &statusErr) {
+		return http.StatusUnprocessableEntity, ErrorResponse{
+			Message: "Cambio de estado inválido: " + statusErr.Error(),
+			Errors:  []FieldError{{Field: "status", Message: statusErr.Error()}},
+		}
+	}
+	var progressErr *models.ProgressError
+	if errors.As(err, &progressErr) {
+		return http.StatusUnprocessableEntity, validationErrorResponse([]FieldError{{Field: progressErr.Field, Message: progressErr.Message}})
+	}
+	return http.StatusInternalServerError, ErrorResponse{Message: prefix + err.Error()}
+}
+
+// seriesID lee y valida el parámetro {id} de la URL.
+// Si no es un número escribe un 400 y devuelve false.
+func seriesID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	return pathID(w, r, "id")
+}
+
+// pathID lee y valida el parámetro numérico name de la URL.
+// Si no es un número escribe un 400 y devuelve false.
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	idStr := chi.URLParam(r, name)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+		return 0, false
+	}
+	return id, true
+}
+
+// SeriesHandler agrupa los handlers HTTP del recurso 'series'.
+// Recibe el repositorio por constructor, de modo que puede usarse con cualquier
+// implementación de repository.SeriesRepository (GORM, memoria, etc.).
+// Las imágenes de portada se guardan en blobs (sistema de archivos, S3, memoria).
+type SeriesHandler struct {
+	repo  repository.SeriesRepository
+	blobs storage.BlobStore
+}
+
+// NewSeriesHandler crea un SeriesHandler que usa el repositorio y el almacenamiento de portadas indicados.
+func NewSeriesHandler(repo repository.SeriesRepository, blobs storage.BlobStore) *SeriesHandler {
+	return &SeriesHandler{repo: repo, blobs: blobs}
+}
+
 // --- Handlers ---
 
 // GetAllSeries godoc
-// @Summary      Listar todas las series
-// @Description  Obtiene una lista completa de todas las series almacenadas en la base de datos.
+// @Summary      Listar series
+// @Description  Obtiene una página de las series del usuario autenticado aplicando filtros, ordenamiento y paginación en el servidor. El total de resultados se devuelve en el header X-Total-Count y los enlaces de navegación en el header Link.
 // @Tags         Series
 // @Accept       json
 // @Produce      json
-// @Success      200 {array}  models.Series "Lista de series recuperada exitosamente"
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        search     query string false "Texto a buscar en el título (coincidencia parcial, sin distinguir mayúsculas)"
+// @Param        status     query string false "Filtrar por estado exacto" Enums(Plan to Watch, Watching, On Hold, Completed, Dropped)
+// @Param        minRanking query int    false "Ranking mínimo (inclusivo)"
+// @Param        maxRanking query int    false "Ranking máximo (inclusivo)"
+// @Param        tag        query []int  false "ID de etiqueta; puede repetirse y se devuelven las series con todas las etiquetas" collectionFormat(multi)
+// @Param        list       query int    false "ID de lista personalizada"
+// @Param        sortBy     query string false "Campo de ordenamiento (por defecto 'id', o 'ranking' si solo se indica 'sort'; con 'score' las series sin puntuar van al final)" Enums(id, title, status, lastEpisodeWatched, totalEpisodes, ranking, score)
+// @Param        sort       query string false "Dirección del ordenamiento" Enums(asc, desc)
+// @Param        page       query int    false "Número de página (empieza en 1)" default(1)
+// @Param        pageSize   query int    false "Cantidad de series por página (máximo 500)" default(100)
+// @Success      200 {array}  models.Series "Página de series recuperada exitosamente"
+// @Header       200 {integer} X-Total-Count "Número total de series que cumplen los filtros"
+// @Header       200 {string}  Link "Enlaces first, prev, next y last (RFC 8288)"
+// @Failure      400 {object} ErrorResponse "Query params inválidos"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al buscar series"
 // @Router       /series [get]
-func GetAllSeries(w http.ResponseWriter, r *http.Request) {
-	var series []models.Series
-	if err := repository.DB.Find(&series).Error; err != nil {
+func (h *SeriesHandler) GetAllSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	query, err := parseSeriesQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: "+err.Error())
+		return
+	}
+
+	series, total, err := h.repo.List(r.Context(), userID, query)
+	if err != nil {
 		writeError(w, http.StatusInternalServerError, "Error buscando series: "+err.Error())
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK) // Explícito OK
-	json.NewEncoder(w).Encode(series)
+
+	setPaginationHeaders(w, r, query.Page, query.PageSize, total)
+	writeJSON(w, http.StatusOK, series)
 }
 
 // GetSeriesByID godoc
@@ -59,49 +167,69 @@ func GetAllSeries(w http.ResponseWriter, r *http.Request) {
 // @Tags         Series
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie a buscar" Format(int64) example(1)
+// @Param        If-None-Match header string false "ETag conocido por el cliente; si coincide con la versión actual se responde 304"
 // @Success      200 {object} models.Series "Detalles de la serie encontrados"
+// @Header       200 {string} ETag "Versión actual de la serie"
+// @Success      304 "La serie no cambió desde el ETag indicado"
 // @Failure      400 {object} ErrorResponse "ID proporcionado inválido (no es un número)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada con el ID proporcionado"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al buscar la serie"
 // @Router       /series/{id} [get]
-func GetSeriesByID(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+func (h *SeriesHandler) GetSeriesByID(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
-	var serie models.Series
-	result := repository.DB.First(&serie, id)
+	serie, err := h.repo.Get(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error buscando la serie: ")
+		return
+	}
 
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			writeError(w, http.StatusNotFound, "Serie no encontrada")
-		} else {
-			writeError(w, http.StatusInternalServerError, "Error buscando la serie: "+result.Error.Error())
-		}
+	// Si el cliente ya tiene la versión actual no hace falta reenviarla
+	if slices.Contains(parseETagVersions(r.Header.Get("If-None-Match")), serie.Version) {
+		w.Header().Set("ETag", seriesETag(serie))
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(serie)
+	writeSeries(w, http.StatusOK, serie)
 }
 
 // CreateSeries godoc
 // @Summary      Crear una nueva serie
-// @Description  Añade una nueva serie a la base de datos utilizando los datos proporcionados en el cuerpo de la solicitud. El ID es auto-generado por la base de datos.
+// @Description  Añade una nueva serie a la base de datos utilizando los datos proporcionados en el cuerpo de la solicitud. El ID es auto-generado por la base de datos. Si se omite el estado se usa 'Plan to Watch'; una serie cuyo último episodio visto alcanza el total se guarda como 'Completed'.
 // @Tags         Series
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        series body models.Series true "Datos de la nueva serie a crear (el campo ID será ignorado)"
 // @Success      201 {object} models.Series "Serie creada exitosamente (devuelve el objeto completo con el nuevo ID)"
-// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, falta título)"
+// @Header       201 {string} ETag "Versión de la serie creada"
+// @Failure      400 {object} ErrorResponse "JSON mal formado"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      422 {object} ErrorResponse "Datos inválidos (detalle por campo en 'errors')"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al guardar la serie"
 // @Router       /series [post]
-func CreateSeries(w http.ResponseWriter, r *http.Request) {
+func (h *SeriesHandler) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
 	var newSeries models.Series
 	// Decodificar el cuerpo de la solicitud en la estructura newSeries
 	if err := json.NewDecoder(r.Body).Decode(&newSeries); err != nil {
@@ -109,54 +237,52 @@ func CreateSeries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validación básica (GORM podría manejar 'not null', pero una validación explícita es buena)
-	if newSeries.Title == "" {
-		writeError(w, http.StatusBadRequest, "El campo 'title' es obligatorio")
+	if errs := validateSeries(&newSeries, true); len(errs) > 0 {
+		writeValidationError(w, errs)
 		return
 	}
+	newSeries.ApplyProgressRules()
 
-	// Crear el registro en la base de datos
-	// GORM asignará el ID automáticamente si la creación es exitosa.
-	if err := repository.DB.Create(&newSeries).Error; err != nil {
+	// El ID siempre lo asigna el repositorio y el dueño es el usuario autenticado
+	newSeries.ID = 0
+	newSeries.UserID = userID
+	if err := h.repo.Create(r.Context(), &newSeries); err != nil {
 		writeError(w, http.StatusInternalServerError, "Error creando la serie: "+err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated) // 201 Created
-	json.NewEncoder(w).Encode(newSeries) // Devolver el objeto creado con su ID
+	writeSeries(w, http.StatusCreated, newSeries) // Devolver el objeto creado con su ID
 }
 
 // UpdateSeries godoc
 // @Summary      Actualizar una serie existente
-// @Description  Actualiza todos los campos de una serie existente identificada por su ID, utilizando los datos proporcionados en el cuerpo de la solicitud.
+// @Description  Actualiza todos los campos de una serie existente identificada por su ID, utilizando los datos proporcionados en el cuerpo de la solicitud. El cambio de estado debe ser una transición permitida (ver PATCH /series/{id}/status) y, si el último episodio visto alcanza el total, la serie pasa a 'Completed'.
 // @Tags         Series
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie a actualizar" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
 // @Param        series body models.Series true "Nuevos datos completos para la serie (se usará el ID de la URL, no el del cuerpo si existe)"
 // @Success      200 {object} models.Series "Serie actualizada exitosamente"
+// @Header       200 {string} ETag "Nueva versión de la serie"
 // @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido en URL)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada con el ID proporcionado"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "Datos inválidos o transición de estado no permitida (detalle por campo en 'errors')"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar la serie"
 // @Router       /series/{id} [put]
-func UpdateSeries(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+func (h *SeriesHandler) UpdateSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	// Verificar primero si la serie existe
-	var serie models.Series
-	result := repository.DB.First(&serie, id)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			writeError(w, http.StatusNotFound, "Serie no encontrada para actualizar")
-		} else {
-			writeError(w, http.StatusInternalServerError, "Error buscando la serie: "+result.Error.Error())
-		}
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
@@ -167,59 +293,54 @@ func UpdateSeries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Actualizar los campos del objeto 'serie' existente con los 'updatedData'
-	// Es importante usar el ID de la URL, no el del cuerpo (si lo tuviera)
-	serie.Title = updatedData.Title
-	serie.Status = updatedData.Status
-	serie.LastEpisodeWatched = updatedData.LastEpisodeWatched
-	serie.TotalEpisodes = updatedData.TotalEpisodes
-	serie.Ranking = updatedData.Ranking
+	if errs := validateSeries(&updatedData, false); len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
 
-	// Guardar los cambios en la base de datos
-	if err := repository.DB.Save(&serie).Error; err != nil {
-		writeError(w, http.StatusInternalServerError, "Error actualizando la serie: "+err.Error())
+	// Es importante usar el ID de la URL, no el del cuerpo (si lo tuviera)
+	updatedData.ID = id
+	updatedData.UserID = userID
+	if err := h.repo.Update(r.Context(), &updatedData); err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada para actualizar", "Error actualizando la serie: ")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(serie) // Devolver el objeto actualizado
+	writeSeries(w, http.StatusOK, updatedData) // Devolver el objeto actualizado
 }
 
 // DeleteSeries godoc
 // @Summary      Eliminar una serie
-// @Description  Elimina permanentemente una serie de la base de datos utilizando su ID.
+// @Description  Mueve la serie a la papelera: deja de aparecer en el resto de la API, pero conserva su historial, reseña, votos, etiquetas y listas, y puede restaurarse con POST /series/{id}/restore hasta que se purga (por defecto, 30 días después).
 // @Tags         Series
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie a eliminar" example(1)
-// @Success      204 "Sin contenido (eliminado exitosamente)"
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      204 "Sin contenido (movida a la papelera)"
 // @Failure      400 {object} ErrorResponse "ID proporcionado inválido (no es un número)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada para eliminar"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al eliminar la serie"
 // @Router       /series/{id} [delete]
-func DeleteSeries(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+func (h *SeriesHandler) DeleteSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	// Intentar eliminar la serie directamente por ID
-	// Usamos un modelo vacío para que GORM sepa en qué tabla buscar
-	result := repository.DB.Delete(&models.Series{}, id)
-
-	if result.Error != nil {
-		// GORM no devuelve ErrRecordNotFound en Delete si no afecta filas,
-		// por eso verificamos RowsAffected después.
-		writeError(w, http.StatusInternalServerError, "Error eliminando la serie: "+result.Error.Error())
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
-	// Verificar si alguna fila fue realmente eliminada
-	if result.RowsAffected == 0 {
-		writeError(w, http.StatusNotFound, "Serie no encontrada para eliminar")
+	// La serie va a la papelera; su portada subida se elimina al purgarla
+	if err := h.repo.Delete(r.Context(), userID, id); err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada para eliminar", "Error eliminando la serie: ")
 		return
 	}
 
@@ -231,33 +352,33 @@ func DeleteSeries(w http.ResponseWriter, r *http.Request) {
 
 // UpdateSeriesStatus godoc
 // @Summary      Actualizar estado de una serie (parcial)
-// @Description  Actualiza únicamente el campo 'status' de una serie existente identificada por su ID.
+// @Description  Actualiza el campo 'status' de una serie existente identificada por su ID. Solo se permiten estas transiciones: 'Plan to Watch' → 'Watching', 'On Hold', 'Completed' o 'Dropped'; 'Watching' → 'On Hold', 'Completed' o 'Dropped'; 'On Hold' → 'Watching', 'Completed' o 'Dropped'; 'Completed' → 'Watching' (conserva el progreso; para empezar de nuevo ver POST /series/{id}/rewatch); 'Dropped' → 'Plan to Watch' o 'Watching'. Al marcarla como 'Completed' el último episodio visto pasa a ser el total de episodios (si se conoce).
 // @Tags         Series Actions
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie cuyo estado se actualizará" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
 // @Param        status body models.StatusUpdate true "Objeto JSON con el nuevo estado"
 // @Success      200 {object} models.Series "Estado actualizado, devuelve la serie completa"
-// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, falta status, ID inválido)"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "Falta el estado, es desconocido o la transición no está permitida (detalle por campo en 'errors')"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar el estado"
 // @Router       /series/{id}/status [patch]
-func UpdateSeriesStatus(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+func (h *SeriesHandler) UpdateSeriesStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	// Verificar si la serie existe
-	var serie models.Series
-	if err := repository.DB.First(&serie, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			writeError(w, http.StatusNotFound, "Serie no encontrada")
-		} else {
-			writeError(w, http.StatusInternalServerError, "Error buscando la serie: "+err.Error())
-		}
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
@@ -268,166 +389,133 @@ func UpdateSeriesStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validar que el status no esté vacío
-	if statusUpdate.Status == "" {
-		writeError(w, http.StatusBadRequest, "El campo 'status' no puede estar vacío")
+	if errs := validateStatusUpdate(statusUpdate); len(errs) > 0 {
+		writeValidationError(w, errs)
 		return
 	}
 
-	// Actualizar solo el campo 'Status' usando Update para eficiencia
-	if err := repository.DB.Model(&serie).Update("status", statusUpdate.Status).Error; err != nil {
-		writeError(w, http.StatusInternalServerError, "Error actualizando el estado de la serie: "+err.Error())
+	serie, err := h.repo.UpdateStatus(r.Context(), userID, id, statusUpdate.Status)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error actualizando el estado de la serie: ")
 		return
 	}
 
-	// Refrescar los datos de la serie (Update no actualiza el struct original por defecto)
-	repository.DB.First(&serie, id) // Volver a leer para obtener el estado actualizado
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(serie) // Devuelve la serie actualizada
+	writeSeries(w, http.StatusOK, serie) // Devuelve la serie actualizada
 }
 
 // IncrementSeriesEpisode godoc
 // @Summary      Incrementar episodio visto
-// @Description  Incrementa en 1 el campo 'lastEpisodeWatched' de la serie identificada por ID. No realiza cambios si el último episodio visto ya es igual o mayor al total de episodios.
+// @Description  Incrementa en 1 el campo 'lastEpisodeWatched' de la serie identificada por ID. No realiza cambios si el último episodio visto ya es igual o mayor al total de episodios. Ver un episodio pasa la serie a 'Watching' y, al llegar al último episodio, a 'Completed'.
 // @Tags         Series Actions
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie cuyo episodio se incrementará" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
 // @Success      200 {object} models.Series "Episodio incrementado, devuelve la serie actualizada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
 // @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al incrementar el episodio"
 // @Router       /series/{id}/episode [patch]
-func IncrementSeriesEpisode(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
-		return
-	}
-
-	var serie models.Series
-	if err := repository.DB.First(&serie, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			writeError(w, http.StatusNotFound, "Serie no encontrada")
-		} else {
-			writeError(w, http.StatusInternalServerError, "Error buscando la serie: "+err.Error())
-		}
+func (h *SeriesHandler) IncrementSeriesEpisode(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	// No incrementar si ya se alcanzó o superó el total (si el total es > 0)
-	if serie.TotalEpisodes > 0 && serie.LastEpisodeWatched >= serie.TotalEpisodes {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK) // OK, pero no se hizo cambio
-		json.NewEncoder(w).Encode(serie)
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
-	// Incrementar el contador de episodios vistos usando expresión SQL para atomicidad
-	newEpisodeCount := serie.LastEpisodeWatched + 1
-	if err := repository.DB.Model(&serie).Update("last_episode_watched", gorm.Expr("last_episode_watched + ?", 1)).Error; err != nil {
-		writeError(w, http.StatusInternalServerError, "Error incrementando el episodio: "+err.Error())
+	serie, err := h.repo.IncrementEpisode(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error incrementando el episodio: ")
 		return
 	}
 
-	// Actualizar el valor en la struct local para la respuesta
-	serie.LastEpisodeWatched = newEpisodeCount
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(serie) // Devuelve la serie actualizada
+	writeSeries(w, http.StatusOK, serie) // Devuelve la serie actualizada
 }
 
 // UpvoteSeries godoc
 // @Summary      Votar positivamente (Upvote) una serie
-// @Description  Incrementa en 1 el campo 'ranking' de la serie identificada por su ID.
+// @Description  Registra el voto positivo del usuario sobre la serie, propia o de otro usuario. Si ya tenía un voto positivo lo retira y si tenía uno negativo lo reemplaza (un voto por usuario). El ranking es el ranking anterior a los votos (legacyRanking) más los votos positivos menos los negativos de todos los usuarios. Si la serie es de otro usuario solo se devuelven sus datos públicos (models.PublicSeries).
 // @Tags         Series Actions
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie a votar positivamente" example(1)
-// @Success      200 {object} models.Series "Ranking incrementado, devuelve la serie actualizada"
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      200 {object} models.Series "Voto actualizado, devuelve la serie con el ranking recalculado"
+// @Header       200 {string} ETag "Nueva versión de la serie"
 // @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar el ranking"
 // @Router       /series/{id}/upvote [patch]
-func UpvoteSeries(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+func (h *SeriesHandler) UpvoteSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	var serie models.Series
-	// Verificar si existe antes de intentar actualizar
-	if err := repository.DB.First(&serie, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			writeError(w, http.StatusNotFound, "Serie no encontrada")
-		} else {
-			writeError(w, http.StatusInternalServerError, "Error buscando la serie: "+err.Error())
-		}
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
-	// Incrementar el ranking usando una expresión SQL para atomicidad
-	if err := repository.DB.Model(&serie).Update("ranking", gorm.Expr("ranking + 1")).Error; err != nil {
-		writeError(w, http.StatusInternalServerError, "Error al votar positivamente (upvote): "+err.Error())
+	serie, err := h.repo.ToggleVote(r.Context(), userID, id, models.VoteUp)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error al votar positivamente (upvote): ")
 		return
 	}
 
-	// Refrescar la struct para obtener el nuevo valor del ranking
-	repository.DB.First(&serie, id) // Volver a leer de la DB
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(serie) // Devuelve la serie actualizada
+	writeVotedSeries(w, userID, serie) // Devuelve la serie actualizada
 }
 
 // DownvoteSeries godoc
 // @Summary      Votar negativamente (Downvote) una serie
-// @Description  Decrementa en 1 el campo 'ranking' de la serie identificada por su ID.
+// @Description  Registra el voto negativo del usuario sobre la serie, propia o de otro usuario. Si ya tenía un voto negativo lo retira y si tenía uno positivo lo reemplaza (un voto por usuario). El ranking es el ranking anterior a los votos (legacyRanking) más los votos positivos menos los negativos de todos los usuarios. Si la serie es de otro usuario solo se devuelven sus datos públicos (models.PublicSeries).
 // @Tags         Series Actions
 // @Accept       json
 // @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Param        id path int true "ID de la Serie a votar negativamente" example(1)
-// @Success      200 {object} models.Series "Ranking decrementado, devuelve la serie actualizada"
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      200 {object} models.Series "Voto actualizado, devuelve la serie con el ranking recalculado"
+// @Header       200 {string} ETag "Nueva versión de la serie"
 // @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
 // @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
 // @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar el ranking"
 // @Router       /series/{id}/downvote [patch]
-func DownvoteSeries(w http.ResponseWriter, r *http.Request) {
-	idStr := chi.URLParam(r, "id")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "ID inválido: "+idStr)
+func (h *SeriesHandler) DownvoteSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
 		return
 	}
 
-	var serie models.Series
-	// Verificar si existe antes de intentar actualizar
-	if err := repository.DB.First(&serie, id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			writeError(w, http.StatusNotFound, "Serie no encontrada")
-		} else {
-			writeError(w, http.StatusInternalServerError, "Error buscando la serie: "+err.Error())
-		}
+	id, ok := seriesID(w, r)
+	if !ok {
 		return
 	}
 
-	// Decrementar el ranking usando una expresión SQL
-	// Considerar si se quiere prevenir que el ranking sea negativo
-	if err := repository.DB.Model(&serie).Update("ranking", gorm.Expr("ranking - 1")).Error; err != nil {
-		writeError(w, http.StatusInternalServerError, "Error al votar negativamente (downvote): "+err.Error())
+	serie, err := h.repo.ToggleVote(r.Context(), userID, id, models.VoteDown)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error al votar negativamente (downvote): ")
 		return
 	}
 
-	// Refrescar la struct para obtener el nuevo valor del ranking
-	repository.DB.First(&serie, id) // Volver a leer de la DB
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(serie) // Devuelve la serie actualizada
+	writeVotedSeries(w, userID, serie) // Devuelve la serie actualizada
 }
@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"lab6/auth"
+	"lab6/models"
+	"lab6/repository"
+	"lab6/storage"
+)
+
+// testUserID es el usuario autenticado en las solicitudes de prueba.
+const testUserID = 1
+
+// newTestRouter monta los handlers de series sobre un repositorio en memoria,
+// con las mismas rutas que main.go. El usuario de cada solicitud se toma del
+// header X-Test-User (testUserID si no se envía) en lugar de autenticarlo.
+func newTestRouter(repo repository.SeriesRepository) http.Handler {
+	h := NewSeriesHandler(repo, storage.NewMemoryStore())
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := testUserID
+			if header := r.Header.Get("X-Test-User"); header != "" {
+				if header == "none" {
+					next.ServeHTTP(w, r)
+					return
+				}
+				userID, _ = strconv.Atoi(header)
+			}
+			ctx := auth.WithPrincipal(r.Context(), auth.Principal{UserID: userID})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	r.Get("/series/top", h.GetTopSeries)
+	r.Get("/series/{id}", h.GetSeriesByID)
+	r.Post("/series", h.CreateSeries)
+	r.Post("/series/batch", h.BatchSeries)
+	r.Post("/import", h.ImportSeries)
+	r.Group(func(r chi.Router) {
+		r.Use(IfMatch)
+		r.Patch("/series/{id}", h.PatchSeries)
+		r.Patch("/series/{id}/status", h.UpdateSeriesStatus)
+		r.Patch("/series/{id}/episode", h.IncrementSeriesEpisode)
+		r.Patch("/series/{id}/episode/decrement", h.DecrementSeriesEpisode)
+		r.Patch("/series/{id}/upvote", h.UpvoteSeries)
+	})
+	return r
+}
+
+// do envía la solicitud al router y devuelve la respuesta registrada.
+func do(t *testing.T, router http.Handler, method, target, body string, headers ...string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode convierte el cuerpo JSON de la respuesta en v.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("respuesta no es JSON válido (%q): %v", rec.Body.String(), err)
+	}
+}
+
+// createSeries crea una serie del usuario userID directamente en el repositorio.
+func createSeries(t *testing.T, repo repository.SeriesRepository, userID int, s models.Series) models.Series {
+	t.Helper()
+	s.UserID = userID
+	if s.Status == "" {
+		s.Status = models.StatusPlanToWatch
+	}
+	if err := repo.Create(context.Background(), &s); err != nil {
+		t.Fatalf("creando la serie: %v", err)
+	}
+	return s
+}
+
+func TestCreateSeries(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+
+	rec := do(t, router, http.MethodPost, "/series", `{"title": "  Frieren ", "totalEpisodes": 28}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, se esperaba 201: %s", rec.Code, rec.Body)
+	}
+	var created models.Series
+	decode(t, rec, &created)
+	if created.ID == 0 || created.UserID != testUserID || created.Title != "Frieren" || created.Status != models.StatusPlanToWatch {
+		t.Errorf("serie creada inesperada: %+v", created)
+	}
+	if etag := rec.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf("ETag = %q, se esperaba \"1\"", etag)
+	}
+
+	rec = do(t, router, http.MethodPost, "/series", `{"title": "", "status": "Viendo"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, se esperaba 422: %s", rec.Code, rec.Body)
+	}
+	var resp ErrorResponse
+	decode(t, rec, &resp)
+	fields := map[string]bool{}
+	for _, e := range resp.Errors {
+		fields[e.Field] = true
+	}
+	if !fields["title"] || !fields["status"] {
+		t.Errorf("errores por campo = %+v, se esperaban 'title' y 'status'", resp.Errors)
+	}
+
+	rec = do(t, router, http.MethodPost, "/series", `{"title": "Frieren"}`, "X-Test-User", "none")
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("sin usuario: status = %d, se esperaba 401", rec.Code)
+	}
+}
+
+func TestGetSeriesByID(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	serie := createSeries(t, repo, testUserID, models.Series{Title: "Mushishi", TotalEpisodes: 26})
+	path := "/series/" + strconv.Itoa(serie.ID)
+
+	tests := []struct {
+		name    string
+		target  string
+		headers []string
+		want    int
+	}{
+		{"propia", path, nil, http.StatusOK},
+		{"de otro usuario", path, []string{"X-Test-User", "2"}, http.StatusNotFound},
+		{"inexistente", "/series/999", nil, http.StatusNotFound},
+		{"ID inválido", "/series/abc", nil, http.StatusBadRequest},
+		{"versión sin cambios", path, []string{"If-None-Match", `"1"`}, http.StatusNotModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, router, http.MethodGet, tt.target, "", tt.headers...)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, se esperaba %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestUpdateSeriesStatus(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	serie := createSeries(t, repo, testUserID, models.Series{Title: "Mononoke", TotalEpisodes: 12})
+	path := "/series/" + strconv.Itoa(serie.ID) + "/status"
+
+	rec := do(t, router, http.MethodPatch, path, `{"status": "Completed"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var updated models.Series
+	decode(t, rec, &updated)
+	if updated.Status != models.StatusCompleted || updated.LastEpisodeWatched != 12 {
+		t.Errorf("serie = %q en el episodio %d, se esperaba 'Completed' en el 12", updated.Status, updated.LastEpisodeWatched)
+	}
+
+	// 'Completed' solo puede pasar a 'Watching'
+	rec = do(t, router, http.MethodPatch, path, `{"status": "Dropped"}`)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("transición no permitida: status = %d, se esperaba 422: %s", rec.Code, rec.Body)
+	}
+
+	rec = do(t, router, http.MethodPatch, path, `{"status":`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("JSON mal formado: status = %d, se esperaba 400", rec.Code)
+	}
+}
+
+func TestIncrementSeriesEpisodeIfMatch(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	serie := createSeries(t, repo, testUserID, models.Series{Title: "Dororo", TotalEpisodes: 2})
+	path := "/series/" + strconv.Itoa(serie.ID) + "/episode"
+
+	rec := do(t, router, http.MethodPatch, path, "", "If-Match", `"1"`)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"2"` {
+		t.Fatalf("status = %d, ETag = %q; se esperaba 200 y \"2\"", rec.Code, rec.Header().Get("ETag"))
+	}
+
+	// Otra solicitud con la versión anterior no pisa el cambio
+	rec = do(t, router, http.MethodPatch, path, "", "If-Match", `"1"`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("If-Match desactualizado: status = %d, se esperaba 412", rec.Code)
+	}
+
+	rec = do(t, router, http.MethodPatch, path, "", "If-Match", `"2"`)
+	var updated models.Series
+	decode(t, rec, &updated)
+	if updated.Status != models.StatusCompleted || updated.LastEpisodeWatched != 2 {
+		t.Errorf("serie = %q en el episodio %d, se esperaba 'Completed' en el 2", updated.Status, updated.LastEpisodeWatched)
+	}
+
+	// Con la serie completa no hay cambios ni nueva versión
+	rec = do(t, router, http.MethodPatch, path, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"3"` {
+		t.Errorf("status = %d, ETag = %q; se esperaba 200 y \"3\"", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestDecrementSeriesEpisode(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	serie := createSeries(t, repo, testUserID, models.Series{Title: "Dororo", Status: models.StatusCompleted, LastEpisodeWatched: 24, TotalEpisodes: 24})
+	path := "/series/" + strconv.Itoa(serie.ID) + "/episode/decrement"
+
+	rec := do(t, router, http.MethodPatch, path, "")
+	var updated models.Series
+	decode(t, rec, &updated)
+	if rec.Code != http.StatusOK || updated.Status != models.StatusWatching || updated.LastEpisodeWatched != 23 {
+		t.Errorf("status = %d, serie = %q en el episodio %d; se esperaba 200 y 'Watching' en el 23", rec.Code, updated.Status, updated.LastEpisodeWatched)
+	}
+
+	// Un progreso que sigue superando el total tras restar no se guarda
+	broken := createSeries(t, repo, testUserID, models.Series{Title: "Trigun", Status: models.StatusWatching, LastEpisodeWatched: 30, TotalEpisodes: 26})
+	rec = do(t, router, http.MethodPatch, "/series/"+strconv.Itoa(broken.ID)+"/episode/decrement", "")
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, se esperaba 422: %s", rec.Code, rec.Body)
+	}
+	var resp ErrorResponse
+	decode(t, rec, &resp)
+	if len(resp.Errors) != 1 {
+		t.Errorf("errores por campo = %+v, se esperaba uno", resp.Errors)
+	}
+	if got, _ := repo.Get(context.Background(), testUserID, broken.ID); got.LastEpisodeWatched != 30 || got.Version != broken.Version {
+		t.Errorf("la serie cambió tras el error: %+v", got)
+	}
+}
+
+func TestUpvoteOtherUsersSeries(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	serie := createSeries(t, repo, testUserID, models.Series{Title: "Kaiba", TotalEpisodes: 12})
+	path := "/series/" + strconv.Itoa(serie.ID) + "/upvote"
+
+	do(t, router, http.MethodPatch, path, "")
+	rec := do(t, router, http.MethodPatch, path, "", "X-Test-User", "2")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	// Para el otro usuario solo se devuelven los datos públicos
+	var public map[string]any
+	decode(t, rec, &public)
+	if public["ranking"] != float64(2) {
+		t.Errorf("ranking = %v, se esperaba 2", public["ranking"])
+	}
+	if _, ok := public["status"]; ok {
+		t.Errorf("la respuesta a otro usuario incluye el estado: %s", rec.Body)
+	}
+
+	rec = do(t, router, http.MethodGet, "/series/top?scope=all", "", "X-Test-User", "2")
+	var top []models.PublicSeries
+	decode(t, rec, &top)
+	if len(top) != 1 || top[0].ID != serie.ID || top[0].Ranking != 2 {
+		t.Errorf("top de todos los usuarios = %+v, se esperaba la serie %d con ranking 2", top, serie.ID)
+	}
+
+	rec = do(t, router, http.MethodGet, "/series/top", "", "X-Test-User", "2")
+	decode(t, rec, &top)
+	if len(top) != 0 {
+		t.Errorf("top propio del otro usuario = %+v, se esperaba vacío", top)
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+
+	"lab6/models"
+)
+
+// GetSeriesHistory godoc
+// @Summary      Historial de visualización de una serie
+// @Description  Devuelve los cambios de progreso y de estado de la serie, del más reciente al más antiguo. Cada evento indica el último episodio visto y el estado antes y después del cambio. El total se devuelve en el header X-Total-Count y los enlaces de navegación en el header Link.
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id       path  int true  "ID de la Serie" example(1)
+// @Param        page     query int false "Número de página (empieza en 1)" default(1)
+// @Param        pageSize query int false "Cantidad de eventos por página (máximo 500)" default(100)
+// @Success      200 {array}  models.WatchEvent "Página del historial"
+// @Header       200 {integer} X-Total-Count "Número total de eventos de la serie"
+// @Header       200 {string}  Link "Enlaces first, prev, next y last (RFC 8288)"
+// @Failure      400 {object} ErrorResponse "ID o query params inválidos"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener el historial"
+// @Router       /series/{id}/history [get]
+func (h *SeriesHandler) GetSeriesHistory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var q models.HistoryQuery
+	var err error
+	if q.Page, q.PageSize, err = parsePagination(r.URL.Query()); err != nil {
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: "+err.Error())
+		return
+	}
+
+	events, total, err := h.repo.History(r.Context(), userID, id, q)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error obteniendo el historial: ")
+		return
+	}
+
+	setPaginationHeaders(w, r, q.Page, q.PageSize, total)
+	writeJSON(w, http.StatusOK, events)
+}
+
+// UndoSeriesEvent godoc
+// @Summary      Deshacer el último cambio de progreso
+// @Description  Elimina el último evento del historial de la serie y la devuelve al episodio y estado que tenía antes de ese cambio (limitado al total de episodios actual si este bajó). Puede repetirse para seguir deshaciendo.
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Success      200 {object} models.Series "Cambio deshecho, devuelve la serie actualizada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      409 {object} ErrorResponse "La serie no tiene cambios para deshacer"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al deshacer el cambio"
+// @Router       /series/{id}/history/undo [post]
+func (h *SeriesHandler) UndoSeriesEvent(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	serie, err := h.repo.UndoLastEvent(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error deshaciendo el cambio: ")
+		return
+	}
+
+	writeSeries(w, http.StatusOK, serie)
+}
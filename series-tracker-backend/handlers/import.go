@@ -0,0 +1,413 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"lab6/models"
+	"lab6/repository"
+	"lab6/transfer"
+)
+
+const (
+	// maxImportBytes es el tamaño máximo del archivo de importación (sin descomprimir).
+	maxImportBytes = 16 << 20
+	// maxImportRows limita la cantidad de entradas de una importación.
+	maxImportRows = 10000
+	// importFileField es el campo multipart con el archivo, si se envía como formulario.
+	importFileField = "file"
+)
+
+// Resultados posibles de cada fila de una importación.
+const (
+	importCreated = "created"
+	importUpdated = "updated"
+	importSkipped = "skipped"
+	importFailed  = "failed"
+)
+
+// ImportRow es el resultado de importar una entrada del archivo.
+// @Description Resultado de una entrada de la importación.
+type ImportRow struct {
+	// Row es la posición (desde 1) de la entrada en el archivo.
+	// example: 3
+	Row int `json:"row"`
+	// Title es el título de la entrada.
+	// example: "Cowboy Bebop"
+	Title string `json:"title"`
+	// Action es 'created', 'updated', 'skipped' o 'failed'.
+	// example: "updated"
+	Action string `json:"action" enums:"created,updated,skipped,failed"`
+	// SeriesID es el ID de la serie creada, actualizada u omitida por existir (0 si no hay).
+	// example: 12
+	SeriesID int `json:"seriesId,omitempty"`
+	// Message explica por qué la entrada se omitió o falló.
+	// example: "sin cambios"
+	Message string `json:"message,omitempty"`
+	// Errors detalla los campos inválidos de la entrada, si falló por validación.
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// ImportReport es la respuesta de "POST /api/import".
+// @Description Resumen y detalle por fila de una importación.
+type ImportReport struct {
+	// Format es el formato del archivo: 'mal' o 'anilist'.
+	// example: "mal"
+	Format string `json:"format"`
+	// DryRun indica que no se guardó ningún cambio (solo se simuló la importación).
+	DryRun bool `json:"dryRun"`
+	// Total, Created, Updated, Skipped y Failed cuentan las filas por resultado.
+	// example: 120
+	Total int `json:"total"`
+	// example: 100
+	Created int `json:"created"`
+	// example: 12
+	Updated int `json:"updated"`
+	// example: 6
+	Skipped int `json:"skipped"`
+	// example: 2
+	Failed int `json:"failed"`
+	// Rows tiene el resultado de cada entrada, en el orden del archivo.
+	Rows []ImportRow `json:"rows"`
+}
+
+// add registra el resultado de una fila y actualiza los contadores.
+func (rep *ImportReport) add(row ImportRow) {
+	switch row.Action {
+	case importCreated:
+		rep.Created++
+	case importUpdated:
+		rep.Updated++
+	case importSkipped:
+		rep.Skipped++
+	case importFailed:
+		rep.Failed++
+	}
+	rep.Total++
+	rep.Rows = append(rep.Rows, row)
+}
+
+// importOptions son las opciones de "POST /api/import" leídas de la query.
+type importOptions struct {
+	format     string
+	dryRun     bool
+	skipExists bool
+}
+
+// ImportSeries godoc
+// @Summary      Importar una lista de MyAnimeList o AniList
+// @Description  Importa las entradas de una exportación XML de MyAnimeList (también comprimida, .xml.gz) o de un volcado JSON de AniList (respuesta GraphQL de MediaListCollection o array de entradas). El archivo se envía como cuerpo de la solicitud o en el campo multipart 'file' (hasta 16 MiB). Los estados y el progreso se convierten a los de la aplicación y la puntuación se guarda en la reseña del usuario (conservando sus notas). Las entradas se deduplican por título, sin distinguir mayúsculas: si ya existe una serie con ese título se actualizan su estado y su progreso y se completan los datos descriptivos vacíos (o se omite con onDuplicate=skip), y las entradas repetidas en el archivo se omiten. Cada entrada se guarda por separado: un error en una fila no impide importar las demás. La respuesta detalla el resultado de cada fila.
+// @Tags         Import/Export
+// @Accept       xml
+// @Accept       json
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        format      query string false "Formato del archivo; si se omite se detecta por el contenido" Enums(mal, anilist)
+// @Param        onDuplicate query string false "Qué hacer si ya existe una serie con el mismo título" Enums(update, skip) default(update)
+// @Param        dryRun      query bool   false "Simular la importación sin guardar cambios" default(false)
+// @Param        file        formData file false "Archivo de exportación (alternativa a enviarlo como cuerpo)"
+// @Success      200 {object} ImportReport "Resultado de la importación por fila"
+// @Failure      400 {object} ErrorResponse "Parámetros inválidos o archivo vacío, ilegible o de formato no reconocido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      413 {object} ErrorResponse "El archivo supera los 16 MiB o las 10000 entradas"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al importar"
+// @Router       /import [post]
+func (h *SeriesHandler) ImportSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	opts, ok := parseImportOptions(w, r)
+	if !ok {
+		return
+	}
+	data, ok := readImportFile(w, r)
+	if !ok {
+		return
+	}
+
+	entries, format, err := transfer.Parse(data, opts.format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "No se pudo leer el archivo: "+err.Error())
+		return
+	}
+	if len(entries) > maxImportRows {
+		writeError(w, http.StatusRequestEntityTooLarge, "El archivo tiene "+strconv.Itoa(len(entries))+" entradas; se admiten hasta "+strconv.Itoa(maxImportRows))
+		return
+	}
+
+	// Series actuales del usuario por título, para deduplicar
+	existing, _, err := h.repo.List(r.Context(), userID, models.SeriesQuery{})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error obteniendo las series: "+err.Error())
+		return
+	}
+	byTitle := make(map[string]models.Series, len(existing))
+	for _, s := range existing {
+		if _, dup := byTitle[titleKey(s.Title)]; !dup {
+			byTitle[titleKey(s.Title)] = s
+		}
+	}
+
+	report := ImportReport{Format: format, DryRun: opts.dryRun, Rows: []ImportRow{}}
+	seen := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		row := ImportRow{Row: entry.Row, Title: entry.Title}
+		key := titleKey(entry.Title)
+		switch first, dup := seen[key]; {
+		case entry.Problem != "":
+			row.Action, row.Message = importFailed, entry.Problem
+		case dup:
+			row.Action, row.Message = importSkipped, "título repetido en el archivo (fila "+strconv.Itoa(first)+")"
+		default:
+			seen[key] = entry.Row
+			if current, found := byTitle[key]; found {
+				h.importExisting(r, userID, current, entry, opts, &row)
+			} else {
+				h.importNew(r, userID, entry, opts, &row)
+			}
+		}
+		report.add(row)
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// importNew crea la serie de entry (salvo en una simulación) y completa row.
+func (h *SeriesHandler) importNew(r *http.Request, userID int, entry transfer.Entry, opts importOptions, row *ImportRow) {
+	serie := entry.Series
+	if errs := validateSeries(&serie, true); len(errs) > 0 {
+		row.Action, row.Message, row.Errors = importFailed, "datos inválidos", errs
+		return
+	}
+	serie.ApplyProgressRules()
+	serie.UserID = userID
+
+	row.Action = importCreated
+	if opts.dryRun {
+		return
+	}
+	if err := h.repo.Create(r.Context(), &serie); err != nil {
+		row.Action, row.Message = importFailed, "Error creando la serie: "+err.Error()
+		return
+	}
+	row.SeriesID = serie.ID
+	if entry.Score != nil {
+		review := models.Review{SeriesID: serie.ID, UserID: userID, Score: entry.Score}
+		if _, err := h.repo.SaveReview(r.Context(), &review); err != nil {
+			row.Message = "la serie se creó, pero no se pudo guardar la puntuación: " + err.Error()
+		}
+	}
+}
+
+// importExisting aplica entry sobre la serie existente current (salvo con
+// onDuplicate=skip o en una simulación) y completa row. Si no hay cambios la
+// fila se omite.
+func (h *SeriesHandler) importExisting(r *http.Request, userID int, current models.Series, entry transfer.Entry, opts importOptions, row *ImportRow) {
+	row.SeriesID = current.ID
+	if opts.skipExists {
+		row.Action, row.Message = importSkipped, "ya existe una serie con ese título"
+		return
+	}
+
+	merged := mergeImported(current, entry.Series)
+	if errs := validateSeries(&merged, false); len(errs) > 0 {
+		row.Action, row.Message, row.Errors = importFailed, "datos inválidos", errs
+		return
+	}
+	merged.ApplyProgressRules()
+	if err := models.ValidateTransition(current.Status, merged.Status); err != nil {
+		row.Action, row.Message = importFailed, err.Error()
+		return
+	}
+	seriesChanged := importChanges(current, merged)
+
+	review, err := h.repo.GetReview(r.Context(), userID, current.ID)
+	if err != nil && !errors.Is(err, repository.ErrNoReview) {
+		row.Action, row.Message = importFailed, "Error obteniendo la reseña: "+err.Error()
+		return
+	}
+	scoreChanged := entry.Score != nil && (review.Score == nil || *review.Score != *entry.Score)
+
+	if !seriesChanged && !scoreChanged {
+		row.Action, row.Message = importSkipped, "sin cambios"
+		return
+	}
+	row.Action = importUpdated
+	if opts.dryRun {
+		return
+	}
+	if seriesChanged {
+		if err := h.repo.Update(r.Context(), &merged); err != nil {
+			row.Action, row.Message = importFailed, "Error actualizando la serie: "+err.Error()
+			return
+		}
+	}
+	if scoreChanged {
+		// Se conservan las notas de la reseña existente
+		review := models.Review{SeriesID: current.ID, UserID: userID, Score: entry.Score, Body: review.Body}
+		if _, err := h.repo.SaveReview(r.Context(), &review); err != nil {
+			row.Message = "no se pudo guardar la puntuación: " + err.Error()
+		}
+	}
+}
+
+// mergeImported devuelve current con el estado y el progreso de imported y los
+// datos descriptivos que current no tiene. El total de episodios solo se toma
+// si la serie no tiene temporadas (que lo determinan).
+func mergeImported(current, imported models.Series) models.Series {
+	merged := current
+	merged.Status = imported.Status
+	merged.LastEpisodeWatched = imported.LastEpisodeWatched
+	if imported.TotalEpisodes > 0 && len(current.SeasonEpisodes) == 0 {
+		merged.TotalEpisodes = imported.TotalEpisodes
+	}
+	if merged.TotalEpisodes > 0 && merged.LastEpisodeWatched > merged.TotalEpisodes {
+		merged.LastEpisodeWatched = merged.TotalEpisodes
+	}
+
+	if len(merged.AltTitles) == 0 {
+		merged.AltTitles = imported.AltTitles
+	}
+	if merged.CoverURL == "" {
+		merged.CoverURL = imported.CoverURL
+	}
+	if merged.StartYear == nil {
+		merged.StartYear = imported.StartYear
+	}
+	if merged.EndYear == nil {
+		merged.EndYear = imported.EndYear
+	}
+	if merged.MediaType == "" {
+		merged.MediaType = imported.MediaType
+	}
+	if merged.Studio == "" {
+		merged.Studio = imported.Studio
+	}
+	if merged.AiringStatus == "" {
+		merged.AiringStatus = imported.AiringStatus
+	}
+	return merged
+}
+
+// importChanges indica si merged difiere de current en algún campo que la importación puede cambiar.
+func importChanges(current, merged models.Series) bool {
+	return current.Status != merged.Status ||
+		current.LastEpisodeWatched != merged.LastEpisodeWatched ||
+		current.TotalEpisodes != merged.TotalEpisodes ||
+		!slices.Equal(current.AltTitles, merged.AltTitles) ||
+		current.CoverURL != merged.CoverURL ||
+		!equalYear(current.StartYear, merged.StartYear) ||
+		!equalYear(current.EndYear, merged.EndYear) ||
+		current.MediaType != merged.MediaType ||
+		current.Studio != merged.Studio ||
+		current.AiringStatus != merged.AiringStatus
+}
+
+// equalYear compara dos años opcionales.
+func equalYear(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// titleKey normaliza un título para deduplicar: minúsculas y espacios simples.
+func titleKey(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// parseImportOptions lee los parámetros de query de la importación. Si alguno
+// no es válido escribe un 400 y devuelve false.
+func parseImportOptions(w http.ResponseWriter, r *http.Request) (importOptions, bool) {
+	query := r.URL.Query()
+	var opts importOptions
+
+	switch opts.format = query.Get("format"); opts.format {
+	case "", transfer.FormatMAL, transfer.FormatAniList:
+	default:
+		writeError(w, http.StatusBadRequest, "Parámetro 'format' inválido: use '"+transfer.FormatMAL+"' o '"+transfer.FormatAniList+"'")
+		return opts, false
+	}
+
+	switch query.Get("onDuplicate") {
+	case "", "update":
+	case "skip":
+		opts.skipExists = true
+	default:
+		writeError(w, http.StatusBadRequest, "Parámetro 'onDuplicate' inválido: use 'update' o 'skip'")
+		return opts, false
+	}
+
+	if raw := query.Get("dryRun"); raw != "" {
+		dryRun, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Parámetro 'dryRun' inválido: use 'true' o 'false'")
+			return opts, false
+		}
+		opts.dryRun = dryRun
+	}
+	return opts, true
+}
+
+// readImportFile lee el archivo de importación del campo multipart 'file' o,
+// si la solicitud no es multipart, del cuerpo completo. Si falta, está vacío o
+// supera maxImportBytes escribe el error (400 o 413) y devuelve false.
+func readImportFile(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	tooLarge := "El archivo supera el máximo de " + strconv.Itoa(maxImportBytes>>20) + " MiB"
+	body := io.Reader(http.MaxBytesReader(w, r.Body, maxImportBytes+64<<10))
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		r.Body = io.NopCloser(body)
+		reader, err := r.MultipartReader()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "Cuerpo multipart inválido: "+err.Error())
+			return nil, false
+		}
+		for {
+			part, err := reader.NextPart()
+			var maxBytesErr *http.MaxBytesError
+			switch {
+			case errors.Is(err, io.EOF):
+				writeError(w, http.StatusBadRequest, "Falta el campo '"+importFileField+"' con el archivo")
+				return nil, false
+			case errors.As(err, &maxBytesErr):
+				writeError(w, http.StatusRequestEntityTooLarge, tooLarge)
+				return nil, false
+			case err != nil:
+				writeError(w, http.StatusBadRequest, "Cuerpo multipart inválido: "+err.Error())
+				return nil, false
+			}
+			if part.FormName() == importFileField {
+				body = part
+				break
+			}
+			part.Close()
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxImportBytes+1))
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr) || len(data) > maxImportBytes:
+		writeError(w, http.StatusRequestEntityTooLarge, tooLarge)
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusBadRequest, "Error leyendo el archivo: "+err.Error())
+		return nil, false
+	case len(bytes.TrimSpace(data)) == 0:
+		writeError(w, http.StatusBadRequest, "El archivo está vacío")
+		return nil, false
+	}
+	return data, true
+}
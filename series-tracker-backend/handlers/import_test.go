@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// importMAL es una exportación de MyAnimeList con un título que ya existe (con
+// otras mayúsculas y espacios), títulos repetidos en el archivo y un estado
+// desconocido.
+const importMAL = `<?xml version="1.0" encoding="UTF-8" ?>
+<myanimelist>
+	<anime>
+		<series_title>cowboy bebop</series_title>
+		<series_episodes>26</series_episodes>
+		<my_watched_episodes>26</my_watched_episodes>
+		<my_score>9</my_score>
+		<my_status>Completed</my_status>
+	</anime>
+	<anime>
+		<series_title>Trigun</series_title>
+		<series_episodes>26</series_episodes>
+		<my_watched_episodes>30</my_watched_episodes>
+		<my_score>0</my_score>
+		<my_status>Watching</my_status>
+	</anime>
+	<anime>
+		<series_title>Cowboy Bebop</series_title>
+		<series_episodes>26</series_episodes>
+		<my_watched_episodes>3</my_watched_episodes>
+		<my_status>Dropped</my_status>
+	</anime>
+	<anime>
+		<series_title>Mushishi</series_title>
+		<series_episodes>26</series_episodes>
+		<my_status>Rewatching</my_status>
+	</anime>
+	<anime>
+		<series_title> TRIGUN </series_title>
+		<my_status>Plan to Watch</my_status>
+	</anime>
+</myanimelist>`
+
+func TestImportSeries(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	bebop := createSeries(t, repo, testUserID, models.Series{
+		Title: "Cowboy  BEBOP", Status: models.StatusWatching, LastEpisodeWatched: 5, TotalEpisodes: 26,
+	})
+
+	rec := do(t, router, http.MethodPost, "/import", importMAL, "Content-Type", "application/xml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var report ImportReport
+	decode(t, rec, &report)
+	if report.Format != "mal" || report.Total != 5 || report.Created != 1 || report.Updated != 1 || report.Skipped != 2 || report.Failed != 1 {
+		t.Errorf("resumen inesperado: %+v", report)
+	}
+	want := []struct {
+		action  string
+		message string
+	}{
+		{importUpdated, ""},
+		{importCreated, ""},
+		{importSkipped, "título repetido en el archivo (fila 1)"},
+		{importFailed, `estado de MyAnimeList desconocido: "Rewatching"`},
+		{importSkipped, "título repetido en el archivo (fila 2)"},
+	}
+	if len(report.Rows) != len(want) {
+		t.Fatalf("se obtuvieron %d filas, se esperaban %d: %+v", len(report.Rows), len(want), report.Rows)
+	}
+	for i, w := range want {
+		row := report.Rows[i]
+		if row.Row != i+1 || row.Action != w.action || row.Message != w.message {
+			t.Errorf("fila %d = %+v, se esperaba %s %q", i+1, row, w.action, w.message)
+		}
+	}
+	if report.Rows[0].SeriesID != bebop.ID {
+		t.Errorf("la fila 1 actualizó la serie %d, se esperaba la existente %d", report.Rows[0].SeriesID, bebop.ID)
+	}
+
+	ctx := context.Background()
+	updated, err := repo.Get(ctx, testUserID, bebop.ID)
+	if err != nil {
+		t.Fatalf("obteniendo la serie: %v", err)
+	}
+	if updated.Title != bebop.Title || updated.Status != models.StatusCompleted || updated.LastEpisodeWatched != 26 {
+		t.Errorf("serie actualizada inesperada: %+v", updated)
+	}
+	review, err := repo.GetReview(ctx, testUserID, bebop.ID)
+	if err != nil || review.Score == nil || *review.Score != 9 {
+		t.Errorf("reseña = %+v, %v; se esperaba la puntuación 9", review, err)
+	}
+
+	trigun, err := repo.Get(ctx, testUserID, report.Rows[1].SeriesID)
+	if err != nil {
+		t.Fatalf("obteniendo la serie creada: %v", err)
+	}
+	if trigun.Title != "Trigun" || trigun.LastEpisodeWatched != 26 {
+		t.Errorf("serie creada inesperada (el progreso debía limitarse a 26): %+v", trigun)
+	}
+
+	all, _, err := repo.List(ctx, testUserID, models.SeriesQuery{})
+	if err != nil {
+		t.Fatalf("listando las series: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("hay %d series, se esperaban 2 (las repetidas y la fallida no se crean)", len(all))
+	}
+}
+
+func TestImportSeriesOptions(t *testing.T) {
+	repo := repository.NewMemorySeriesRepository()
+	router := newTestRouter(repo)
+	bebop := createSeries(t, repo, testUserID, models.Series{Title: "Cowboy Bebop", TotalEpisodes: 26})
+
+	// Con onDuplicate=skip la serie existente no se toca y con dryRun no se crea nada
+	rec := do(t, router, http.MethodPost, "/import?onDuplicate=skip&dryRun=true", importMAL)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var report ImportReport
+	decode(t, rec, &report)
+	if !report.DryRun || report.Created != 1 || report.Updated != 0 || report.Skipped != 3 {
+		t.Errorf("resumen inesperado: %+v", report)
+	}
+	if row := report.Rows[0]; row.Action != importSkipped || row.SeriesID != bebop.ID || row.Message != "ya existe una serie con ese título" {
+		t.Errorf("fila 1 = %+v, se esperaba omitida por existir", row)
+	}
+	all, _, err := repo.List(context.Background(), testUserID, models.SeriesQuery{})
+	if err != nil {
+		t.Fatalf("listando las series: %v", err)
+	}
+	if len(all) != 1 || all[0].Status != models.StatusPlanToWatch {
+		t.Errorf("la simulación modificó las series: %+v", all)
+	}
+
+	for _, target := range []string{"/import?format=csv", "/import?onDuplicate=merge", "/import?dryRun=quizás"} {
+		if rec := do(t, router, http.MethodPost, target, importMAL); rec.Code != http.StatusBadRequest {
+			t.Errorf("POST %s: status = %d, se esperaba 400", target, rec.Code)
+		}
+	}
+	if rec := do(t, router, http.MethodPost, "/import", "titulo,estado\n"); rec.Code != http.StatusBadRequest {
+		t.Errorf("formato no reconocido: status = %d, se esperaba 400", rec.Code)
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"lab6/models"
+)
+
+// ListCustomLists godoc
+// @Summary      Listar listas personalizadas
+// @Description  Devuelve las listas personalizadas del usuario autenticado, ordenadas por nombre, con la cantidad de series de cada una. Las series de una lista se obtienen con GET /api/series?list={id}.
+// @Tags         Lists
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200 {array}  models.CustomList "Listas del usuario"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener las listas"
+// @Router       /lists [get]
+func (h *CollectionHandler) ListCustomLists(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	lists, err := h.collections.ListLists(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error obteniendo las listas: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lists)
+}
+
+// GetCustomList godoc
+// @Summary      Obtener una lista personalizada
+// @Description  Devuelve el nombre, la descripción y la cantidad de series de una lista del usuario autenticado.
+// @Tags         Lists
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la lista" example(1)
+// @Success      200 {object} models.CustomList "Lista encontrada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Lista no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener la lista"
+// @Router       /lists/{id} [get]
+func (h *CollectionHandler) GetCustomList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	list, err := h.collections.GetList(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Lista no encontrada", "Error obteniendo la lista: ")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// CreateCustomList godoc
+// @Summary      Crear una lista personalizada
+// @Description  Crea una lista con nombre del usuario autenticado (por ejemplo "Para ver con amigos"). El nombre es único por usuario sin distinguir mayúsculas.
+// @Tags         Lists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        list body models.CustomListInput true "Nombre y descripción de la lista"
+// @Success      201 {object} models.CustomList "Lista creada"
+// @Failure      400 {object} ErrorResponse "JSON mal formado"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      409 {object} ErrorResponse "Ya existe una lista con ese nombre"
+// @Failure      422 {object} ErrorResponse "Datos inválidos (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al crear la lista"
+// @Router       /lists [post]
+func (h *CollectionHandler) CreateCustomList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var in models.CustomListInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if errs := validateCustomList(&in); len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	list := models.CustomList{UserID: userID, Name: in.Name, Description: in.Description}
+	if err := h.collections.CreateList(r.Context(), &list); err != nil {
+		writeCollectionError(w, err, "Lista no encontrada", "Ya existe una lista con ese nombre", "Error creando la lista: ")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, list)
+}
+
+// UpdateCustomList godoc
+// @Summary      Modificar una lista personalizada
+// @Description  Reemplaza el nombre y la descripción de una lista del usuario autenticado; sus series no cambian.
+// @Tags         Lists
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id   path int true "ID de la lista" example(1)
+// @Param        list body models.CustomListInput true "Nuevo nombre y descripción"
+// @Success      200 {object} models.CustomList "Lista actualizada"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Lista no encontrada"
+// @Failure      409 {object} ErrorResponse "Ya existe una lista con ese nombre"
+// @Failure      422 {object} ErrorResponse "Datos inválidos (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar la lista"
+// @Router       /lists/{id} [put]
+func (h *CollectionHandler) UpdateCustomList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var in models.CustomListInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if errs := validateCustomList(&in); len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	list := models.CustomList{ID: id, UserID: userID, Name: in.Name, Description: in.Description}
+	if err := h.collections.UpdateList(r.Context(), &list); err != nil {
+		writeCollectionError(w, err, "Lista no encontrada", "Ya existe una lista con ese nombre", "Error actualizando la lista: ")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// DeleteCustomList godoc
+// @Summary      Eliminar una lista personalizada
+// @Description  Elimina una lista del usuario autenticado. Las series de la lista no se eliminan.
+// @Tags         Lists
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la lista" example(1)
+// @Success      204 "Lista eliminada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Lista no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al eliminar la lista"
+// @Router       /lists/{id} [delete]
+func (h *CollectionHandler) DeleteCustomList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.collections.DeleteList(r.Context(), userID, id); err != nil {
+		writeRepositoryError(w, err, "Lista no encontrada", "Error eliminando la lista: ")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddSeriesToList godoc
+// @Summary      Agregar una serie a una lista
+// @Description  Agrega la serie a la lista personalizada. Es idempotente: si la serie ya estaba no cambia nada.
+// @Tags         Lists
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id       path int true "ID de la lista" example(1)
+// @Param        seriesId path int true "ID de la Serie" example(3)
+// @Success      204 "Serie agregada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Lista o serie no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al agregar la serie"
+// @Router       /lists/{id}/series/{seriesId} [put]
+func (h *CollectionHandler) AddSeriesToList(w http.ResponseWriter, r *http.Request) {
+	h.changeListItem(w, r, h.collections.AddToList, "Error agregando la serie a la lista: ")
+}
+
+// RemoveSeriesFromList godoc
+// @Summary      Quitar una serie de una lista
+// @Description  Quita la serie de la lista personalizada (la serie no se elimina). Es idempotente: si la serie no estaba no cambia nada.
+// @Tags         Lists
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id       path int true "ID de la lista" example(1)
+// @Param        seriesId path int true "ID de la Serie" example(3)
+// @Success      204 "Serie quitada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Lista o serie no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al quitar la serie"
+// @Router       /lists/{id}/series/{seriesId} [delete]
+func (h *CollectionHandler) RemoveSeriesFromList(w http.ResponseWriter, r *http.Request) {
+	h.changeListItem(w, r, h.collections.RemoveFromList, "Error quitando la serie de la lista: ")
+}
+
+// changeListItem lee los IDs de lista y serie de la URL y aplica change.
+func (h *CollectionHandler) changeListItem(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, userID, listID, seriesID int) error, prefix string) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	listID, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "seriesId")
+	if !ok {
+		return
+	}
+
+	if err := change(r.Context(), userID, listID, id); err != nil {
+		writeRepositoryError(w, err, "Lista o serie no encontrada", prefix)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"lab6/auth"
+)
+
+// AuthMiddleware autentica las solicitudes probando en orden una lista de
+// autenticadores (sesiones, JWT, ...) y agrega el principal al contexto.
+type AuthMiddleware struct {
+	authenticators []auth.Authenticator
+}
+
+// NewAuthMiddleware crea un AuthMiddleware con los autenticadores indicados.
+func NewAuthMiddleware(authenticators ...auth.Authenticator) *AuthMiddleware {
+	return &AuthMiddleware{authenticators: authenticators}
+}
+
+// writeUnauthorized responde 401 indicando el esquema de autenticación esperado.
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="series-tracker"`)
+	writeError(w, http.StatusUnauthorized, message)
+}
+
+// currentUserID devuelve el ID del usuario autenticado en la solicitud.
+// Si no hay usuario (la ruta no pasó por RequireAuth) escribe un 401 y devuelve false.
+func currentUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	principal, ok := auth.FromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "Se requiere autenticación")
+		return 0, false
+	}
+	return principal.UserID, true
+}
+
+// RequireAuth es un middleware que exige credenciales válidas. Responde 401 si
+// no se enviaron o son inválidas, y 500 si falla la verificación.
+func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, authenticator := range m.authenticators {
+			principal, err := authenticator.Authenticate(r)
+			if errors.Is(err, auth.ErrNoCredentials) {
+				continue
+			}
+			var credErr *auth.CredentialsError
+			if errors.As(err, &credErr) {
+				writeUnauthorized(w, "Credenciales inválidas: "+credErr.Reason)
+				return
+			}
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, "Error verificando las credenciales: "+err.Error())
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+			return
+		}
+		writeUnauthorized(w, "Se requiere autenticación: envíe el header 'Authorization: Bearer <token>'")
+	})
+}
+
+// RequireScope devuelve un middleware que responde 403 si el principal no tiene el scope indicado.
+// Debe montarse después de RequireAuth.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				writeUnauthorized(w, "Se requiere autenticación")
+				return
+			}
+			if !principal.HasScope(scope) {
+				writeError(w, http.StatusForbidden, "Permisos insuficientes: se requiere el scope '"+scope+"'")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireMethodScope exige el scope 'read' para los métodos de solo lectura
+// (GET, HEAD, OPTIONS) y el scope 'write' para el resto.
+func RequireMethodScope(next http.Handler) http.Handler {
+	read, write := RequireScope(auth.ScopeRead)(next), RequireScope(auth.ScopeWrite)(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			read.ServeHTTP(w, r)
+		default:
+			write.ServeHTTP(w, r)
+		}
+	})
+}
+
+// RejectAPIKeys responde 403 si la solicitud se autenticó con una clave de API.
+// Se usa en rutas sensibles (como la gestión de claves) para que una clave
+// filtrada no pueda crear otras claves ni revocar las existentes.
+// Debe montarse después de RequireAuth.
+func RejectAPIKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := auth.FromContext(r.Context()); ok && principal.Method == auth.MethodAPIKey {
+			writeError(w, http.StatusForbidden, "Esta operación requiere una sesión de usuario o un JWT, no una clave de API")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
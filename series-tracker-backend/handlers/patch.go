@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// Tipos de contenido aceptados por "PATCH /api/series/{id}".
+const (
+	// contentTypeMergePatch es JSON Merge Patch (RFC 7396).
+	contentTypeMergePatch = "application/merge-patch+json"
+	// contentTypeJSONPatch es JSON Patch (RFC 6902).
+	contentTypeJSONPatch = "application/json-patch+json"
+
+	// maxPatchBytes limita el tamaño del cuerpo de un parche.
+	maxPatchBytes = 1 << 20
+	// patchAttempts es la cantidad de veces que se reintenta aplicar un parche sin
+	// If-Match cuando otra solicitud modifica la serie entre la lectura y la escritura.
+	patchAttempts = 3
+)
+
+// patchError indica que el parche no se puede aplicar a la serie; lleva el
+// código HTTP con el que se responde.
+type patchError struct {
+	status  int
+	message string
+}
+
+// Error implementa error.
+func (e *patchError) Error() string {
+	return e.message
+}
+
+// patchDocument devuelve el documento JSON de s sobre el que se aplican los
+// parches. Incluye siempre todos los campos modificables, también los que se
+// omiten en las respuestas cuando están vacíos (altTitles y seasonEpisodes),
+// para que un "replace" de JSON Patch sobre ellos no falle por ruta inexistente.
+func patchDocument(s models.Series) ([]byte, error) {
+	doc, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+	for _, field := range []string{"altTitles", "seasonEpisodes"} {
+		if _, ok := fields[field]; !ok {
+			fields[field] = json.RawMessage("[]")
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// applySeriesPatch aplica el parche body (del tipo mediaType) a la serie s y
+// devuelve la serie resultante. Los campos que controla el servidor (id,
+// userId, version) se conservan aunque el parche los modifique.
+func applySeriesPatch(s models.Series, mediaType string, body []byte) (models.Series, error) {
+	doc, err := patchDocument(s)
+	if err != nil {
+		return models.Series{}, err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case contentTypeMergePatch, "application/json":
+		if patched, err = jsonpatch.MergePatch(doc, body); err != nil {
+			return models.Series{}, &patchError{http.StatusBadRequest, "JSON Merge Patch inválido: " + err.Error()}
+		}
+	case contentTypeJSONPatch:
+		patch, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return models.Series{}, &patchError{http.StatusBadRequest, "JSON Patch inválido: " + err.Error()}
+		}
+		if patched, err = patch.Apply(doc); err != nil {
+			if errors.Is(err, jsonpatch.ErrTestFailed) {
+				return models.Series{}, &patchError{http.StatusConflict, "Falló una operación 'test' del JSON Patch: " + err.Error()}
+			}
+			return models.Series{}, &patchError{http.StatusUnprocessableEntity, "No se pudo aplicar el JSON Patch: " + err.Error()}
+		}
+	default:
+		return models.Series{}, &patchError{http.StatusUnsupportedMediaType,
+			"Content-Type no soportado: use " + contentTypeMergePatch + " o " + contentTypeJSONPatch}
+	}
+
+	var result models.Series
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return models.Series{}, &patchError{http.StatusUnprocessableEntity, "El parche produce una serie inválida: " + err.Error()}
+	}
+	result.ID, result.UserID, result.Version = s.ID, s.UserID, s.Version
+	return result, nil
+}
+
+// PatchSeries godoc
+// @Summary      Modificar parcialmente una serie
+// @Description  Modifica solo los campos indicados de una serie. Acepta JSON Merge Patch (RFC 7396, Content-Type application/merge-patch+json o application/json), por ejemplo {"title": "Nuevo título"}, o JSON Patch (RFC 6902, Content-Type application/json-patch+json), por ejemplo [{"op": "replace", "path": "/totalEpisodes", "value": 24}]. La serie resultante se valida igual que en PUT (incluidas las transiciones de estado). Los campos id, userId y version no se pueden modificar.
+// @Tags         Series
+// @Accept       json,application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie a modificar" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Param        patch body models.SeriesMergePatch true "Parche JSON Merge Patch (o un arreglo de operaciones JSON Patch)"
+// @Success      200 {object} models.Series "Serie modificada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "Parche mal formado o ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      409 {object} ErrorResponse "Falló una operación 'test' del JSON Patch"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      415 {object} ErrorResponse "Content-Type no soportado"
+// @Failure      422 {object} ErrorResponse "El parche no se puede aplicar o la serie resultante es inválida (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al modificar la serie"
+// @Router       /series/{id} [patch]
+func (h *SeriesHandler) PatchSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, "Content-Type no soportado: use "+contentTypeMergePatch+" o "+contentTypeJSONPatch)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchBytes))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+
+	// Sin If-Match del cliente, se condiciona la escritura a la versión leída para
+	// que el parche nunca se aplique sobre datos desactualizados, reintentando si
+	// otra solicitud modificó la serie entretanto.
+	clientIfMatch := r.Header.Get("If-Match") != "" && r.Header.Get("If-Match") != "*"
+	for attempt := 1; ; attempt++ {
+		current, err := h.repo.Get(r.Context(), userID, id)
+		if err != nil {
+			writeRepositoryError(w, err, "Serie no encontrada", "Error buscando la serie: ")
+			return
+		}
+
+		patched, err := applySeriesPatch(current, mediaType, body)
+		if err != nil {
+			var pErr *patchError
+			if errors.As(err, &pErr) {
+				writeError(w, pErr.status, pErr.message)
+			} else {
+				writeError(w, http.StatusInternalServerError, "Error aplicando el parche: "+err.Error())
+			}
+			return
+		}
+		if errs := validateSeries(&patched, false); len(errs) > 0 {
+			writeValidationError(w, errs)
+			return
+		}
+
+		ctx := r.Context()
+		if !clientIfMatch {
+			ctx = repository.WithIfMatch(ctx, current.Version)
+		}
+		err = h.repo.Update(ctx, &patched)
+		if errors.Is(err, repository.ErrPreconditionFailed) && !clientIfMatch && attempt < patchAttempts {
+			continue
+		}
+		if err != nil {
+			writeRepositoryError(w, err, "Serie no encontrada", "Error modificando la serie: ")
+			return
+		}
+
+		writeSeries(w, http.StatusOK, patched)
+		return
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"testing"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+func TestPatchSeries(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        int
+		check       func(t *testing.T, s models.Series)
+	}{
+		{
+			name:        "merge patch",
+			contentType: contentTypeMergePatch,
+			body:        `{"title": "Sousou no Frieren", "synopsis": null}`,
+			want:        http.StatusOK,
+			check: func(t *testing.T, s models.Series) {
+				if s.Title != "Sousou no Frieren" || s.TotalEpisodes != 28 || s.Synopsis != "" {
+					t.Errorf("serie = %+v, se esperaba el título nuevo, 28 episodios y sin sinopsis", s)
+				}
+			},
+		},
+		{
+			name:        "json patch sobre campos vacíos",
+			contentType: contentTypeJSONPatch,
+			body: `[{"op": "replace", "path": "/altTitles", "value": ["Sousou no Frieren"]},
+				{"op": "replace", "path": "/seasonEpisodes", "value": [16, 12]}]`,
+			want: http.StatusOK,
+			check: func(t *testing.T, s models.Series) {
+				if !slices.Equal(s.AltTitles, models.Titles{"Sousou no Frieren"}) || s.Seasons != 2 || s.TotalEpisodes != 28 {
+					t.Errorf("serie = %+v, se esperaban el título alternativo y 2 temporadas", s)
+				}
+			},
+		},
+		{
+			name:        "operación test fallida",
+			contentType: contentTypeJSONPatch,
+			body:        `[{"op": "test", "path": "/title", "value": "Otra"}, {"op": "replace", "path": "/title", "value": "X"}]`,
+			want:        http.StatusConflict,
+		},
+		{
+			name:        "ruta inexistente",
+			contentType: contentTypeJSONPatch,
+			body:        `[{"op": "replace", "path": "/noExiste/a", "value": 1}]`,
+			want:        http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "serie resultante inválida",
+			contentType: contentTypeMergePatch,
+			body:        `{"title": ""}`,
+			want:        http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "Content-Type no soportado",
+			contentType: "text/plain",
+			body:        `{"title": "X"}`,
+			want:        http.StatusUnsupportedMediaType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemorySeriesRepository()
+			router := newTestRouter(repo)
+			serie := createSeries(t, repo, testUserID, models.Series{Title: "Frieren", TotalEpisodes: 28, Synopsis: "Elfa"})
+
+			rec := do(t, router, http.MethodPatch, "/series/"+strconv.Itoa(serie.ID), tt.body, "Content-Type", tt.contentType)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, se esperaba %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.check != nil {
+				var patched models.Series
+				decode(t, rec, &patched)
+				tt.check(t, patched)
+			}
+		})
+	}
+}
+
+// racingRepo modifica la serie la primera vez que PatchSeries la lee, como si
+// otra solicitud se adelantara entre la lectura y la escritura del parche.
+type racingRepo struct {
+	repository.SeriesRepository
+	raced bool
+}
+
+// Get implementa repository.SeriesRepository.
+func (r *racingRepo) Get(ctx context.Context, userID, id int) (models.Series, error) {
+	s, err := r.SeriesRepository.Get(ctx, userID, id)
+	if err == nil && !r.raced {
+		r.raced = true
+		_, err = r.SeriesRepository.IncrementEpisode(ctx, userID, id)
+	}
+	return s, err
+}
+
+func TestPatchSeriesConcurrentModification(t *testing.T) {
+	repo := &racingRepo{SeriesRepository: repository.NewMemorySeriesRepository()}
+	router := newTestRouter(repo)
+	serie := createSeries(t, repo, testUserID, models.Series{Title: "Frieren", TotalEpisodes: 28})
+	path := "/series/" + strconv.Itoa(serie.ID)
+
+	// Sin If-Match el parche se vuelve a aplicar sobre la versión nueva y
+	// conserva el episodio que vio la otra solicitud
+	rec := do(t, router, http.MethodPatch, path, `{"title": "Sousou no Frieren"}`, "Content-Type", contentTypeMergePatch)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, se esperaba 200: %s", rec.Code, rec.Body)
+	}
+	var patched models.Series
+	decode(t, rec, &patched)
+	if patched.Title != "Sousou no Frieren" || patched.LastEpisodeWatched != 1 || patched.Version != 3 {
+		t.Errorf("serie = %q en el episodio %d (versión %d); se esperaba el título nuevo en el 1 (versión 3)",
+			patched.Title, patched.LastEpisodeWatched, patched.Version)
+	}
+
+	// Con If-Match del cliente no se reintenta
+	repo.raced = false
+	rec = do(t, router, http.MethodPatch, path, `{"title": "Frieren"}`,
+		"Content-Type", contentTypeMergePatch, "If-Match", `"3"`)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("If-Match desactualizado: status = %d, se esperaba 412: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"lab6/models"
+)
+
+const (
+	// defaultPageSize es el tamaño de página usado cuando no se indica "pageSize".
+	defaultPageSize = 100
+	// maxPageSize limita el tamaño de página para evitar respuestas gigantes.
+	maxPageSize = 500
+	// maxTagFilters limita la cantidad de parámetros "tag" de un listado.
+	maxTagFilters = 20
+)
+
+// parseSeriesQuery construye un models.SeriesQuery a partir de los query params
+// de la solicitud, validando tipos y valores permitidos.
+func parseSeriesQuery(values url.Values) (models.SeriesQuery, error) {
+	q := models.SeriesQuery{
+		Search:   strings.TrimSpace(values.Get("search")),
+		Status:   models.SeriesStatus(values.Get("status")),
+		Page:     1,
+		PageSize: defaultPageSize,
+	}
+
+	if q.Status != "" && !q.Status.Valid() {
+		return q, models.ValidateStatus(q.Status)
+	}
+
+	var err error
+	if q.MinRanking, err = parseOptionalInt(values, "minRanking"); err != nil {
+		return q, err
+	}
+	if q.MaxRanking, err = parseOptionalInt(values, "maxRanking"); err != nil {
+		return q, err
+	}
+	if q.MinRanking != nil && q.MaxRanking != nil && *q.MinRanking > *q.MaxRanking {
+		return q, fmt.Errorf("'minRanking' no puede ser mayor que 'maxRanking'")
+	}
+
+	// "tag" puede repetirse: se devuelven las series que tienen todas las etiquetas
+	tags := values["tag"]
+	if len(tags) > maxTagFilters {
+		return q, fmt.Errorf("se admiten hasta %d filtros 'tag'", maxTagFilters)
+	}
+	for _, raw := range tags {
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return q, fmt.Errorf("'tag' debe ser el ID (entero) de una etiqueta: %q", raw)
+		}
+		q.TagIDs = append(q.TagIDs, id)
+	}
+	if q.ListID, err = parseOptionalInt(values, "list"); err != nil {
+		return q, err
+	}
+
+	// "sort" indica la dirección. Si no se especifica "sortBy" pero sí "sort",
+	// se ordena por ranking (comportamiento esperado por el frontend).
+	switch sort := strings.ToLower(values.Get("sort")); sort {
+	case "", "asc":
+	case "desc":
+		q.SortDesc = true
+	default:
+		return q, fmt.Errorf("valor de 'sort' inválido: %q (use 'asc' o 'desc')", sort)
+	}
+	q.SortBy = values.Get("sortBy")
+	if q.SortBy == "" {
+		q.SortBy = "id"
+		if values.Get("sort") != "" {
+			q.SortBy = "ranking"
+		}
+	}
+	if _, ok := models.SeriesSortFields[q.SortBy]; !ok {
+		return q, fmt.Errorf("valor de 'sortBy' inválido: %q", q.SortBy)
+	}
+
+	if q.Page, q.PageSize, err = parsePagination(values); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// parsePagination lee los query params "page" (por defecto 1) y "pageSize"
+// (por defecto defaultPageSize, máximo maxPageSize).
+func parsePagination(values url.Values) (page, pageSize int, err error) {
+	page, pageSize = 1, defaultPageSize
+	if p, err := parseOptionalInt(values, "page"); err != nil {
+		return 0, 0, err
+	} else if p != nil {
+		if *p < 1 {
+			return 0, 0, fmt.Errorf("'page' debe ser mayor o igual a 1")
+		}
+		page = *p
+	}
+	if size, err := parseOptionalInt(values, "pageSize"); err != nil {
+		return 0, 0, err
+	} else if size != nil {
+		if *size < 1 || *size > maxPageSize {
+			return 0, 0, fmt.Errorf("'pageSize' debe estar entre 1 y %d", maxPageSize)
+		}
+		pageSize = *size
+	}
+	return page, pageSize, nil
+}
+
+// parseOptionalInt lee un query param entero. Devuelve nil si no está presente.
+func parseOptionalInt(values url.Values, name string) (*int, error) {
+	raw := values.Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' debe ser un número entero: %q", name, raw)
+	}
+	return &n, nil
+}
+
+// setPaginationHeaders añade los headers X-Total-Count y Link (RFC 8288) con
+// los enlaces first, prev, next y last de la página actual.
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, page, pageSize int, total int64) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+
+	lastPage := int((total + int64(pageSize) - 1) / int64(pageSize))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	pageURL := func(n int) string {
+		values := r.URL.Query()
+		values.Set("page", strconv.Itoa(n))
+		values.Set("pageSize", strconv.Itoa(pageSize))
+		u := url.URL{Path: r.URL.Path, RawQuery: values.Encode()}
+		return u.String()
+	}
+
+	links := []string{fmt.Sprintf(`<%s>; rel="first"`, pageURL(1))}
+	if page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(min(page-1, lastPage))))
+	}
+	if page < lastPage {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(page+1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(lastPage)))
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"lab6/models"
+)
+
+// GetSeriesReview godoc
+// @Summary      Obtener mi reseña
+// @Description  Devuelve la puntuación personal y las notas en Markdown del usuario autenticado sobre la serie.
+// @Tags         Reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Success      200 {object} models.Review "Reseña del usuario"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada o sin reseña"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener la reseña"
+// @Router       /series/{id}/review [get]
+func (h *SeriesHandler) GetSeriesReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := h.repo.GetReview(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error obteniendo la reseña: ")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, review)
+}
+
+// SaveSeriesReview godoc
+// @Summary      Crear o reemplazar mi reseña
+// @Description  Guarda la puntuación personal (1 a 10, admite medios puntos) y las notas en Markdown del usuario autenticado sobre la serie. Reemplaza la reseña anterior por completo; se requiere al menos uno de los dos campos. La reseña no cambia la versión (ETag) de la serie.
+// @Tags         Reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        review body models.ReviewInput true "Puntuación y notas"
+// @Success      200 {object} models.Review "Reseña reemplazada"
+// @Success      201 {object} models.Review "Reseña creada"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      422 {object} ErrorResponse "Puntuación o notas inválidas (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al guardar la reseña"
+// @Router       /series/{id}/review [put]
+func (h *SeriesHandler) SaveSeriesReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var in models.ReviewInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if errs := validateReview(&in); len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	review := models.Review{SeriesID: id, UserID: userID, Score: in.Score, Body: in.Body}
+	created, err := h.repo.SaveReview(r.Context(), &review)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error guardando la reseña: ")
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, review)
+}
+
+// DeleteSeriesReview godoc
+// @Summary      Eliminar mi reseña
+// @Description  Elimina la puntuación y las notas del usuario autenticado sobre la serie.
+// @Tags         Reviews
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Success      204 "Reseña eliminada"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada o sin reseña"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al eliminar la reseña"
+// @Router       /series/{id}/review [delete]
+func (h *SeriesHandler) DeleteSeriesReview(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.repo.DeleteReview(r.Context(), userID, id); err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error eliminando la reseña: ")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
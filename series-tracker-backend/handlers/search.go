@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// defaultSearchLimit es la cantidad de resultados de "GET /api/series/search" si no se indica "limit".
+	defaultSearchLimit = 20
+	// maxSearchLimit es el máximo aceptado en "limit".
+	maxSearchLimit = 100
+	// maxSearchQueryLength es la longitud máxima de "q".
+	maxSearchQueryLength = 200
+)
+
+// SearchSeries godoc
+// @Summary      Buscar series por título
+// @Description  Busca en el título y los títulos alternativos (romaji, nativo, inglés...) de las series del usuario y devuelve las coincidencias de la más a la menos relevante. Coinciden las palabras completas, los comienzos de palabra ('shin' encuentra 'Shingeki no Kyojin') y las palabras con errores de escritura ('atack titan' encuentra 'Attack on Titan'), sin distinguir mayúsculas ni acentos. El orden de las palabras no importa.
+// @Description  La búsqueda usa el índice de texto completo del motor (FULLTEXT en MySQL, tsvector en PostgreSQL y FTS5 en SQLite) y un índice de trigramas para los errores de escritura (pg_trgm en PostgreSQL y FTS5 en SQLite; en MySQL se comparan los títulos del usuario). Las series de la papelera no se incluyen.
+// @Tags         Series
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        q     query string true  "Texto a buscar (hasta 200 caracteres)" example(attack titan)
+// @Param        limit query int    false "Cantidad máxima de resultados (máximo 100)" default(20)
+// @Success      200 {array}  models.SearchResult "Series encontradas, de mayor a menor relevancia"
+// @Failure      400 {object} ErrorResponse "Falta 'q' o los query params son inválidos"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al buscar series"
+// @Router       /series/search [get]
+func (h *SeriesHandler) SearchSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	query := strings.TrimSpace(values.Get("q"))
+	limit, err := parseOptionalInt(values, "limit")
+	switch {
+	case err != nil:
+	case query == "":
+		err = errors.New("'q' es obligatorio")
+	case utf8.RuneCountInString(query) > maxSearchQueryLength:
+		err = fmt.Errorf("'q' admite hasta %d caracteres", maxSearchQueryLength)
+	case limit != nil && (*limit < 1 || *limit > maxSearchLimit):
+		err = fmt.Errorf("'limit' debe estar entre 1 y %d", maxSearchLimit)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: "+err.Error())
+		return
+	}
+	searchLimit := defaultSearchLimit
+	if limit != nil {
+		searchLimit = *limit
+	}
+
+	results, err := h.repo.Search(r.Context(), userID, query, searchLimit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error buscando series: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
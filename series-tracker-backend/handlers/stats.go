@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// Cantidad de períodos por defecto y máxima de cada serie temporal de GET /api/stats.
+const (
+	defaultStatsDays   = 30
+	maxStatsDays       = 366
+	defaultStatsWeeks  = 12
+	maxStatsWeeks      = 104
+	defaultStatsMonths = 12
+	maxStatsMonths     = 120
+)
+
+// StatsHandler agrupa los handlers de estadísticas de visualización.
+type StatsHandler struct {
+	stats repository.StatsRepository
+}
+
+// NewStatsHandler crea un StatsHandler que usa el repositorio indicado.
+func NewStatsHandler(stats repository.StatsRepository) *StatsHandler {
+	return &StatsHandler{stats: stats}
+}
+
+// parseStatsQuery lee los query params "days", "weeks" y "months".
+func parseStatsQuery(values url.Values, now time.Time) (models.StatsQuery, error) {
+	q := models.StatsQuery{Now: now}
+	params := []struct {
+		name     string
+		def, max int
+		dst      *int
+	}{
+		{"days", defaultStatsDays, maxStatsDays, &q.Days},
+		{"weeks", defaultStatsWeeks, maxStatsWeeks, &q.Weeks},
+		{"months", defaultStatsMonths, maxStatsMonths, &q.Months},
+	}
+	for _, p := range params {
+		n, err := parseOptionalInt(values, p.name)
+		if err != nil {
+			return q, err
+		}
+		*p.dst = p.def
+		if n != nil {
+			if *n < 1 || *n > p.max {
+				return q, fmt.Errorf("'%s' debe estar entre 1 y %d", p.name, p.max)
+			}
+			*p.dst = *n
+		}
+	}
+	return q, nil
+}
+
+// GetStats godoc
+// @Summary      Estadísticas de visualización
+// @Description  Devuelve estadísticas del usuario autenticado calculadas en la base de datos: totales, series y progreso medio por estado, episodios vistos por día, semana (desde el lunes) y mes según el historial, tiempo medio para completar una serie y rachas de días consecutivos viendo episodios. Las fechas se agrupan en UTC.
+// @Tags         Stats
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        days   query int false "Días incluidos en 'episodesPerDay' (máximo 366)" default(30)
+// @Param        weeks  query int false "Semanas incluidas en 'episodesPerWeek' (máximo 104)" default(12)
+// @Param        months query int false "Meses incluidos en 'episodesPerMonth' (máximo 120)" default(12)
+// @Success      200 {object} models.Stats "Estadísticas del usuario"
+// @Failure      400 {object} ErrorResponse "Query params inválidos"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al calcular las estadísticas"
+// @Router       /stats [get]
+func (h *StatsHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	q, err := parseStatsQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: "+err.Error())
+		return
+	}
+
+	stats, err := h.stats.Stats(r.Context(), userID, q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error calculando las estadísticas: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
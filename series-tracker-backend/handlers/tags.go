@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"lab6/models"
+	"lab6/repository"
+)
+
+// CollectionHandler agrupa los handlers de etiquetas y listas personalizadas.
+type CollectionHandler struct {
+	collections repository.CollectionRepository
+}
+
+// NewCollectionHandler crea un CollectionHandler que usa el repositorio indicado.
+func NewCollectionHandler(collections repository.CollectionRepository) *CollectionHandler {
+	return &CollectionHandler{collections: collections}
+}
+
+// writeCollectionError responde 409 con conflictMessage si err es
+// repository.ErrConflict (nombre en uso) y, si no, como writeRepositoryError.
+func writeCollectionError(w http.ResponseWriter, err error, notFoundMessage, conflictMessage, prefix string) {
+	if errors.Is(err, repository.ErrConflict) {
+		writeError(w, http.StatusConflict, conflictMessage)
+		return
+	}
+	writeRepositoryError(w, err, notFoundMessage, prefix)
+}
+
+// ListTags godoc
+// @Summary      Listar etiquetas
+// @Description  Devuelve las etiquetas (géneros o categorías) del usuario autenticado, ordenadas por nombre, con la cantidad de series de cada una.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200 {array}  models.Tag "Etiquetas del usuario"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener las etiquetas"
+// @Router       /tags [get]
+func (h *CollectionHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := h.collections.ListTags(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error obteniendo las etiquetas: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// CreateTag godoc
+// @Summary      Crear una etiqueta
+// @Description  Crea una etiqueta del usuario autenticado. El nombre es único por usuario sin distinguir mayúsculas.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        tag body models.TagInput true "Nombre de la etiqueta"
+// @Success      201 {object} models.Tag "Etiqueta creada"
+// @Failure      400 {object} ErrorResponse "JSON mal formado"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      409 {object} ErrorResponse "Ya existe una etiqueta con ese nombre"
+// @Failure      422 {object} ErrorResponse "Nombre inválido (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al crear la etiqueta"
+// @Router       /tags [post]
+func (h *CollectionHandler) CreateTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var in models.TagInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if errs := validateTag(&in); len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	tag := models.Tag{UserID: userID, Name: in.Name}
+	if err := h.collections.CreateTag(r.Context(), &tag); err != nil {
+		writeCollectionError(w, err, "Etiqueta no encontrada", "Ya existe una etiqueta con ese nombre", "Error creando la etiqueta: ")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, tag)
+}
+
+// RenameTag godoc
+// @Summary      Renombrar una etiqueta
+// @Description  Cambia el nombre de una etiqueta del usuario autenticado; las series etiquetadas la conservan.
+// @Tags         Tags
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id  path int true "ID de la etiqueta" example(1)
+// @Param        tag body models.TagInput true "Nuevo nombre"
+// @Success      200 {object} models.Tag "Etiqueta renombrada"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Etiqueta no encontrada"
+// @Failure      409 {object} ErrorResponse "Ya existe una etiqueta con ese nombre"
+// @Failure      422 {object} ErrorResponse "Nombre inválido (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al renombrar la etiqueta"
+// @Router       /tags/{id} [put]
+func (h *CollectionHandler) RenameTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var in models.TagInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if errs := validateTag(&in); len(errs) > 0 {
+		writeValidationError(w, errs)
+		return
+	}
+
+	tag := models.Tag{ID: id, UserID: userID, Name: in.Name}
+	if err := h.collections.RenameTag(r.Context(), &tag); err != nil {
+		writeCollectionError(w, err, "Etiqueta no encontrada", "Ya existe una etiqueta con ese nombre", "Error renombrando la etiqueta: ")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tag)
+}
+
+// DeleteTag godoc
+// @Summary      Eliminar una etiqueta
+// @Description  Elimina una etiqueta del usuario autenticado y la quita de todas sus series (las series no se eliminan).
+// @Tags         Tags
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la etiqueta" example(1)
+// @Success      204 "Etiqueta eliminada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Etiqueta no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al eliminar la etiqueta"
+// @Router       /tags/{id} [delete]
+func (h *CollectionHandler) DeleteTag(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.collections.DeleteTag(r.Context(), userID, id); err != nil {
+		writeRepositoryError(w, err, "Etiqueta no encontrada", "Error eliminando la etiqueta: ")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSeriesTags godoc
+// @Summary      Etiquetas de una serie
+// @Description  Devuelve las etiquetas asignadas a la serie, ordenadas por nombre.
+// @Tags         Tags
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Success      200 {array}  models.Tag "Etiquetas de la serie"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener las etiquetas"
+// @Router       /series/{id}/tags [get]
+func (h *CollectionHandler) GetSeriesTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	tags, err := h.collections.SeriesTags(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error obteniendo las etiquetas: ")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tags)
+}
+
+// AttachSeriesTag godoc
+// @Summary      Etiquetar una serie
+// @Description  Asigna la etiqueta a la serie. Es idempotente: si la serie ya la tenía no cambia nada.
+// @Tags         Tags
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id    path int true "ID de la Serie" example(1)
+// @Param        tagId path int true "ID de la etiqueta" example(2)
+// @Success      204 "Etiqueta asignada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie o etiqueta no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al asignar la etiqueta"
+// @Router       /series/{id}/tags/{tagId} [put]
+func (h *CollectionHandler) AttachSeriesTag(w http.ResponseWriter, r *http.Request) {
+	h.changeSeriesTag(w, r, h.collections.AttachTag, "Error asignando la etiqueta: ")
+}
+
+// DetachSeriesTag godoc
+// @Summary      Quitar una etiqueta de una serie
+// @Description  Quita la etiqueta de la serie. Es idempotente: si la serie no la tenía no cambia nada.
+// @Tags         Tags
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id    path int true "ID de la Serie" example(1)
+// @Param        tagId path int true "ID de la etiqueta" example(2)
+// @Success      204 "Etiqueta quitada"
+// @Failure      400 {object} ErrorResponse "ID inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie o etiqueta no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al quitar la etiqueta"
+// @Router       /series/{id}/tags/{tagId} [delete]
+func (h *CollectionHandler) DetachSeriesTag(w http.ResponseWriter, r *http.Request) {
+	h.changeSeriesTag(w, r, h.collections.DetachTag, "Error quitando la etiqueta: ")
+}
+
+// changeSeriesTag lee los IDs de serie y etiqueta de la URL y aplica change.
+func (h *CollectionHandler) changeSeriesTag(w http.ResponseWriter, r *http.Request,
+	change func(ctx context.Context, userID, seriesID, tagID int) error, prefix string) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+	tagID, ok := pathID(w, r, "tagId")
+	if !ok {
+		return
+	}
+
+	if err := change(r.Context(), userID, id, tagID); err != nil {
+		writeRepositoryError(w, err, "Serie o etiqueta no encontrada", prefix)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
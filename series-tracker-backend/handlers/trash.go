@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"lab6/models"
+	"lab6/repository"
+	"lab6/storage"
+)
+
+// TrashHandler agrupa los handlers de la papelera de series y su purga periódica.
+type TrashHandler struct {
+	repo      repository.SeriesRepository
+	blobs     storage.BlobStore
+	retention time.Duration
+}
+
+// NewTrashHandler crea un TrashHandler que usa el repositorio y el almacenamiento
+// de portadas indicados. Las series eliminadas se conservan durante retention.
+func NewTrashHandler(repo repository.SeriesRepository, blobs storage.BlobStore, retention time.Duration) *TrashHandler {
+	return &TrashHandler{repo: repo, blobs: blobs, retention: retention}
+}
+
+// ListTrash godoc
+// @Summary      Listar la papelera
+// @Description  Devuelve las series eliminadas del usuario, de la más reciente a la más antigua, con la fecha de eliminación y la fecha a partir de la cual se purgan definitivamente.
+// @Tags         Trash
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Success      200 {array}  models.TrashedSeries "Series en la papelera"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al leer la papelera"
+// @Router       /trash [get]
+func (h *TrashHandler) ListTrash(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	series, err := h.repo.Trash(r.Context(), userID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error obteniendo la papelera: "+err.Error())
+		return
+	}
+
+	trashed := make([]models.TrashedSeries, len(series))
+	for i, s := range series {
+		deletedAt := s.DeletedAt.Time.UTC()
+		trashed[i] = models.TrashedSeries{Series: s, DeletedAt: deletedAt, PurgeAt: deletedAt.Add(h.retention)}
+	}
+	writeJSON(w, http.StatusOK, trashed)
+}
+
+// RestoreSeries godoc
+// @Summary      Restaurar una serie de la papelera
+// @Description  Saca la serie de la papelera con su historial, reseña, votos, etiquetas y listas. La versión de la serie aumenta.
+// @Tags         Trash
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Success      200 {object} models.Series "Serie restaurada"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "La serie no está en la papelera"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al restaurar la serie"
+// @Router       /series/{id}/restore [post]
+func (h *TrashHandler) RestoreSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	serie, err := h.repo.Restore(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "La serie no está en la papelera", "Error restaurando la serie: ")
+		return
+	}
+
+	writeSeries(w, http.StatusOK, serie)
+}
+
+// Purge elimina definitivamente las series que llevan en la papelera más que el
+// tiempo de retención, junto con las imágenes de sus portadas subidas.
+func (h *TrashHandler) Purge(ctx context.Context) error {
+	purged, err := h.repo.Purge(ctx, time.Now().Add(-h.retention))
+	// Aunque falle un lote, las series de los anteriores ya se eliminaron
+	for _, s := range purged {
+		deleteCoverBlobs(ctx, h.blobs, s.CoverKey)
+	}
+	if len(purged) > 0 {
+		log.Printf("Papelera: %d series eliminadas definitivamente", len(purged))
+	}
+	if err != nil {
+		return fmt.Errorf("purgando la papelera: %w", err)
+	}
+	return nil
+}
+
+// RunPurges ejecuta las purgas indicadas (papelera, sesiones expiradas) al
+// iniciar y luego cada interval, hasta que se cancela ctx. El error de una
+// purga se registra en el log y no impide las demás.
+func RunPurges(ctx context.Context, interval time.Duration, purges ...func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, purge := range purges {
+			if err := purge(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Error en la purga periódica: %v", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
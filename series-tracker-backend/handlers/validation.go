@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"lab6/covers"
+	"lab6/models"
+)
+
+const (
+	// maxTitleLength es la longitud máxima del título (columna VARCHAR(255)).
+	maxTitleLength = 255
+	// maxAltTitles limita la cantidad de títulos alternativos de una serie.
+	maxAltTitles = 20
+	// maxEpisodes es un límite razonable para la cantidad de episodios de una serie.
+	maxEpisodes = 100000
+	// maxSeasons limita la cantidad de temporadas (la lista se guarda en una columna VARCHAR(2000)).
+	maxSeasons = 100
+	// maxReviewLength es la longitud máxima de las notas de una reseña.
+	maxReviewLength = 20000
+	// maxCoverURLLength es la longitud máxima de la URL de portada (columna VARCHAR(2048)).
+	maxCoverURLLength = 2048
+	// maxSynopsisLength es la longitud máxima de la sinopsis.
+	maxSynopsisLength = 10000
+	// maxStudioLength es la longitud máxima del estudio (columna VARCHAR(255)).
+	maxStudioLength = 255
+	// minYear y maxYear acotan los años de emisión aceptados.
+	minYear = 1900
+	maxYear = 2100
+	// maxTagNameLength es la longitud máxima del nombre de una etiqueta.
+	maxTagNameLength = 50
+	// maxListNameLength y maxListDescriptionLength limitan el nombre y la descripción de una lista.
+	maxListNameLength        = 100
+	maxListDescriptionLength = 1000
+)
+
+// FieldError describe un problema de validación en un campo concreto del cuerpo
+// de la solicitud, para que el cliente pueda señalarlo en el formulario.
+// @Description Error de validación asociado a un campo.
+type FieldError struct {
+	// Field es el nombre del campo en el JSON de la solicitud.
+	// example: "lastEpisodeWatched"
+	Field string `json:"field"`
+	// Message describe el problema.
+	// example: "no puede ser mayor que 'totalEpisodes' (12)"
+	Message string `json:"message"`
+}
+
+// validator acumula los errores de validación de un cuerpo de solicitud.
+type validator struct {
+	errors []FieldError
+}
+
+// check registra un error en field si ok es falso.
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.add(field, message)
+	}
+}
+
+// add registra un error en field, salvo que ese campo ya tenga uno.
+func (v *validator) add(field, message string) {
+	for _, e := range v.errors {
+		if e.Field == field {
+			return
+		}
+	}
+	v.errors = append(v.errors, FieldError{Field: field, Message: message})
+}
+
+// writeValidationError responde 422 con la lista de errores por campo.
+func writeValidationError(w http.ResponseWriter, errs []FieldError) {
+	writeJSON(w, http.StatusUnprocessableEntity, validationErrorResponse(errs))
+}
+
+// validationErrorResponse devuelve el cuerpo de un error de validación con el detalle por campo.
+func validationErrorResponse(errs []FieldError) ErrorResponse {
+	return ErrorResponse{Message: "La solicitud contiene datos inválidos", Errors: errs}
+}
+
+// printable indica si s no contiene caracteres de control.
+func printable(s string) bool {
+	return strings.IndexFunc(s, unicode.IsControl) < 0
+}
+
+// validateSeries normaliza y valida los datos de una serie recibidos en
+// "POST /api/series" o "PUT /api/series/{id}". Al crear (creating) el estado
+// es opcional y por defecto 'Plan to Watch'.
+func validateSeries(s *models.Series, creating bool) []FieldError {
+	var v validator
+
+	s.Title = strings.TrimSpace(s.Title)
+	v.check(s.Title != "", "title", "es obligatorio")
+	v.check(printable(s.Title), "title", "no puede contener caracteres de control")
+	v.check(utf8.RuneCountInString(s.Title) <= maxTitleLength, "title", fmt.Sprintf("admite hasta %d caracteres", maxTitleLength))
+	validateAltTitles(&v, s)
+
+	if s.Status == "" && creating {
+		s.Status = models.StatusPlanToWatch
+	}
+	v.check(s.Status != "", "status", "es obligatorio")
+	if err := models.ValidateStatus(s.Status); err != nil {
+		v.add("status", err.Error())
+	}
+
+	// Con temporadas, el total se calcula a partir de ellas
+	v.check(len(s.SeasonEpisodes) <= maxSeasons, "seasonEpisodes", fmt.Sprintf("admite hasta %d temporadas", maxSeasons))
+	for _, n := range s.SeasonEpisodes {
+		v.check(n >= 1 && n <= maxEpisodes, "seasonEpisodes", fmt.Sprintf("cada temporada debe tener entre 1 y %d episodios", maxEpisodes))
+	}
+	v.check(s.SeasonEpisodes.Total() <= maxEpisodes, "seasonEpisodes", fmt.Sprintf("la suma de episodios no puede ser mayor que %d", maxEpisodes))
+	s.SyncSeasons()
+
+	v.check(s.TotalEpisodes >= 0, "totalEpisodes", "no puede ser negativo")
+	v.check(s.TotalEpisodes <= maxEpisodes, "totalEpisodes", fmt.Sprintf("no puede ser mayor que %d", maxEpisodes))
+	v.check(s.LastEpisodeWatched >= 0, "lastEpisodeWatched", "no puede ser negativo")
+	v.check(s.LastEpisodeWatched <= maxEpisodes, "lastEpisodeWatched", fmt.Sprintf("no puede ser mayor que %d", maxEpisodes))
+	if s.TotalEpisodes > 0 {
+		v.check(s.LastEpisodeWatched <= s.TotalEpisodes, "lastEpisodeWatched",
+			fmt.Sprintf("no puede ser mayor que 'totalEpisodes' (%d)", s.TotalEpisodes))
+	}
+
+	validateMetadata(&v, s)
+
+	return v.errors
+}
+
+// validateAltTitles normaliza y valida los títulos alternativos: quita los
+// vacíos y los repetidos (incluido el título principal), sin distinguir mayúsculas.
+func validateAltTitles(v *validator, s *models.Series) {
+	seen := map[string]bool{titleKey(s.Title): true}
+	var titles models.Titles
+	for _, title := range s.AltTitles {
+		title = strings.TrimSpace(title)
+		if title == "" || seen[titleKey(title)] {
+			continue
+		}
+		seen[titleKey(title)] = true
+		v.check(printable(title), "altTitles", "no pueden contener caracteres de control")
+		v.check(utf8.RuneCountInString(title) <= maxTitleLength, "altTitles", fmt.Sprintf("cada título admite hasta %d caracteres", maxTitleLength))
+		titles = append(titles, title)
+	}
+	v.check(len(titles) <= maxAltTitles, "altTitles", fmt.Sprintf("admite hasta %d títulos", maxAltTitles))
+	s.AltTitles = titles
+}
+
+// validateMetadata normaliza y valida los datos descriptivos de la serie
+// (portada, sinopsis, años, formato, estudio y estado de emisión).
+func validateMetadata(v *validator, s *models.Series) {
+	s.CoverURL = strings.TrimSpace(s.CoverURL)
+	if s.CoverURL != "" {
+		v.check(utf8.RuneCountInString(s.CoverURL) <= maxCoverURLLength, "coverUrl", fmt.Sprintf("admite hasta %d caracteres", maxCoverURLLength))
+		v.check(validCoverURL(s.CoverURL), "coverUrl", "debe ser una URL absoluta http o https o la ruta de una portada subida")
+	}
+
+	s.Synopsis = strings.TrimSpace(s.Synopsis)
+	v.check(printableText(s.Synopsis), "synopsis", "no puede contener caracteres de control")
+	v.check(utf8.RuneCountInString(s.Synopsis) <= maxSynopsisLength, "synopsis", fmt.Sprintf("admite hasta %d caracteres", maxSynopsisLength))
+
+	yearMessage := fmt.Sprintf("debe estar entre %d y %d", minYear, maxYear)
+	if s.StartYear != nil {
+		v.check(*s.StartYear >= minYear && *s.StartYear <= maxYear, "startYear", yearMessage)
+	}
+	if s.EndYear != nil {
+		v.check(*s.EndYear >= minYear && *s.EndYear <= maxYear, "endYear", yearMessage)
+		if s.StartYear != nil {
+			v.check(*s.EndYear >= *s.StartYear, "endYear", "no puede ser anterior a 'startYear'")
+		}
+	}
+
+	v.check(s.MediaType.Valid(), "mediaType", "formato inválido (use uno de: "+models.MediaTypeList()+")")
+
+	s.Studio = strings.TrimSpace(s.Studio)
+	v.check(printable(s.Studio), "studio", "no puede contener caracteres de control")
+	v.check(utf8.RuneCountInString(s.Studio) <= maxStudioLength, "studio", fmt.Sprintf("admite hasta %d caracteres", maxStudioLength))
+
+	v.check(s.AiringStatus.Valid(), "airingStatus", "estado de emisión inválido (use uno de: "+models.AiringStatusList()+")")
+}
+
+// validCoverURL indica si raw es una URL absoluta http o https con host o la
+// ruta de una portada subida (ver covers.URL), que se conserva al editar la serie.
+func validCoverURL(raw string) bool {
+	if _, _, ok := covers.ParseURL(raw); ok {
+		return true
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateStatusUpdate valida el cuerpo de "PATCH /api/series/{id}/status".
+func validateStatusUpdate(u models.StatusUpdate) []FieldError {
+	var v validator
+	if u.Status == "" {
+		v.add("status", "es obligatorio")
+	} else if err := models.ValidateStatus(u.Status); err != nil {
+		v.add("status", err.Error())
+	}
+	return v.errors
+}
+
+// printableText indica si s no contiene caracteres de control, salvo saltos de
+// línea y tabulaciones (texto de varias líneas, como el Markdown de una reseña).
+func printableText(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsControl(r) && r != '\n' && r != '\r' && r != '\t'
+	}) < 0
+}
+
+// validateReview normaliza y valida el cuerpo de "PUT /api/series/{id}/review".
+func validateReview(in *models.ReviewInput) []FieldError {
+	var v validator
+
+	in.Body = strings.TrimSpace(in.Body)
+	if in.Score != nil {
+		v.check(models.ValidScore(*in.Score), "score",
+			fmt.Sprintf("debe estar entre %g y %g, en pasos de 0.5", models.MinScore, models.MaxScore))
+	}
+	v.check(printableText(in.Body), "body", "no puede contener caracteres de control")
+	v.check(utf8.RuneCountInString(in.Body) <= maxReviewLength, "body", fmt.Sprintf("admite hasta %d caracteres", maxReviewLength))
+	if in.Score == nil && in.Body == "" {
+		v.add("score", "se requiere una puntuación o notas ('body')")
+	}
+
+	return v.errors
+}
+
+// validateName normaliza y valida el nombre de una etiqueta o lista.
+func validateName(v *validator, name *string, maxLength int) {
+	*name = strings.TrimSpace(*name)
+	v.check(*name != "", "name", "es obligatorio")
+	v.check(printable(*name), "name", "no puede contener caracteres de control")
+	v.check(utf8.RuneCountInString(*name) <= maxLength, "name", fmt.Sprintf("admite hasta %d caracteres", maxLength))
+}
+
+// validateTag normaliza y valida el cuerpo de "POST /api/tags" y "PUT /api/tags/{id}".
+func validateTag(in *models.TagInput) []FieldError {
+	var v validator
+	validateName(&v, &in.Name, maxTagNameLength)
+	return v.errors
+}
+
+// validateCustomList normaliza y valida el cuerpo de "POST /api/lists" y "PUT /api/lists/{id}".
+func validateCustomList(in *models.CustomListInput) []FieldError {
+	var v validator
+	validateName(&v, &in.Name, maxListNameLength)
+	in.Description = strings.TrimSpace(in.Description)
+	v.check(printableText(in.Description), "description", "no puede contener caracteres de control")
+	v.check(utf8.RuneCountInString(in.Description) <= maxListDescriptionLength, "description",
+		fmt.Sprintf("admite hasta %d caracteres", maxListDescriptionLength))
+	return v.errors
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"lab6/models"
+)
+
+const (
+	// defaultTopLimit es la cantidad de series de "GET /api/series/top" si no se indica "limit".
+	defaultTopLimit = 10
+	// maxTopLimit es el máximo aceptado en "limit".
+	maxTopLimit = 100
+
+	// Valores del parámetro "scope" de "GET /api/series/top".
+	topScopeMine = "mine"
+	topScopeAll  = "all"
+)
+
+// writeVotedSeries escribe la serie votada: completa si es del usuario y, si
+// es de otro, solo sus datos públicos (models.PublicSeries).
+func writeVotedSeries(w http.ResponseWriter, userID int, serie models.Series) {
+	if serie.UserID == userID {
+		writeSeries(w, http.StatusOK, serie)
+		return
+	}
+	w.Header().Set("ETag", seriesETag(serie))
+	writeJSON(w, http.StatusOK, serie.Public())
+}
+
+// GetSeriesVote godoc
+// @Summary      Obtener mi voto
+// @Description  Devuelve el voto del usuario autenticado sobre la serie (propia o de otro usuario): 1 (positivo), -1 (negativo) o 0 (sin voto).
+// @Tags         Series Actions
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Success      200 {object} models.VoteValue "Voto del usuario"
+// @Failure      400 {object} ErrorResponse "ID proporcionado inválido"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al obtener el voto"
+// @Router       /series/{id}/vote [get]
+func (h *SeriesHandler) GetSeriesVote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	value, err := h.repo.GetVote(r.Context(), userID, id)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error obteniendo el voto: ")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, models.VoteValue{Value: value})
+}
+
+// SetSeriesVote godoc
+// @Summary      Fijar mi voto
+// @Description  Fija el voto del usuario autenticado sobre la serie, propia o de otro usuario: 1 (positivo), -1 (negativo) o 0 (retirar el voto). A diferencia de upvote/downvote no alterna: repetir el mismo valor no cambia nada. Si la serie es de otro usuario solo se devuelven sus datos públicos (models.PublicSeries).
+// @Tags         Series Actions
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        id path int true "ID de la Serie" example(1)
+// @Param        If-Match header string false "ETag de la versión esperada; si la serie cambió se responde 412"
+// @Param        vote body models.VoteValue true "Nuevo voto"
+// @Success      200 {object} models.Series "Voto actualizado, devuelve la serie con el ranking recalculado"
+// @Header       200 {string} ETag "Nueva versión de la serie"
+// @Failure      400 {object} ErrorResponse "Entrada inválida (ej. JSON mal formado, ID inválido)"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      404 {object} ErrorResponse "Serie no encontrada"
+// @Failure      412 {object} ErrorResponse "If-Match no coincide con la versión actual"
+// @Failure      422 {object} ErrorResponse "Valor de voto inválido (detalle por campo en 'errors')"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al actualizar el voto"
+// @Router       /series/{id}/vote [put]
+func (h *SeriesHandler) SetSeriesVote(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, ok := seriesID(w, r)
+	if !ok {
+		return
+	}
+
+	var vote models.VoteValue
+	if err := json.NewDecoder(r.Body).Decode(&vote); err != nil {
+		writeError(w, http.StatusBadRequest, "Cuerpo de la solicitud inválido: "+err.Error())
+		return
+	}
+	if !models.ValidVote(vote.Value) {
+		writeValidationError(w, []FieldError{{Field: "value", Message: "debe ser 1, -1 o 0"}})
+		return
+	}
+
+	serie, err := h.repo.SetVote(r.Context(), userID, id, vote.Value)
+	if err != nil {
+		writeRepositoryError(w, err, "Serie no encontrada", "Error actualizando el voto: ")
+		return
+	}
+
+	writeVotedSeries(w, userID, serie)
+}
+
+// GetTopSeries godoc
+// @Summary      Series mejor valoradas
+// @Description  Devuelve las series con mayor ranking (votos de todos los usuarios), de mayor a menor. Con scope=mine (por defecto) solo las del usuario autenticado, completas; con scope=all las de todos los usuarios, solo con sus datos públicos (models.PublicSeries), y no se admite 'status'.
+// @Tags         Series
+// @Produce      json
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Param        limit  query int    false "Cantidad de series (máximo 100)" default(10)
+// @Param        scope  query string false "Series propias o de todos los usuarios" Enums(mine, all) default(mine)
+// @Param        status query string false "Filtrar por estado exacto (solo con scope=mine)" Enums(Plan to Watch, Watching, On Hold, Completed, Dropped)
+// @Success      200 {array}  models.Series "Series ordenadas por ranking (models.PublicSeries con scope=all)"
+// @Failure      400 {object} ErrorResponse "Query params inválidos"
+// @Failure      401 {object} ErrorResponse "No autenticado"
+// @Failure      403 {object} ErrorResponse "Permisos insuficientes (scope requerido)"
+// @Failure      500 {object} ErrorResponse "Error interno del servidor al buscar series"
+// @Router       /series/top [get]
+func (h *SeriesHandler) GetTopSeries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	values := r.URL.Query()
+	q := models.SeriesQuery{
+		Status:   models.SeriesStatus(values.Get("status")),
+		SortBy:   "ranking",
+		SortDesc: true,
+		Page:     1,
+		PageSize: defaultTopLimit,
+	}
+	if q.Status != "" && !q.Status.Valid() {
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: "+models.ValidateStatus(q.Status).Error())
+		return
+	}
+	limit, err := parseOptionalInt(values, "limit")
+	if err == nil && limit != nil && (*limit < 1 || *limit > maxTopLimit) {
+		err = fmt.Errorf("'limit' debe estar entre 1 y %d", maxTopLimit)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: "+err.Error())
+		return
+	}
+	if limit != nil {
+		q.PageSize = *limit
+	}
+
+	switch values.Get("scope") {
+	case "", topScopeMine:
+	case topScopeAll:
+		if q.Status != "" {
+			writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: 'status' solo se admite con scope="+topScopeMine)
+			return
+		}
+		h.writeTopPublicSeries(w, r, q.PageSize)
+		return
+	default:
+		writeError(w, http.StatusBadRequest, "Parámetros de consulta inválidos: 'scope' debe ser '"+topScopeMine+"' o '"+topScopeAll+"'")
+		return
+	}
+
+	series, _, err := h.repo.List(r.Context(), userID, q)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error buscando series: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, series)
+}
+
+// writeTopPublicSeries escribe las limit series de todos los usuarios con mayor
+// ranking, solo con sus datos públicos.
+func (h *SeriesHandler) writeTopPublicSeries(w http.ResponseWriter, r *http.Request, limit int) {
+	series, err := h.repo.TopSeries(r.Context(), limit)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "Error buscando series: "+err.Error())
+		return
+	}
+
+	public := make([]models.PublicSeries, len(series))
+	for i, s := range series {
+		public[i] = s.Public()
+	}
+	writeJSON(w, http.StatusOK, public)
+}
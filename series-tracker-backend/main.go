@@ -14,6 +14,16 @@
 // @BasePath  /api
 // @schemes   http https
 
+// @securityDefinitions.apikey  BearerAuth
+// @in                          header
+// @name                        Authorization
+// @description                 Token de sesión (obtenido en /auth/login o /auth/register), JWT firmado o clave de API, con el formato "Bearer <token>".
+
+// @securityDefinitions.apikey  ApiKeyAuth
+// @in                          header
+// @name                        X-API-Key
+// @description                 Clave de API creada en /keys (prefijo "stk_").
+
 // Package main es el punto de entrada de la aplicación.
 // Configura la base de datos, el router HTTP, el middleware, las rutas de la API,
 // la ruta para Swagger UI y finalmente inicia el servidor web.
@@ -32,8 +42,10 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 
+	"lab6/auth"
 	"lab6/handlers"   // Asegúrate que la ruta de importación sea correcta
 	"lab6/repository" // Asegúrate que la ruta de importación sea correcta
+	"lab6/storage"
 
 	// Importa http-swagger para servir la UI de Swagger
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -46,22 +58,70 @@ func main() {
 	log.Println("Iniciando aplicación Series Tracker...")
 
 	// Iniciar la conexión con la base de datos
-	repository.InitDB()
-	// Obtener la instancia de DB subyacente para poder cerrarla después
-	sqlDB, err := repository.DB.DB()
+	db := repository.InitDB()
+	// Programar el cierre de la conexión DB al final de main
+	defer repository.CloseDB(db)
+
+	// Subcomando "migrate": gestionar el esquema y terminar sin levantar el servidor
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			repository.CloseDB(db)
+			log.Fatalf("Error en migrate: %v", err)
+		}
+		return
+	}
+
+	// Aplicar migraciones pendientes antes de aceptar solicitudes
+	autoMigrate(db)
+	warnOrphanSeries(db)
+
+	// Duración de las sesiones de usuario (por defecto 30 días)
+	sessionTTL := durationEnv("SESSION_TTL", 30*24*time.Hour)
+
+	// Tiempo que las series eliminadas permanecen en la papelera (por defecto 30
+	// días) y cada cuánto se purgan las vencidas y las sesiones expiradas (por
+	// defecto cada hora)
+	trashRetention := durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+	purgeInterval := durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+
+	// Almacenamiento de las imágenes de portada (BLOB_STORE: fs, s3 o memory)
+	blobs, err := storage.Open()
 	if err != nil {
-		log.Fatalf("Error obteniendo la instancia DB subyacente: %v", err)
+		log.Fatalf("Error configurando el almacenamiento de portadas: %v", err)
 	}
-	// Programar el cierre de la conexión DB al final de main
-	defer func() {
-		log.Println("Cerrando conexión a la base de datos...")
-		if err := sqlDB.Close(); err != nil {
-			log.Printf("Error cerrando la conexión a la base de datos: %v", err)
-		} else {
-			log.Println("Conexión a la base de datos cerrada.")
+
+	// Construir los repositorios y los handlers (inyección de dependencias por constructor)
+	userRepo := repository.NewGormUserRepository(db)
+	apiKeyRepo := repository.NewGormAPIKeyRepository(db)
+	seriesRepo := repository.NewGormSeriesRepository(db)
+	seriesHandler := handlers.NewSeriesHandler(seriesRepo, blobs)
+	authHandler := handlers.NewAuthHandler(userRepo, userRepo, sessionTTL)
+	apiKeyHandler := handlers.NewAPIKeyHandler(apiKeyRepo)
+	statsHandler := handlers.NewStatsHandler(repository.NewGormStatsRepository(db))
+	collectionHandler := handlers.NewCollectionHandler(repository.NewGormCollectionRepository(db))
+	exportHandler := handlers.NewExportHandler(repository.NewGormExportRepository(db))
+	trashHandler := handlers.NewTrashHandler(seriesRepo, blobs, trashRetention)
+
+	// Autenticadores aceptados: claves de API, tokens de sesión y, si está configurado, JWT firmados
+	authenticators := []auth.Authenticator{
+		auth.NewAPIKeyAuthenticator(apiKeyRepo, userRepo),
+		auth.NewSessionAuthenticator(userRepo, userRepo),
+	}
+	jwtConfig := auth.JWTConfig{
+		Secret:   []byte(os.Getenv("JWT_SECRET")),
+		JWKSFile: os.Getenv("JWT_JWKS_FILE"),
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+	}
+	if jwtConfig.Enabled() {
+		jwtAuthenticator, err := auth.NewJWTAuthenticator(jwtConfig, userRepo)
+		if err != nil {
+			log.Fatalf("Error configurando la autenticación JWT: %v", err)
 		}
-	}()
-	// defer repository.CloseDB() // Alternativa si CloseDB maneja nil checks
+		authenticators = append(authenticators, jwtAuthenticator)
+		log.Println("Autenticación JWT habilitada.")
+	}
+	authMiddleware := handlers.NewAuthMiddleware(authenticators...)
 
 	// Configurar router Chi
 	r := chi.NewRouter()
@@ -82,8 +142,8 @@ func main() {
 		AllowedOrigins:   []string{"*"}, // Permitir cualquier origen (inseguro para producción)
 		// AllowedOrigins: []string{"http://localhost:3000", "https://mi-frontend.com"}, // Ejemplo más seguro
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"}, // Añadir headers necesarios
-		ExposedHeaders:   []string{"Link"},                                                    // Headers expuestos al frontend
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-API-Key", "If-Match", "If-None-Match"}, // Añadir headers necesarios
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "ETag"},                          // Headers expuestos al frontend
 		AllowCredentials: true,                                                                // Permitir cookies/auth
 		MaxAge:           300, // Tiempo máximo que el resultado de preflight puede ser cacheado (en segundos)
 	}))
@@ -96,18 +156,116 @@ func main() {
 
 	// Agrupar rutas de la API bajo el prefijo /api
 	r.Route("/api", func(r chi.Router) {
-		// Rutas para el recurso 'series'
-		r.Get("/series", handlers.GetAllSeries)                // GET /api/series
-		r.Post("/series", handlers.CreateSeries)               // POST /api/series
-		r.Get("/series/{id}", handlers.GetSeriesByID)          // GET /api/series/123
-		r.Put("/series/{id}", handlers.UpdateSeries)           // PUT /api/series/123
-		r.Delete("/series/{id}", handlers.DeleteSeries)        // DELETE /api/series/123
-
-		// Rutas de acciones específicas sobre 'series' (usando PATCH)
-		r.Patch("/series/{id}/status", handlers.UpdateSeriesStatus)     // PATCH /api/series/123/status
-		r.Patch("/series/{id}/episode", handlers.IncrementSeriesEpisode) // PATCH /api/series/123/episode
-		r.Patch("/series/{id}/upvote", handlers.UpvoteSeries)           // PATCH /api/series/123/upvote
-		r.Patch("/series/{id}/downvote", handlers.DownvoteSeries)       // PATCH /api/series/123/downvote
+		// Rutas públicas de autenticación
+		r.Post("/auth/register", authHandler.Register) // POST /api/auth/register
+		r.Post("/auth/login", authHandler.Login)       // POST /api/auth/login
+
+		// Imágenes de portada: públicas y cacheables (el token es aleatorio)
+		r.Get("/covers/{token}/{file}", seriesHandler.GetCoverImage) // GET /api/covers/3f2a.../small.jpg
+
+		// Rutas que requieren credenciales válidas (header Authorization: Bearer <token>)
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+
+			r.Post("/auth/logout", authHandler.Logout) // POST /api/auth/logout
+			r.Get("/auth/me", authHandler.Me)          // GET /api/auth/me
+
+			// Gestión de claves de API: solo con sesión o JWT, nunca con otra clave de API
+			r.Group(func(r chi.Router) {
+				r.Use(handlers.RejectAPIKeys)
+				r.Use(handlers.RequireMethodScope)
+
+				r.Get("/keys", apiKeyHandler.ListAPIKeys)          // GET /api/keys
+				r.Post("/keys", apiKeyHandler.CreateAPIKey)        // POST /api/keys
+				r.Delete("/keys/{id}", apiKeyHandler.RevokeAPIKey) // DELETE /api/keys/123
+			})
+		})
+
+		// Rutas de datos: además de autenticación exigen el scope 'read' (GET) o 'write' (resto)
+		r.Group(func(r chi.Router) {
+			r.Use(authMiddleware.RequireAuth)
+			r.Use(handlers.RequireMethodScope)
+
+			// Rutas para el recurso 'series' (limitadas a las series del usuario)
+			r.Get("/series", seriesHandler.GetAllSeries)        // GET /api/series
+			r.Get("/series/top", seriesHandler.GetTopSeries)    // GET /api/series/top
+			r.Get("/series/search", seriesHandler.SearchSeries) // GET /api/series/search?q=attack+titan
+			r.Post("/series", seriesHandler.CreateSeries)       // POST /api/series
+			r.Post("/series/batch", seriesHandler.BatchSeries)  // POST /api/series/batch
+			r.Get("/series/{id}", seriesHandler.GetSeriesByID)  // GET /api/series/123
+
+			// Modificaciones de una serie concreta: aceptan If-Match y responden 412
+			// si la serie cambió (concurrencia optimista)
+			r.Group(func(r chi.Router) {
+				r.Use(handlers.IfMatch)
+
+				r.Put("/series/{id}", seriesHandler.UpdateSeries)    // PUT /api/series/123
+				r.Patch("/series/{id}", seriesHandler.PatchSeries)   // PATCH /api/series/123
+				r.Delete("/series/{id}", seriesHandler.DeleteSeries) // DELETE /api/series/123
+
+				// Rutas de acciones específicas sobre 'series' (usando PATCH)
+				r.Patch("/series/{id}/status", seriesHandler.UpdateSeriesStatus)      // PATCH /api/series/123/status
+				r.Patch("/series/{id}/episode", seriesHandler.IncrementSeriesEpisode) // PATCH /api/series/123/episode
+				r.Patch("/series/{id}/upvote", seriesHandler.UpvoteSeries)            // PATCH /api/series/123/upvote
+				r.Patch("/series/{id}/downvote", seriesHandler.DownvoteSeries)        // PATCH /api/series/123/downvote
+
+				// Progreso por episodio o temporada
+				r.Put("/series/{id}/episode", seriesHandler.SetSeriesEpisode)                   // PUT /api/series/123/episode
+				r.Patch("/series/{id}/episode/decrement", seriesHandler.DecrementSeriesEpisode) // PATCH /api/series/123/episode/decrement
+				r.Post("/series/{id}/episode/range", seriesHandler.WatchSeriesRange)            // POST /api/series/123/episode/range
+				r.Post("/series/{id}/rewatch", seriesHandler.RewatchSeries)                     // POST /api/series/123/rewatch
+
+				// Deshacer el último cambio del historial
+				r.Post("/series/{id}/history/undo", seriesHandler.UndoSeriesEvent) // POST /api/series/123/history/undo
+
+				// Voto del usuario (un voto por usuario y serie)
+				r.Put("/series/{id}/vote", seriesHandler.SetSeriesVote) // PUT /api/series/123/vote
+
+				// Portada subida por el usuario (multipart/form-data)
+				r.Post("/series/{id}/cover", seriesHandler.UploadSeriesCover)   // POST /api/series/123/cover
+				r.Delete("/series/{id}/cover", seriesHandler.DeleteSeriesCover) // DELETE /api/series/123/cover
+			})
+
+			// Historial de visualización y voto del usuario
+			r.Get("/series/{id}/history", seriesHandler.GetSeriesHistory) // GET /api/series/123/history
+			r.Get("/series/{id}/vote", seriesHandler.GetSeriesVote)       // GET /api/series/123/vote
+
+			// Reseña del usuario: puntuación personal y notas
+			r.Get("/series/{id}/review", seriesHandler.GetSeriesReview)       // GET /api/series/123/review
+			r.Put("/series/{id}/review", seriesHandler.SaveSeriesReview)      // PUT /api/series/123/review
+			r.Delete("/series/{id}/review", seriesHandler.DeleteSeriesReview) // DELETE /api/series/123/review
+
+			// Papelera: series eliminadas que todavía pueden restaurarse
+			r.Get("/trash", trashHandler.ListTrash)                    // GET /api/trash
+			r.Post("/series/{id}/restore", trashHandler.RestoreSeries) // POST /api/series/123/restore
+
+			// Etiquetas (géneros) y su asignación a series
+			r.Get("/tags", collectionHandler.ListTags)                               // GET /api/tags
+			r.Post("/tags", collectionHandler.CreateTag)                             // POST /api/tags
+			r.Put("/tags/{id}", collectionHandler.RenameTag)                         // PUT /api/tags/5
+			r.Delete("/tags/{id}", collectionHandler.DeleteTag)                      // DELETE /api/tags/5
+			r.Get("/series/{id}/tags", collectionHandler.GetSeriesTags)              // GET /api/series/123/tags
+			r.Put("/series/{id}/tags/{tagId}", collectionHandler.AttachSeriesTag)    // PUT /api/series/123/tags/5
+			r.Delete("/series/{id}/tags/{tagId}", collectionHandler.DetachSeriesTag) // DELETE /api/series/123/tags/5
+
+			// Listas personalizadas
+			r.Get("/lists", collectionHandler.ListCustomLists)                                // GET /api/lists
+			r.Post("/lists", collectionHandler.CreateCustomList)                              // POST /api/lists
+			r.Get("/lists/{id}", collectionHandler.GetCustomList)                             // GET /api/lists/7
+			r.Put("/lists/{id}", collectionHandler.UpdateCustomList)                          // PUT /api/lists/7
+			r.Delete("/lists/{id}", collectionHandler.DeleteCustomList)                       // DELETE /api/lists/7
+			r.Put("/lists/{id}/series/{seriesId}", collectionHandler.AddSeriesToList)         // PUT /api/lists/7/series/123
+			r.Delete("/lists/{id}/series/{seriesId}", collectionHandler.RemoveSeriesFromList) // DELETE /api/lists/7/series/123
+
+			// Importación de listas de MyAnimeList (XML) y AniList (JSON)
+			r.Post("/import", seriesHandler.ImportSeries) // POST /api/import
+
+			// Exportación completa en JSON, CSV o XML compatible con MyAnimeList
+			r.Get("/export", exportHandler.ExportSeries) // GET /api/export?format=csv
+
+			// Estadísticas de visualización
+			r.Get("/stats", statsHandler.GetStats) // GET /api/stats
+		})
 	})
 
 	// Ruta de health check simple
@@ -133,6 +291,11 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	// Purgar periódicamente la papelera y las sesiones expiradas mientras el servidor está en marcha
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go handlers.RunPurges(purgeCtx, purgeInterval, trashHandler.Purge, authHandler.PurgeSessions)
+
 	// Canal para escuchar errores del servidor en una goroutine separada
 	serverErrors := make(chan error, 1)
 
@@ -154,6 +317,7 @@ func main() {
 
 	case sig := <-shutdown:
 		log.Printf("Señal de cierre (%v) recibida. Iniciando apagado grácil...", sig)
+		stopPurge()
 
 		// Crear un contexto con timeout para el apagado
 		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
@@ -173,3 +337,18 @@ func main() {
 
 	log.Println("Aplicación terminada.")
 }
+
+// durationEnv lee la duración de la variable de entorno name (por ejemplo
+// "720h"), o devuelve def si no está definida. Termina el programa si el valor
+// no es una duración positiva.
+func durationEnv(name string, def time.Duration) time.Duration {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Fatalf("%s inválido (%q): use una duración como 720h", name, raw)
+	}
+	return d
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"lab6/migrations"
+	"lab6/models"
+)
+
+// migrateUsage describe el uso del subcomando "migrate".
+const migrateUsage = `Uso: server migrate <comando>
+
+Comandos:
+  up          Aplica todas las migraciones pendientes
+  down [n]    Revierte las últimas n migraciones aplicadas (por defecto 1)
+  status      Muestra qué migraciones están aplicadas
+  resolve applied|pending
+              Quita la marca "dirty" de la migración que falló a medio aplicar
+              o revertir (una vez corregido el esquema a mano), registrándola
+              como aplicada o como pendiente
+  assign-orphans [-user nombre]
+              Asigna las series sin dueño (anteriores a las cuentas de usuario)
+              al usuario indicado o, si se omite, al primero que se registró`
+
+// runMigrate ejecuta el subcomando "migrate" con los argumentos indicados
+// (sin incluir "migrate") y devuelve un error si el comando falla.
+func runMigrate(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("falta el comando\n%s", migrateUsage)
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Printf("Aplicada   %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("No hay migraciones pendientes.")
+		}
+		return err
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("cantidad de pasos inválida: %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			fmt.Printf("Revertida  %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("No hay migraciones aplicadas para revertir.")
+		}
+		return err
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			applied := "pendiente"
+			if s.Dirty {
+				applied = "a medio aplicar (dirty)"
+			} else if s.AppliedAt != nil {
+				applied = "aplicada " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", s.Version, s.Name, applied)
+		}
+		return nil
+
+	case "resolve":
+		if len(args) < 2 || (args[1] != "applied" && args[1] != "pending") {
+			return fmt.Errorf("indique 'applied' o 'pending'\n%s", migrateUsage)
+		}
+		resolved, err := migrator.Resolve(ctx, args[1] == "applied")
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Resuelta   %04d_%s (%s)\n", resolved.Version, resolved.Name, args[1])
+		return nil
+
+	case "assign-orphans":
+		return assignOrphans(ctx, db, args[1:])
+	}
+
+	return fmt.Errorf("comando desconocido: %q\n%s", args[0], migrateUsage)
+}
+
+// assignOrphans asigna las series sin dueño (user_id NULL, creadas antes de la
+// migración 0002_create_users) al usuario indicado con -user o, si se omite,
+// al primer usuario registrado. Incluye las que están en la papelera.
+func assignOrphans(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("assign-orphans", flag.ContinueOnError)
+	username := flags.String("user", "", "usuario al que se asignan las series (por defecto el primero registrado)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var user models.User
+	query := db.WithContext(ctx).Order("id")
+	if *username != "" {
+		query = query.Where("username = ?", *username)
+	}
+	if err := query.First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if *username != "" {
+				return fmt.Errorf("no existe el usuario %q", *username)
+			}
+			return errors.New("no hay usuarios registrados: registre uno antes de asignar las series")
+		}
+		return err
+	}
+
+	result := db.WithContext(ctx).Exec("UPDATE series SET user_id = ? WHERE user_id IS NULL", user.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	fmt.Printf("Series asignadas a %q: %d\n", user.Username, result.RowsAffected)
+	return nil
+}
+
+// warnOrphanSeries avisa en el log si quedan series sin dueño, que no son
+// visibles por la API hasta asignarlas con "server migrate assign-orphans".
+func warnOrphanSeries(db *gorm.DB) {
+	var orphans int64
+	if err := db.Table("series").Where("user_id IS NULL").Count(&orphans).Error; err != nil {
+		log.Printf("Error contando las series sin dueño: %v", err)
+		return
+	}
+	if orphans > 0 {
+		log.Printf("Hay %d series sin dueño (anteriores a las cuentas de usuario) que no son visibles por la API; asígnelas con \"server migrate assign-orphans [-user nombre]\"", orphans)
+	}
+}
+
+// autoMigrate aplica las migraciones pendientes al iniciar el servidor, salvo que
+// DB_AUTO_MIGRATE sea "false" (en ese caso se deben aplicar con "server migrate up").
+// Termina la aplicación si alguna migración falla.
+func autoMigrate(db *gorm.DB) {
+	if os.Getenv("DB_AUTO_MIGRATE") == "false" {
+		log.Println("DB_AUTO_MIGRATE=false: se omiten las migraciones automáticas.")
+		return
+	}
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		log.Fatalf("Error fatal cargando migraciones: %v", err)
+	}
+	applied, err := migrator.Up(context.Background())
+	for _, m := range applied {
+		log.Printf("Migración aplicada: %04d_%s", m.Version, m.Name)
+	}
+	if err != nil {
+		log.Fatalf("Error fatal aplicando migraciones: %v", err)
+	}
+}
@@ -0,0 +1,384 @@
+// Package migrations aplica de forma ordenada y repetible los cambios de esquema
+// de la base de datos. Cada migración es un par de archivos SQL embebidos en el
+// binario (NNNN_nombre.up.sql y NNNN_nombre.down.sql) dentro del directorio del
+// motor correspondiente (mysql, postgres o sqlite). Las versiones aplicadas se
+// registran en la tabla schema_migrations.
+//
+// Aplicar o revertir migraciones toma un lock de la base de datos (GET_LOCK en
+// MySQL, pg_advisory_lock en PostgreSQL) para que varias instancias que inician
+// a la vez no las ejecuten dos veces. Como MySQL no puede deshacer las sentencias
+// DDL, una migración que falla a mitad de camino queda marcada como "dirty" y
+// bloquea las siguientes hasta que se revise el esquema y se resuelva a mano
+// (Resolve).
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+const (
+	// lockName es el nombre del lock de migraciones en MySQL (GET_LOCK) y
+	// lockKey su clave en PostgreSQL (pg_advisory_lock).
+	lockName = "schema_migrations"
+	lockKey  = 7_355_270_001
+
+	// lockTimeout es el tiempo máximo de espera del lock de migraciones.
+	lockTimeout = 5 * time.Minute
+)
+
+// ErrDirty indica que una migración quedó a medio aplicar o revertir: sus
+// sentencias fallaron después de que MySQL confirmara parte de ellas.
+var ErrDirty = errors.New("migración a medio aplicar (dirty)")
+
+// Migration representa un cambio de esquema versionado.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status describe una migración y, si ya fue aplicada, cuándo. Dirty indica
+// que quedó a medio aplicar o revertir (ver ErrDirty).
+type Status struct {
+	Migration
+	AppliedAt *time.Time
+	Dirty     bool
+}
+
+// appliedRow es una fila de la tabla schema_migrations.
+type appliedRow struct {
+	Version   int
+	AppliedAt time.Time
+	Dirty     bool
+}
+
+// Load devuelve las migraciones del motor indicado ("mysql", "postgres" o "sqlite")
+// ordenadas por versión. Falla si falta el archivo up o down de alguna versión.
+func Load(dialect string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dialect)
+	if err != nil {
+		return nil, fmt.Errorf("no hay migraciones para el motor %q: %w", dialect, err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		// Formato esperado: 0001_create_series.up.sql
+		name := strings.TrimSuffix(entry.Name(), ".sql")
+		base, direction := name[:max(strings.LastIndex(name, "."), 0)], path.Ext(name)
+		versionStr, label, found := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionStr)
+		if !found || err != nil || (direction != ".up" && direction != ".down") {
+			return nil, fmt.Errorf("nombre de migración inválido: %s/%s", dialect, entry.Name())
+		}
+
+		content, err := fs.ReadFile(files, dialect+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: label}
+			byVersion[version] = m
+		} else if m.Name != label {
+			return nil, fmt.Errorf("la versión %d tiene nombres distintos: %q y %q", version, m.Name, label)
+		}
+		if direction == ".up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("la migración %04d_%s debe tener archivos up y down", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// Migrator aplica y revierte migraciones sobre una conexión GORM.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// New crea un Migrator con las migraciones correspondientes al motor de db.
+func New(db *gorm.DB) (*Migrator, error) {
+	migrations, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// ensureTable crea la tabla schema_migrations si no existe y le agrega la
+// columna dirty si fue creada por una versión anterior.
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL,
+    dirty BOOLEAN NOT NULL DEFAULT FALSE
+)`).Error
+	if err != nil || db.Migrator().HasColumn("schema_migrations", "dirty") {
+		return err
+	}
+	return db.Exec("ALTER TABLE schema_migrations ADD COLUMN dirty BOOLEAN NOT NULL DEFAULT FALSE").Error
+}
+
+// applied devuelve las versiones registradas en schema_migrations.
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedRow, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, fmt.Errorf("creando schema_migrations: %w", err)
+	}
+	var rows []appliedRow
+	if err := m.db.WithContext(ctx).Raw("SELECT version, applied_at, dirty FROM schema_migrations").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("leyendo schema_migrations: %w", err)
+	}
+	applied := make(map[int]appliedRow, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// clean devuelve las versiones aplicadas, o un error que envuelve ErrDirty si
+// alguna quedó a medio aplicar o revertir.
+func (m *Migrator) clean(ctx context.Context) (map[int]appliedRow, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range m.migrations {
+		if row, ok := applied[migration.Version]; ok && row.Dirty {
+			return nil, fmt.Errorf("%w: %04d_%s; revise el esquema y ejecute \"server migrate resolve applied|pending\"",
+				ErrDirty, migration.Version, migration.Name)
+		}
+	}
+	return applied, nil
+}
+
+// locked ejecuta fn con un Migrator ligado a una única conexión que tiene
+// tomado el lock de migraciones. En SQLite no hace falta: admite un solo
+// escritor y sus sentencias DDL son transaccionales.
+func (m *Migrator) locked(ctx context.Context, fn func(m *Migrator) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		conn = conn.Session(&gorm.Session{NewDB: true})
+		var lock, unlock func() error
+		switch m.db.Dialector.Name() {
+		case "mysql":
+			lock = func() error {
+				var acquired sql.NullInt64
+				err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Row().Scan(&acquired)
+				if err == nil && acquired.Int64 != 1 {
+					err = fmt.Errorf("no se obtuvo en %s", lockTimeout)
+				}
+				return err
+			}
+			unlock = func() error { return conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error }
+		case "postgres":
+			lock = func() error {
+				lockCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+				defer cancel()
+				return conn.WithContext(lockCtx).Exec("SELECT pg_advisory_lock(?)", lockKey).Error
+			}
+			unlock = func() error { return conn.Exec("SELECT pg_advisory_unlock(?)", lockKey).Error }
+		default:
+			return fn(&Migrator{db: conn, migrations: m.migrations})
+		}
+
+		if err := lock(); err != nil {
+			return fmt.Errorf("tomando el lock de migraciones: %w", err)
+		}
+		defer func() {
+			if err := unlock(); err != nil {
+				log.Printf("Error liberando el lock de migraciones: %v", err)
+			}
+		}()
+		return fn(&Migrator{db: conn, migrations: m.migrations})
+	})
+}
+
+// Status devuelve todas las migraciones conocidas indicando cuáles están aplicadas.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			status.AppliedAt, status.Dirty = &row.AppliedAt, row.Dirty
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up aplica en orden todas las migraciones pendientes y devuelve las aplicadas.
+// Se detiene en el primer error; las migraciones anteriores quedan registradas.
+// Falla con ErrDirty si alguna migración quedó a medio aplicar o revertir.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	err = m.locked(ctx, func(m *Migrator) error {
+		applied, err := m.clean(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			err := m.run(ctx, migration.Up, func(tx *gorm.DB) error {
+				return tx.Exec("INSERT INTO schema_migrations (version, name, applied_at, dirty) VALUES (?, ?, ?, ?)",
+					migration.Version, migration.Name, time.Now().UTC(), true).Error
+			}, func(tx *gorm.DB) error {
+				return tx.Exec("UPDATE schema_migrations SET dirty = ? WHERE version = ?", false, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("aplicando migración %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down revierte las últimas steps migraciones aplicadas, de la más reciente a la más antigua.
+// Falla con ErrDirty si alguna migración quedó a medio aplicar o revertir.
+func (m *Migrator) Down(ctx context.Context, steps int) (done []Migration, err error) {
+	err = m.locked(ctx, func(m *Migrator) error {
+		applied, err := m.clean(ctx)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			err := m.run(ctx, migration.Down, func(tx *gorm.DB) error {
+				return tx.Exec("UPDATE schema_migrations SET dirty = ? WHERE version = ?", true, migration.Version).Error
+			}, func(tx *gorm.DB) error {
+				return tx.Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("revirtiendo migración %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Resolve quita la marca dirty de la migración que quedó a medio aplicar o
+// revertir, una vez corregido el esquema a mano: con applied la registra como
+// aplicada y, si no, como pendiente. Devuelve la migración resuelta.
+func (m *Migrator) Resolve(ctx context.Context, applied bool) (resolved Migration, err error) {
+	err = m.locked(ctx, func(m *Migrator) error {
+		rows, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if row, ok := rows[migration.Version]; !ok || !row.Dirty {
+				continue
+			}
+			resolved = migration
+			if applied {
+				return m.db.WithContext(ctx).Exec("UPDATE schema_migrations SET dirty = ? WHERE version = ?", false, migration.Version).Error
+			}
+			return m.db.WithContext(ctx).Exec("DELETE FROM schema_migrations WHERE version = ?", migration.Version).Error
+		}
+		return errors.New("no hay migraciones a medio aplicar")
+	})
+	return resolved, err
+}
+
+// run ejecuta dentro de una transacción mark, las sentencias de script y done.
+// MySQL confirma implícitamente las sentencias DDL (junto con lo ejecutado
+// antes en la transacción), por lo que si script falla después de una de ellas
+// la marca de mark queda guardada y done no se ejecuta: así la migración queda
+// "dirty". En PostgreSQL y SQLite la transacción deshace todo.
+func (m *Migrator) run(ctx context.Context, script string, mark, done func(tx *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := mark(tx); err != nil {
+			return err
+		}
+		for _, statement := range splitStatements(script) {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return done(tx)
+	})
+}
+
+// splitStatements separa un script SQL en sentencias individuales, ya que no
+// todos los drivers aceptan varias sentencias en un solo Exec. Las sentencias
+// terminan con ';' al final de una línea. Los bloques delimitados por las líneas
+// "-- +begin" y "-- +end" (por ejemplo, triggers con ';' internos) se envían
+// completos como una única sentencia.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		inBlock    bool
+	)
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, strings.TrimSuffix(statement, ";"))
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "-- +begin":
+			flush()
+			inBlock = true
+			continue
+		case trimmed == "-- +end":
+			flush()
+			inBlock = false
+			continue
+		case strings.HasPrefix(trimmed, "--") || trimmed == "":
+			continue
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+		if !inBlock && strings.HasSuffix(trimmed, ";") {
+			flush()
+		}
+	}
+	flush()
+	return statements
+}
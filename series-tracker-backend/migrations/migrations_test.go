@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestDirtyMigrationBlocksUntilResolved(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("abriendo SQLite: %v", err)
+	}
+	ctx := context.Background()
+	migrator, err := New(db)
+	if err != nil {
+		t.Fatalf("cargando migraciones: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("aplicando migraciones: %v", err)
+	}
+
+	// Simula una migración de MySQL que falló después de confirmar parte de su DDL
+	last := migrator.migrations[len(migrator.migrations)-1]
+	if err := db.Exec("UPDATE schema_migrations SET dirty = ? WHERE version = ?", true, last.Version).Error; err != nil {
+		t.Fatalf("marcando la migración: %v", err)
+	}
+	if _, err := migrator.Up(ctx); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Up con una migración dirty: err = %v, se esperaba ErrDirty", err)
+	}
+	if _, err := migrator.Down(ctx, 1); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Down con una migración dirty: err = %v, se esperaba ErrDirty", err)
+	}
+
+	resolved, err := migrator.Resolve(ctx, true)
+	if err != nil || resolved.Version != last.Version {
+		t.Fatalf("Resolve = %04d, %v; se esperaba %04d", resolved.Version, err, last.Version)
+	}
+	if _, err := migrator.Resolve(ctx, true); err == nil {
+		t.Error("Resolve sin migraciones dirty no devolvió error")
+	}
+	if done, err := migrator.Down(ctx, 1); err != nil || len(done) != 1 || done[0].Version != last.Version {
+		t.Errorf("Down después de resolver = %v, %v; se esperaba revertir %04d", done, err, last.Version)
+	}
+}
@@ -0,0 +1,10 @@
+-- Tabla principal de series. Coincide con la definición histórica de init.sql,
+-- por lo que IF NOT EXISTS permite adoptar bases de datos ya existentes.
+CREATE TABLE IF NOT EXISTS series (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    status ENUM('Plan to Watch', 'Watching', 'Completed', 'Dropped') NOT NULL DEFAULT 'Plan to Watch',
+    last_episode_watched INT DEFAULT 0,
+    total_episodes INT DEFAULT 0,
+    ranking INT DEFAULT 0
+);
@@ -0,0 +1,7 @@
+ALTER TABLE series DROP FOREIGN KEY fk_series_user;
+
+ALTER TABLE series DROP COLUMN user_id;
+
+DROP TABLE IF EXISTS sessions;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,24 @@
+-- Cuentas de usuario y sesiones. Cada serie pasa a pertenecer a un usuario;
+-- las series existentes quedan sin dueño (user_id NULL) y no son visibles por la API
+-- hasta asignarlas con "server migrate assign-orphans".
+CREATE TABLE IF NOT EXISTS users (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(50) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    CONSTRAINT uq_users_username UNIQUE (username)
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    CONSTRAINT uq_sessions_token_hash UNIQUE (token_hash),
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+ALTER TABLE series ADD COLUMN user_id INT NULL;
+
+ALTER TABLE series ADD CONSTRAINT fk_series_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
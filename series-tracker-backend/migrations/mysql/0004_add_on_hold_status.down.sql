@@ -0,0 +1,4 @@
+-- Las series en pausa vuelven a 'Watching' antes de quitar el valor del ENUM.
+UPDATE series SET status = 'Watching' WHERE status = 'On Hold';
+
+ALTER TABLE series MODIFY COLUMN status ENUM('Plan to Watch', 'Watching', 'Completed', 'Dropped') NOT NULL DEFAULT 'Plan to Watch';
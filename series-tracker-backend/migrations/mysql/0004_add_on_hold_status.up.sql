@@ -0,0 +1,2 @@
+-- Nuevo estado 'On Hold' (serie en pausa).
+ALTER TABLE series MODIFY COLUMN status ENUM('Plan to Watch', 'Watching', 'On Hold', 'Completed', 'Dropped') NOT NULL DEFAULT 'Plan to Watch';
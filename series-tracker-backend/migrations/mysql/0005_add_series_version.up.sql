@@ -0,0 +1,2 @@
+-- Versión de cada serie para el control de concurrencia optimista (ETag / If-Match).
+ALTER TABLE series ADD COLUMN version INT NOT NULL DEFAULT 1;
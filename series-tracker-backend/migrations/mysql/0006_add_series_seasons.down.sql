@@ -0,0 +1,4 @@
+ALTER TABLE series DROP COLUMN current_episode;
+ALTER TABLE series DROP COLUMN current_season;
+ALTER TABLE series DROP COLUMN seasons;
+ALTER TABLE series DROP COLUMN season_episodes;
@@ -0,0 +1,15 @@
+-- Historial de cambios de progreso y de estado de cada serie.
+CREATE TABLE IF NOT EXISTS watch_events (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    series_id INT NOT NULL,
+    user_id INT NOT NULL,
+    episode INT NOT NULL,
+    previous_episode INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    previous_status VARCHAR(20) NOT NULL,
+    watched_at DATETIME NOT NULL,
+    INDEX idx_watch_events_series_id (series_id),
+    INDEX idx_watch_events_user_watched_at (user_id, watched_at),
+    CONSTRAINT fk_watch_events_series FOREIGN KEY (series_id) REFERENCES series (id) ON DELETE CASCADE,
+    CONSTRAINT fk_watch_events_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,23 @@
+-- Un voto (positivo o negativo) por usuario y serie. El ranking de la serie pasa
+-- a ser legacy_ranking + upvotes - downvotes.
+CREATE TABLE IF NOT EXISTS votes (
+    series_id INT NOT NULL,
+    user_id INT NOT NULL,
+    value SMALLINT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (series_id, user_id),
+    INDEX idx_votes_user_id (user_id),
+    CONSTRAINT chk_votes_value CHECK (value IN (-1, 1)),
+    CONSTRAINT fk_votes_series FOREIGN KEY (series_id) REFERENCES series (id) ON DELETE CASCADE,
+    CONSTRAINT fk_votes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+ALTER TABLE series ADD COLUMN upvotes INT NOT NULL DEFAULT 0;
+ALTER TABLE series ADD COLUMN downvotes INT NOT NULL DEFAULT 0;
+ALTER TABLE series ADD COLUMN legacy_ranking INT NOT NULL DEFAULT 0;
+
+-- El ranking acumulado hasta ahora no registra quién votó, por lo que no puede
+-- convertirse en votos: se conserva en legacy_ranking y se suma a los votos
+-- nuevos, de modo que el ranking de cada serie no cambia al migrar.
+UPDATE series SET legacy_ranking = ranking;
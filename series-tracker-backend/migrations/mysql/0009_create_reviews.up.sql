@@ -0,0 +1,15 @@
+-- Puntuación personal (1 a 10, en pasos de 0.5) y notas en Markdown de cada
+-- usuario sobre una serie.
+CREATE TABLE IF NOT EXISTS reviews (
+    series_id INT NOT NULL,
+    user_id INT NOT NULL,
+    score DECIMAL(3, 1) NULL,
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (series_id, user_id),
+    INDEX idx_reviews_user_id (user_id),
+    CONSTRAINT chk_reviews_score CHECK (score BETWEEN 1 AND 10),
+    CONSTRAINT fk_reviews_series FOREIGN KEY (series_id) REFERENCES series (id) ON DELETE CASCADE,
+    CONSTRAINT fk_reviews_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,40 @@
+-- Etiquetas (géneros o categorías) y listas personalizadas de cada usuario, con
+-- su relación muchos a muchos con las series.
+CREATE TABLE IF NOT EXISTS tags (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE INDEX idx_tags_user_name (user_id, name),
+    CONSTRAINT fk_tags_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS series_tags (
+    series_id INT NOT NULL,
+    tag_id INT NOT NULL,
+    PRIMARY KEY (series_id, tag_id),
+    INDEX idx_series_tags_tag_id (tag_id),
+    CONSTRAINT fk_series_tags_series FOREIGN KEY (series_id) REFERENCES series (id) ON DELETE CASCADE,
+    CONSTRAINT fk_series_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS custom_lists (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE INDEX idx_custom_lists_user_name (user_id, name),
+    CONSTRAINT fk_custom_lists_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS custom_list_items (
+    list_id INT NOT NULL,
+    series_id INT NOT NULL,
+    added_at DATETIME NOT NULL,
+    PRIMARY KEY (list_id, series_id),
+    INDEX idx_custom_list_items_series_id (series_id),
+    CONSTRAINT fk_custom_list_items_list FOREIGN KEY (list_id) REFERENCES custom_lists (id) ON DELETE CASCADE,
+    CONSTRAINT fk_custom_list_items_series FOREIGN KEY (series_id) REFERENCES series (id) ON DELETE CASCADE
+);
@@ -0,0 +1,7 @@
+ALTER TABLE series DROP COLUMN airing_status;
+ALTER TABLE series DROP COLUMN studio;
+ALTER TABLE series DROP COLUMN media_type;
+ALTER TABLE series DROP COLUMN end_year;
+ALTER TABLE series DROP COLUMN start_year;
+ALTER TABLE series DROP COLUMN synopsis;
+ALTER TABLE series DROP COLUMN cover_url;
@@ -0,0 +1,9 @@
+-- Datos descriptivos de la serie: portada, sinopsis, años de emisión, formato,
+-- estudio y estado de emisión. Todos son opcionales.
+ALTER TABLE series ADD COLUMN cover_url VARCHAR(2048) NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN synopsis TEXT NOT NULL DEFAULT ('');
+ALTER TABLE series ADD COLUMN start_year INT NULL;
+ALTER TABLE series ADD COLUMN end_year INT NULL;
+ALTER TABLE series ADD COLUMN media_type VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN studio VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN airing_status VARCHAR(20) NOT NULL DEFAULT '';
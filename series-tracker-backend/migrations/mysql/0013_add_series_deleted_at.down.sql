@@ -0,0 +1,2 @@
+DROP INDEX idx_series_deleted_at ON series;
+ALTER TABLE series DROP COLUMN deleted_at;
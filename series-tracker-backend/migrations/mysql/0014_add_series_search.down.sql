@@ -0,0 +1,2 @@
+DROP INDEX ft_series_titles ON series;
+ALTER TABLE series DROP COLUMN alt_titles;
@@ -0,0 +1,5 @@
+-- Búsqueda de series (GET /api/series/search): títulos alternativos e índice
+-- FULLTEXT sobre el título y los títulos alternativos. MariaDB no tiene índices
+-- de trigramas: los errores de escritura se toleran comparando en la aplicación.
+ALTER TABLE series ADD COLUMN alt_titles TEXT NOT NULL DEFAULT ('');
+CREATE FULLTEXT INDEX ft_series_titles ON series (title, alt_titles);
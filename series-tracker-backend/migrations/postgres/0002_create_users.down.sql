@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_series_user_id;
+
+ALTER TABLE series DROP COLUMN IF EXISTS user_id;
+
+DROP TABLE IF EXISTS sessions;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,25 @@
+-- Cuentas de usuario y sesiones. Cada serie pasa a pertenecer a un usuario;
+-- las series existentes quedan sin dueño (user_id NULL) y no son visibles por la API
+-- hasta asignarlas con "server migrate assign-orphans".
+CREATE TABLE IF NOT EXISTS users (
+    id SERIAL PRIMARY KEY,
+    username VARCHAR(50) NOT NULL,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT uq_users_username UNIQUE (username)
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    CONSTRAINT uq_sessions_token_hash UNIQUE (token_hash)
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+
+ALTER TABLE series ADD COLUMN user_id INTEGER NULL REFERENCES users (id) ON DELETE CASCADE;
+
+CREATE INDEX idx_series_user_id ON series (user_id);
@@ -0,0 +1,15 @@
+-- Claves de API para scripts e integraciones. Solo se guarda el hash SHA-256.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scope VARCHAR(10) NOT NULL DEFAULT 'read',
+    created_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ NULL,
+    revoked_at TIMESTAMPTZ NULL,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash)
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
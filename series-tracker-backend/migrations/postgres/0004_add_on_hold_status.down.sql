@@ -0,0 +1,2 @@
+-- Las series en pausa vuelven a 'Watching'.
+UPDATE series SET status = 'Watching' WHERE status = 'On Hold';
@@ -0,0 +1,2 @@
+-- Nuevo estado 'On Hold' (serie en pausa). La columna status es VARCHAR y los
+-- valores permitidos los valida la aplicación, por lo que no hay cambios de esquema.
@@ -0,0 +1 @@
+ALTER TABLE series DROP COLUMN version;
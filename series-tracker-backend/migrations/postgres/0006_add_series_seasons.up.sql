@@ -0,0 +1,6 @@
+-- Estructura opcional de temporadas: episodios por temporada (lista separada por
+-- comas) y la posición derivada del último episodio visto.
+ALTER TABLE series ADD COLUMN season_episodes VARCHAR(2000) NOT NULL DEFAULT '';
+ALTER TABLE series ADD COLUMN seasons INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE series ADD COLUMN current_season INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE series ADD COLUMN current_episode INTEGER NOT NULL DEFAULT 0;
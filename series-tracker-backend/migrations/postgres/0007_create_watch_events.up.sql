@@ -0,0 +1,14 @@
+-- Historial de cambios de progreso y de estado de cada serie.
+CREATE TABLE IF NOT EXISTS watch_events (
+    id SERIAL PRIMARY KEY,
+    series_id INTEGER NOT NULL REFERENCES series (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    episode INTEGER NOT NULL,
+    previous_episode INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    previous_status VARCHAR(20) NOT NULL,
+    watched_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_watch_events_series_id ON watch_events (series_id);
+CREATE INDEX idx_watch_events_user_watched_at ON watch_events (user_id, watched_at);
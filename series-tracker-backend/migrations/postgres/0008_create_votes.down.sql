@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS votes;
+ALTER TABLE series DROP COLUMN legacy_ranking;
+ALTER TABLE series DROP COLUMN downvotes;
+ALTER TABLE series DROP COLUMN upvotes;
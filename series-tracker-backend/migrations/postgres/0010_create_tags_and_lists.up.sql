@@ -0,0 +1,38 @@
+-- Etiquetas (géneros o categorías) y listas personalizadas de cada usuario, con
+-- su relación muchos a muchos con las series.
+CREATE TABLE IF NOT EXISTS tags (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags (user_id, name);
+
+CREATE TABLE IF NOT EXISTS series_tags (
+    series_id INTEGER NOT NULL REFERENCES series (id) ON DELETE CASCADE,
+    tag_id INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (series_id, tag_id)
+);
+
+CREATE INDEX idx_series_tags_tag_id ON series_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS custom_lists (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_custom_lists_user_name ON custom_lists (user_id, name);
+
+CREATE TABLE IF NOT EXISTS custom_list_items (
+    list_id INTEGER NOT NULL REFERENCES custom_lists (id) ON DELETE CASCADE,
+    series_id INTEGER NOT NULL REFERENCES series (id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (list_id, series_id)
+);
+
+CREATE INDEX idx_custom_list_items_series_id ON custom_list_items (series_id);
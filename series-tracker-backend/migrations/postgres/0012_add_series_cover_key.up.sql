@@ -0,0 +1,3 @@
+-- Token de la portada subida por el usuario (ver POST /api/series/{id}/cover).
+-- Las imágenes se guardan en el almacenamiento de blobs bajo covers/{token}/.
+ALTER TABLE series ADD COLUMN cover_key VARCHAR(64) NOT NULL DEFAULT '';
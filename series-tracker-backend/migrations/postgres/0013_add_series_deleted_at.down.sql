@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_series_deleted_at;
+ALTER TABLE series DROP COLUMN deleted_at;
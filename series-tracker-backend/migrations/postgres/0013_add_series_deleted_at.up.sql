@@ -0,0 +1,4 @@
+-- Papelera: DELETE /api/series/{id} marca la serie como eliminada en lugar de
+-- borrarla. La purga en segundo plano borra las que superan el tiempo de retención.
+ALTER TABLE series ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+CREATE INDEX idx_series_deleted_at ON series (deleted_at);
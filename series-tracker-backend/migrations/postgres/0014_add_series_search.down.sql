@@ -0,0 +1,6 @@
+-- La extensión pg_trgm se conserva: otras bases o esquemas pueden usarla.
+DROP INDEX IF EXISTS idx_series_search_trigrams;
+DROP INDEX IF EXISTS idx_series_search_vector;
+ALTER TABLE series DROP COLUMN search_vector;
+ALTER TABLE series DROP COLUMN search_text;
+ALTER TABLE series DROP COLUMN alt_titles;
@@ -0,0 +1,17 @@
+-- Búsqueda de series (GET /api/series/search): títulos alternativos, un
+-- tsvector con el título y los títulos alternativos (configuración 'simple',
+-- sin raíces: los títulos mezclan idiomas) y un índice de trigramas (pg_trgm)
+-- sobre el mismo texto para tolerar errores de escritura. Las columnas
+-- generadas se mantienen solas al modificar la serie.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE series ADD COLUMN alt_titles TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE series ADD COLUMN search_text TEXT
+    GENERATED ALWAYS AS (title || ' ' || alt_titles) STORED;
+
+ALTER TABLE series ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', title || ' ' || alt_titles)) STORED;
+
+CREATE INDEX idx_series_search_vector ON series USING GIN (search_vector);
+CREATE INDEX idx_series_search_trigrams ON series USING GIN (search_text gin_trgm_ops);
@@ -0,0 +1,9 @@
+-- Tabla principal de series.
+CREATE TABLE IF NOT EXISTS series (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'Plan to Watch',
+    last_episode_watched INTEGER NOT NULL DEFAULT 0,
+    total_episodes INTEGER NOT NULL DEFAULT 0,
+    ranking INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,22 @@
+-- SQLite no permite eliminar una columna con clave foránea: se reconstruye la tabla.
+DROP INDEX IF EXISTS idx_series_user_id;
+
+CREATE TABLE series_old (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'Plan to Watch',
+    last_episode_watched INTEGER NOT NULL DEFAULT 0,
+    total_episodes INTEGER NOT NULL DEFAULT 0,
+    ranking INTEGER NOT NULL DEFAULT 0
+);
+
+INSERT INTO series_old (id, title, status, last_episode_watched, total_episodes, ranking)
+SELECT id, title, status, last_episode_watched, total_episodes, ranking FROM series;
+
+DROP TABLE series;
+
+ALTER TABLE series_old RENAME TO series;
+
+DROP TABLE IF EXISTS sessions;
+
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,21 @@
+-- Un voto (positivo o negativo) por usuario y serie. El ranking de la serie pasa
+-- a ser legacy_ranking + upvotes - downvotes.
+CREATE TABLE IF NOT EXISTS votes (
+    series_id INTEGER NOT NULL REFERENCES series (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    value INTEGER NOT NULL CHECK (value IN (-1, 1)),
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (series_id, user_id)
+);
+
+CREATE INDEX idx_votes_user_id ON votes (user_id);
+
+ALTER TABLE series ADD COLUMN upvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE series ADD COLUMN downvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE series ADD COLUMN legacy_ranking INTEGER NOT NULL DEFAULT 0;
+
+-- El ranking acumulado hasta ahora no registra quién votó, por lo que no puede
+-- convertirse en votos: se conserva en legacy_ranking y se suma a los votos
+-- nuevos, de modo que el ranking de cada serie no cambia al migrar.
+UPDATE series SET legacy_ranking = ranking;
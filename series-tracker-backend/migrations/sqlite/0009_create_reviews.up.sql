@@ -0,0 +1,13 @@
+-- Puntuación personal (1 a 10, en pasos de 0.5) y notas en Markdown de cada
+-- usuario sobre una serie.
+CREATE TABLE IF NOT EXISTS reviews (
+    series_id INTEGER NOT NULL REFERENCES series (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    score REAL CHECK (score BETWEEN 1 AND 10),
+    body TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (series_id, user_id)
+);
+
+CREATE INDEX idx_reviews_user_id ON reviews (user_id);
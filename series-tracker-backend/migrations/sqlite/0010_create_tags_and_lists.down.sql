@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS custom_list_items;
+DROP TABLE IF EXISTS custom_lists;
+DROP TABLE IF EXISTS series_tags;
+DROP TABLE IF EXISTS tags;
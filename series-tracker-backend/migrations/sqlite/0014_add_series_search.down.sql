@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS series_search_update;
+DROP TRIGGER IF EXISTS series_search_delete;
+DROP TRIGGER IF EXISTS series_search_insert;
+DROP TABLE IF EXISTS series_trigrams;
+DROP TABLE IF EXISTS series_fts;
+ALTER TABLE series DROP COLUMN alt_titles;
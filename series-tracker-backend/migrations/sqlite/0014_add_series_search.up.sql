@@ -0,0 +1,39 @@
+-- Búsqueda de series (GET /api/series/search): títulos alternativos y dos
+-- índices FTS5 sobre el título y los títulos alternativos, uno por palabras
+-- (sin acentos) y otro por trigramas para tolerar errores de escritura. Son
+-- tablas de contenido externo que los triggers mantienen al día.
+ALTER TABLE series ADD COLUMN alt_titles TEXT NOT NULL DEFAULT '';
+
+CREATE VIRTUAL TABLE series_fts USING fts5(
+    title, alt_titles, content='series', content_rowid='id', tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE VIRTUAL TABLE series_trigrams USING fts5(
+    title, alt_titles, content='series', content_rowid='id', tokenize='trigram'
+);
+
+INSERT INTO series_fts (series_fts) VALUES ('rebuild');
+INSERT INTO series_trigrams (series_trigrams) VALUES ('rebuild');
+
+-- +begin
+CREATE TRIGGER series_search_insert AFTER INSERT ON series BEGIN
+    INSERT INTO series_fts (rowid, title, alt_titles) VALUES (new.id, new.title, new.alt_titles);
+    INSERT INTO series_trigrams (rowid, title, alt_titles) VALUES (new.id, new.title, new.alt_titles);
+END;
+-- +end
+
+-- +begin
+CREATE TRIGGER series_search_delete AFTER DELETE ON series BEGIN
+    INSERT INTO series_fts (series_fts, rowid, title, alt_titles) VALUES ('delete', old.id, old.title, old.alt_titles);
+    INSERT INTO series_trigrams (series_trigrams, rowid, title, alt_titles) VALUES ('delete', old.id, old.title, old.alt_titles);
+END;
+-- +end
+
+-- +begin
+CREATE TRIGGER series_search_update AFTER UPDATE OF title, alt_titles ON series BEGIN
+    INSERT INTO series_fts (series_fts, rowid, title, alt_titles) VALUES ('delete', old.id, old.title, old.alt_titles);
+    INSERT INTO series_trigrams (series_trigrams, rowid, title, alt_titles) VALUES ('delete', old.id, old.title, old.alt_titles);
+    INSERT INTO series_fts (rowid, title, alt_titles) VALUES (new.id, new.title, new.alt_titles);
+    INSERT INTO series_trigrams (rowid, title, alt_titles) VALUES (new.id, new.title, new.alt_titles);
+END;
+-- +end
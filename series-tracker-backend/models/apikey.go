@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// APIKey representa una clave de API de un usuario, pensada para scripts e
+// integraciones. Solo se guarda el hash de la clave; el valor completo se
+// muestra una única vez al crearla.
+// @Description Clave de API (sin el valor secreto).
+type APIKey struct {
+	// ID es el identificador único de la clave.
+	// example: 1
+	ID int `json:"id" gorm:"primaryKey"`
+
+	// UserID es el dueño de la clave.
+	UserID int `json:"-" gorm:"not null;index"`
+
+	// Name es una descripción libre para reconocer la clave.
+	// example: "cron de sincronización"
+	Name string `json:"name" gorm:"not null"`
+
+	// Prefix son los primeros caracteres de la clave, útiles para identificarla.
+	// example: "stk_Ab12Cd34"
+	Prefix string `json:"prefix" gorm:"not null"`
+
+	// KeyHash es el hash SHA-256 de la clave. Nunca se serializa.
+	KeyHash string `json:"-" gorm:"not null;uniqueIndex"`
+
+	// Scope es el permiso de la clave: 'read' (solo lectura) o 'write' (lectura y escritura).
+	// example: "read"
+	Scope string `json:"scope" gorm:"not null"`
+
+	// CreatedAt es la fecha de creación de la clave.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// LastUsedAt es la última vez que se usó la clave (aproximada al minuto).
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	// RevokedAt indica cuándo se revocó la clave; una clave revocada no se acepta.
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// APIKeyCreate es el cuerpo esperado para crear una clave de API.
+// @Description Datos para crear una clave de API.
+type APIKeyCreate struct {
+	// Name es una descripción libre de la clave (obligatoria, hasta 100 caracteres).
+	// example: "bot de Discord"
+	// required: true
+	Name string `json:"name" binding:"required"`
+
+	// Scope es 'read' o 'write'. Por defecto 'read'.
+	// example: "write"
+	Scope string `json:"scope"`
+}
+
+// APIKeyCreated es la respuesta al crear una clave e incluye su valor completo.
+// @Description Clave de API recién creada; el valor de 'key' no se vuelve a mostrar.
+type APIKeyCreated struct {
+	APIKey
+	// Key es el valor secreto de la clave. Guárdelo: no se puede recuperar después.
+	// example: "stk_Ab12Cd34Ef56Gh78Ij90Kl12Mn34Op56Qr78St90Uv1"
+	Key string `json:"key"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Tag es una etiqueta del usuario (un género como "Isekai" o cualquier otra
+// categoría) que puede asignarse a varias series.
+// @Description Etiqueta o género del usuario.
+type Tag struct {
+	// ID es el identificador único de la etiqueta.
+	// example: 1
+	ID int `json:"id" gorm:"primaryKey"`
+
+	// UserID es el dueño de la etiqueta.
+	UserID int `json:"-" gorm:"not null;uniqueIndex:idx_tags_user_name"`
+
+	// Name es el nombre de la etiqueta, único por usuario (sin distinguir mayúsculas).
+	// example: "Isekai"
+	Name string `json:"name" gorm:"not null;uniqueIndex:idx_tags_user_name"`
+
+	// SeriesCount es la cantidad de series con la etiqueta. La calcula el servidor.
+	// example: 4
+	SeriesCount int64 `json:"seriesCount" gorm:"->"`
+
+	// CreatedAt es la fecha de creación de la etiqueta.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SeriesTag es la relación muchos a muchos entre series y etiquetas.
+type SeriesTag struct {
+	SeriesID int `gorm:"primaryKey;autoIncrement:false"`
+	TagID    int `gorm:"primaryKey;autoIncrement:false"`
+}
+
+// TagInput es el cuerpo para crear o renombrar una etiqueta.
+// @Description Datos de una etiqueta.
+type TagInput struct {
+	// Name es el nombre de la etiqueta (obligatorio, hasta 50 caracteres).
+	// example: "Isekai"
+	// required: true
+	Name string `json:"name" binding:"required"`
+}
+
+// CustomList es una lista con nombre creada por el usuario (por ejemplo
+// "Para ver con amigos") que agrupa series.
+// @Description Lista personalizada de series.
+type CustomList struct {
+	// ID es el identificador único de la lista.
+	// example: 1
+	ID int `json:"id" gorm:"primaryKey"`
+
+	// UserID es el dueño de la lista.
+	UserID int `json:"-" gorm:"not null;uniqueIndex:idx_custom_lists_user_name"`
+
+	// Name es el nombre de la lista, único por usuario (sin distinguir mayúsculas).
+	// example: "Para ver con amigos"
+	Name string `json:"name" gorm:"not null;uniqueIndex:idx_custom_lists_user_name"`
+
+	// Description es una descripción libre de la lista (opcional).
+	// example: "Series para el viernes"
+	Description string `json:"description" gorm:"type:text;not null"`
+
+	// SeriesCount es la cantidad de series de la lista. La calcula el servidor.
+	// example: 3
+	SeriesCount int64 `json:"seriesCount" gorm:"->"`
+
+	// CreatedAt y UpdatedAt son las fechas de creación y última modificación.
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CustomListItem es la pertenencia de una serie a una lista personalizada.
+type CustomListItem struct {
+	ListID   int `gorm:"primaryKey;autoIncrement:false"`
+	SeriesID int `gorm:"primaryKey;autoIncrement:false"`
+	// AddedAt es el momento en que la serie se agregó a la lista.
+	AddedAt time.Time
+}
+
+// CustomListInput es el cuerpo para crear o modificar una lista personalizada.
+// @Description Datos de una lista personalizada.
+type CustomListInput struct {
+	// Name es el nombre de la lista (obligatorio, hasta 100 caracteres).
+	// example: "Para ver con amigos"
+	// required: true
+	Name string `json:"name" binding:"required"`
+	// Description es una descripción libre (opcional, hasta 1000 caracteres).
+	// example: "Series para el viernes"
+	Description string `json:"description"`
+}
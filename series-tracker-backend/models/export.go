@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SeriesExport es una serie con los datos relacionados que incluye la
+// exportación completa: etiquetas, listas personalizadas, reseña e historial.
+// @Description Serie exportada con sus etiquetas, listas, reseña e historial de visualización.
+type SeriesExport struct {
+	Series
+
+	// Tags son los nombres de las etiquetas de la serie, en orden alfabético.
+	// example: ["Isekai","Fantasía"]
+	Tags []string `json:"tags"`
+
+	// Lists son los nombres de las listas personalizadas que contienen la serie.
+	// example: ["Favoritas"]
+	Lists []string `json:"lists"`
+
+	// Review es la reseña del usuario (null si no tiene).
+	Review *Review `json:"review"`
+
+	// History es el historial de visualización, del evento más antiguo al más reciente.
+	History []WatchEvent `json:"history"`
+}
+
+// ExportDocument es el documento JSON de GET /api/export?format=json.
+// @Description Exportación completa de la lista del usuario.
+type ExportDocument struct {
+	// ExportedAt es el instante de la exportación (UTC).
+	ExportedAt time.Time `json:"exportedAt"`
+
+	// Series son todas las series del usuario, por id ascendente.
+	Series []SeriesExport `json:"series"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"slices"
+	"strings"
+)
+
+// MediaType es el formato de emisión de una serie.
+type MediaType string
+
+// Formatos válidos. Una serie sin formato conocido lo deja vacío.
+const (
+	MediaTypeTV      MediaType = "TV"
+	MediaTypeMovie   MediaType = "Movie"
+	MediaTypeOVA     MediaType = "OVA"
+	MediaTypeONA     MediaType = "ONA"
+	MediaTypeSpecial MediaType = "Special"
+)
+
+// MediaTypes enumera los formatos válidos.
+var MediaTypes = []MediaType{MediaTypeTV, MediaTypeMovie, MediaTypeOVA, MediaTypeONA, MediaTypeSpecial}
+
+// Valid indica si t es vacío o uno de los formatos conocidos.
+func (t MediaType) Valid() bool {
+	return t == "" || slices.Contains(MediaTypes, t)
+}
+
+// AiringStatus es el estado de emisión de una serie (independiente del estado
+// de visualización del usuario).
+type AiringStatus string
+
+// Estados de emisión válidos. Una serie sin estado conocido lo deja vacío.
+const (
+	AiringNotYetAired AiringStatus = "Not Yet Aired"
+	AiringCurrently   AiringStatus = "Currently Airing"
+	AiringFinished    AiringStatus = "Finished Airing"
+)
+
+// AiringStatuses enumera los estados de emisión válidos.
+var AiringStatuses = []AiringStatus{AiringNotYetAired, AiringCurrently, AiringFinished}
+
+// Valid indica si s es vacío o uno de los estados de emisión conocidos.
+func (s AiringStatus) Valid() bool {
+	return s == "" || slices.Contains(AiringStatuses, s)
+}
+
+// quotedList devuelve los valores como texto entre comillas simples, separados por comas.
+func quotedList[T ~string](values []T) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + string(v) + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// MediaTypeList devuelve los formatos válidos como texto para mensajes de error.
+func MediaTypeList() string {
+	return quotedList(MediaTypes)
+}
+
+// AiringStatusList devuelve los estados de emisión válidos como texto para mensajes de error.
+func AiringStatusList() string {
+	return quotedList(AiringStatuses)
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Límites de la puntuación personal de una reseña.
+const (
+	MinScore = 1.0
+	MaxScore = 10.0
+)
+
+// Review es la puntuación personal y las notas de un usuario sobre una serie.
+// @Description Reseña personal de una serie: puntuación de 1 a 10 (admite medios puntos) y notas en Markdown.
+type Review struct {
+	// SeriesID es la serie reseñada.
+	// example: 1
+	SeriesID int `json:"seriesId" gorm:"primaryKey;autoIncrement:false"`
+
+	// UserID es el autor de la reseña.
+	UserID int `json:"-" gorm:"primaryKey;autoIncrement:false"`
+
+	// Score es la puntuación de 1 a 10 en pasos de 0.5 (opcional).
+	// example: 8.5
+	Score *float64 `json:"score"`
+
+	// Body son las notas o la reseña en formato Markdown (opcional).
+	// example: "La **segunda temporada** es mucho mejor."
+	Body string `json:"body" gorm:"type:text;not null"`
+
+	// CreatedAt y UpdatedAt son las fechas de creación y última modificación.
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ReviewInput es el cuerpo de "PUT /api/series/{id}/review".
+// @Description Puntuación y notas de la reseña; al menos uno de los dos campos es obligatorio.
+type ReviewInput struct {
+	// Score es la puntuación de 1 a 10 en pasos de 0.5; null o ausente para no puntuar.
+	// example: 8.5
+	Score *float64 `json:"score"`
+	// Body son las notas en Markdown.
+	// example: "La **segunda temporada** es mucho mejor."
+	Body string `json:"body"`
+}
+
+// ValidScore indica si score está entre MinScore y MaxScore y es múltiplo de 0.5.
+func ValidScore(score float64) bool {
+	doubled := score * 2
+	return score >= MinScore && score <= MaxScore && doubled == float64(int(doubled))
+}
@@ -0,0 +1,16 @@
+package models
+
+// SearchResult es una serie encontrada por "GET /api/series/search" con su relevancia.
+// @Description Serie encontrada por la búsqueda, con su relevancia y el título que coincidió.
+type SearchResult struct {
+	Series
+
+	// Score es la relevancia de la serie para la búsqueda, entre 0 y 1 (1 es el
+	// título exacto). Los resultados se ordenan de mayor a menor.
+	// example: 0.87
+	Score float64 `json:"score"`
+
+	// MatchedTitle es el título (principal o alternativo) que mejor coincidió.
+	// example: "Shingeki no Kyojin"
+	MatchedTitle string `json:"matchedTitle"`
+}
@@ -0,0 +1,231 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EpisodeCounts es la cantidad de episodios de cada temporada, en orden.
+// Se guarda en la base de datos como una lista separada por comas ("25,25,24").
+type EpisodeCounts []int
+
+// Value implementa driver.Valuer.
+func (c EpisodeCounts) Value() (driver.Value, error) {
+	parts := make([]string, len(c))
+	for i, n := range c {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Scan implementa sql.Scanner.
+func (c *EpisodeCounts) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("tipo no soportado para EpisodeCounts: %T", src)
+	}
+
+	*c = nil
+	if raw == "" {
+		return nil
+	}
+	for _, part := range strings.Split(raw, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return fmt.Errorf("valor inválido en EpisodeCounts: %q", part)
+		}
+		*c = append(*c, n)
+	}
+	return nil
+}
+
+// Total devuelve la suma de episodios de todas las temporadas.
+func (c EpisodeCounts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// EpisodeRef identifica un episodio por su número absoluto (Season = 0) o por
+// su número dentro de una temporada (Season >= 1).
+// @Description Episodio por número absoluto o relativo a una temporada.
+type EpisodeRef struct {
+	// Season es la temporada (opcional); si se indica, Episode es relativo a ella.
+	// example: 2
+	Season int `json:"season,omitempty"`
+	// Episode es el número de episodio (0 indica que no se vio ninguno).
+	// example: 5
+	Episode int `json:"episode"`
+}
+
+// EpisodeRange es un rango inclusivo de episodios. Con Season >= 1 los números
+// son relativos a esa temporada y, si se omiten From y To, abarca la temporada completa.
+// @Description Rango inclusivo de episodios, absoluto o dentro de una temporada.
+type EpisodeRange struct {
+	// Season es la temporada (opcional).
+	// example: 2
+	Season int `json:"season,omitempty"`
+	// From es el primer episodio del rango.
+	// example: 1
+	From int `json:"from,omitempty"`
+	// To es el último episodio del rango.
+	// example: 25
+	To int `json:"to,omitempty"`
+}
+
+// ProgressError describe un cambio de progreso inválido (episodio fuera de
+// rango, temporada inexistente, etc.) asociado a un campo de la solicitud.
+// La API lo traduce a una respuesta 422.
+type ProgressError struct {
+	Field   string
+	Message string
+}
+
+// Error implementa error.
+func (e *ProgressError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// SyncSeasons recalcula los campos derivados de la estructura de temporadas:
+// con SeasonEpisodes, Seasons es la cantidad de temporadas, TotalEpisodes su
+// suma, y CurrentSeason/CurrentEpisode indican la temporada y el número dentro
+// de ella del último episodio visto. Sin estructura de temporadas valen 0.
+func (s *Series) SyncSeasons() {
+	s.Seasons, s.CurrentSeason, s.CurrentEpisode = len(s.SeasonEpisodes), 0, 0
+	if s.Seasons == 0 {
+		return
+	}
+	s.TotalEpisodes = s.SeasonEpisodes.Total()
+
+	s.CurrentSeason, s.CurrentEpisode = 1, s.LastEpisodeWatched
+	for i, count := range s.SeasonEpisodes {
+		if s.CurrentEpisode <= count || i == len(s.SeasonEpisodes)-1 {
+			s.CurrentSeason = i + 1
+			return
+		}
+		s.CurrentEpisode -= count
+	}
+}
+
+// seasonOffset devuelve la cantidad de episodios anteriores a la temporada
+// season y la cantidad de episodios de esa temporada.
+func (s Series) seasonOffset(season int) (offset, count int, err error) {
+	if len(s.SeasonEpisodes) == 0 {
+		return 0, 0, &ProgressError{"season", "la serie no tiene estructura de temporadas (seasonEpisodes)"}
+	}
+	if season < 1 || season > len(s.SeasonEpisodes) {
+		return 0, 0, &ProgressError{"season", fmt.Sprintf("debe estar entre 1 y %d", len(s.SeasonEpisodes))}
+	}
+	for _, n := range s.SeasonEpisodes[:season-1] {
+		offset += n
+	}
+	return offset, s.SeasonEpisodes[season-1], nil
+}
+
+// ResolveEpisode convierte ref en un número de episodio absoluto y verifica que
+// esté dentro de los límites de la serie.
+func (s Series) ResolveEpisode(ref EpisodeRef) (int, error) {
+	episode := ref.Episode
+	if ref.Season != 0 {
+		offset, count, err := s.seasonOffset(ref.Season)
+		if err != nil {
+			return 0, err
+		}
+		if episode < 0 || episode > count {
+			return 0, &ProgressError{"episode", fmt.Sprintf("la temporada %d tiene %d episodios", ref.Season, count)}
+		}
+		episode += offset
+	}
+	if episode < 0 {
+		return 0, &ProgressError{"episode", "no puede ser negativo"}
+	}
+	if s.TotalEpisodes > 0 && episode > s.TotalEpisodes {
+		return 0, &ProgressError{"episode", fmt.Sprintf("no puede ser mayor que el total de episodios (%d)", s.TotalEpisodes)}
+	}
+	return episode, nil
+}
+
+// ResolveRange convierte r en un rango absoluto [from, to] de episodios.
+func (s Series) ResolveRange(r EpisodeRange) (from, to int, err error) {
+	from, to = r.From, r.To
+	if r.Season != 0 {
+		offset, count, err := s.seasonOffset(r.Season)
+		if err != nil {
+			return 0, 0, err
+		}
+		if from == 0 && to == 0 {
+			from, to = 1, count
+		}
+		if from < 1 || to > count {
+			return 0, 0, &ProgressError{"to", fmt.Sprintf("la temporada %d tiene %d episodios", r.Season, count)}
+		}
+		from, to = from+offset, to+offset
+	}
+	switch {
+	case from < 1:
+		return 0, 0, &ProgressError{"from", "debe ser mayor o igual a 1"}
+	case to < from:
+		return 0, 0, &ProgressError{"to", "debe ser mayor o igual a 'from'"}
+	case s.TotalEpisodes > 0 && to > s.TotalEpisodes:
+		return 0, 0, &ProgressError{"to", fmt.Sprintf("no puede ser mayor que el total de episodios (%d)", s.TotalEpisodes)}
+	}
+	return from, to, nil
+}
+
+// SetProgress establece el último episodio visto. Avanzar pasa la serie a
+// 'Watching' (o 'Completed' al llegar al final) y retroceder una serie
+// completada la devuelve a 'Watching', con TransitionTo.
+func (s *Series) SetProgress(ref EpisodeRef) error {
+	episode, err := s.ResolveEpisode(ref)
+	if err != nil {
+		return err
+	}
+	if episode > s.LastEpisodeWatched || (episode < s.LastEpisodeWatched && s.Status == StatusCompleted) {
+		if err := s.TransitionTo(StatusWatching); err != nil {
+			return err
+		}
+	}
+	s.LastEpisodeWatched = episode
+	s.ApplyProgressRules()
+	return nil
+}
+
+// WatchRange marca como vistos los episodios del rango r. Como el progreso es
+// un contador, el rango debe continuar el progreso actual (empezar como mucho
+// en el episodio siguiente al último visto).
+func (s *Series) WatchRange(r EpisodeRange) error {
+	from, to, err := s.ResolveRange(r)
+	if err != nil {
+		return err
+	}
+	if from > s.LastEpisodeWatched+1 {
+		return &ProgressError{"from", fmt.Sprintf("el rango deja episodios sin ver: debe empezar como mucho en el episodio %d", s.LastEpisodeWatched+1)}
+	}
+	if to <= s.LastEpisodeWatched {
+		return nil // Ya estaban vistos
+	}
+	return s.SetProgress(EpisodeRef{Episode: to})
+}
+
+// UnwatchLastEpisode resta un episodio visto. Devuelve false si no había
+// ninguno. Una serie completada vuelve a 'Watching'; si el progreso resultante
+// no es válido (por ejemplo, supera el total) devuelve el error de SetProgress.
+func (s *Series) UnwatchLastEpisode() (bool, error) {
+	if s.LastEpisodeWatched <= 0 {
+		return false, nil
+	}
+	if err := s.SetProgress(EpisodeRef{Episode: s.LastEpisodeWatched - 1}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
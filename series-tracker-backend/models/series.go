@@ -2,6 +2,8 @@
 // representando las entidades principales como las Series de TV.
 package models
 
+import "gorm.io/gorm"
+
 // Series representa la estructura de una serie de TV en la base de datos.
 // Contiene información sobre el título, estado de visualización, progreso y ranking.
 // @Description Estructura de datos para una Serie de TV.
@@ -10,15 +12,27 @@ type Series struct {
 	// example: 1
 	ID int `json:"id" gorm:"primaryKey"`
 
+	// UserID es el ID del usuario dueño de la serie. Lo asigna el servidor a partir
+	// del usuario autenticado; se ignora si viene en el cuerpo de la solicitud.
+	// example: 1
+	UserID int `json:"userId" gorm:"index"`
+
 	// Title es el título de la serie. Es un campo obligatorio.
 	// example: "Attack on Titan"
 	// required: true
 	Title string `json:"title" binding:"required" gorm:"not null"`
 
+	// AltTitles son los títulos alternativos de la serie (opcional): romaji,
+	// nativo, inglés, abreviaturas... La búsqueda (GET /api/series/search) los
+	// considera igual que el título.
+	// example: ["Shingeki no Kyojin","進撃の巨人"]
+	AltTitles Titles `json:"altTitles,omitempty" gorm:"type:text;not null" swaggertype:"array,string"`
+
 	// Status indica el estado actual de visualización de la serie.
-	// Debe ser uno de: 'Plan to Watch', 'Watching', 'Completed', 'Dropped'.
+	// Debe ser uno de: 'Plan to Watch', 'Watching', 'On Hold', 'Completed', 'Dropped'.
+	// Si se omite al crear, se usa 'Plan to Watch'.
 	// example: "Watching"
-	Status string `json:"status"`
+	Status SeriesStatus `json:"status"`
 
 	// LastEpisodeWatched es el número del último episodio que el usuario ha visto.
 	// example: 10
@@ -28,10 +42,83 @@ type Series struct {
 	// example: 24
 	TotalEpisodes int `json:"totalEpisodes"`
 
-	// Ranking es una puntuación o valoración asignada a la serie por el usuario.
-	// Puede ser modificada mediante los endpoints de upvote/downvote.
+	// SeasonEpisodes es la cantidad de episodios de cada temporada (opcional).
+	// Si se indica, TotalEpisodes se calcula como su suma y el progreso puede
+	// consultarse y modificarse por temporada.
+	// example: [25,25,24]
+	SeasonEpisodes EpisodeCounts `json:"seasonEpisodes,omitempty" gorm:"type:varchar(2000);not null;default:''" swaggertype:"array,integer"`
+
+	// Seasons es la cantidad de temporadas. Lo calcula el servidor a partir de SeasonEpisodes.
+	// example: 3
+	Seasons int `json:"seasons"`
+
+	// CurrentSeason es la temporada del último episodio visto y CurrentEpisode su
+	// número dentro de esa temporada. Los calcula el servidor (0 sin temporadas).
+	// example: 2
+	CurrentSeason int `json:"currentSeason"`
+	// example: 5
+	CurrentEpisode int `json:"currentEpisode"`
+
+	// CoverURL es la URL (http o https) de la imagen de portada (opcional). Al
+	// subir una portada el servidor la reemplaza por la ruta de la imagen original.
+	// example: "https://cdn.example.com/covers/attack-on-titan.jpg"
+	CoverURL string `json:"coverUrl" gorm:"type:varchar(2048);not null;default:''"`
+
+	// CoverKey es el token de la portada subida con POST /api/series/{id}/cover
+	// (vacío si no se subió ninguna). Lo asigna el servidor y no se expone.
+	CoverKey string `json:"-" gorm:"type:varchar(64);not null;default:''"`
+
+	// Synopsis es la sinopsis de la serie (opcional).
+	// example: "La humanidad vive tras enormes murallas que la protegen de los titanes."
+	Synopsis string `json:"synopsis" gorm:"type:text;not null"`
+
+	// StartYear y EndYear son los años de inicio y fin de emisión (opcionales).
+	// example: 2013
+	StartYear *int `json:"startYear"`
+	// example: 2023
+	EndYear *int `json:"endYear"`
+
+	// MediaType es el formato: 'TV', 'Movie', 'OVA', 'ONA' o 'Special' (vacío si no se conoce).
+	// example: "TV"
+	MediaType MediaType `json:"mediaType" gorm:"type:varchar(20);not null;default:''"`
+
+	// Studio es el estudio de animación o la productora (opcional).
+	// example: "Wit Studio"
+	Studio string `json:"studio" gorm:"not null;default:''"`
+
+	// AiringStatus es el estado de emisión: 'Not Yet Aired', 'Currently Airing' o
+	// 'Finished Airing' (vacío si no se conoce). No depende del progreso del usuario.
+	// example: "Finished Airing"
+	AiringStatus AiringStatus `json:"airingStatus" gorm:"type:varchar(20);not null;default:''"`
+
+	// Ranking es la puntuación agregada de los votos (LegacyRanking + Upvotes - Downvotes).
+	// La calcula el servidor; se ignora si viene en el cuerpo de la solicitud.
+	// Se modifica mediante los endpoints de voto (upvote/downvote/vote).
 	// example: 8
 	Ranking int `json:"ranking"`
+
+	// Upvotes y Downvotes son la cantidad de votos positivos y negativos (un voto por usuario).
+	// example: 9
+	Upvotes int `json:"upvotes" gorm:"not null;default:0"`
+	// example: 1
+	Downvotes int `json:"downvotes" gorm:"not null;default:0"`
+
+	// LegacyRanking es el ranking acumulado antes de que existieran los votos por
+	// usuario (migración 0008_create_votes); se conserva y se suma a los votos.
+	// Lo asigna el servidor y es 0 en las series creadas después.
+	// example: 0
+	LegacyRanking int `json:"legacyRanking" gorm:"not null;default:0"`
+
+	// Version es el número de versión de la serie; aumenta en cada modificación.
+	// Lo asigna el servidor y se expone también en el header ETag para el control
+	// de concurrencia optimista (If-Match).
+	// example: 3
+	Version int `json:"version" gorm:"not null;default:1"`
+
+	// DeletedAt es el momento en que la serie se movió a la papelera (nulo si no
+	// está eliminada). GORM excluye las series eliminadas de todas las consultas
+	// salvo las que usan Unscoped.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // StatusUpdate se usa específicamente para el endpoint "PATCH /api/series/{id}/status" para actualizar únicamente el estado de la serie de forma parcial.
@@ -40,5 +127,81 @@ type StatusUpdate struct {
 	// Status es el nuevo estado que se asignará a la serie. Campo obligatorio.
 	// example: "Completed"
 	// required: true
-	Status string `json:"status" binding:"required"`
+	Status SeriesStatus `json:"status" binding:"required"`
+}
+
+// SeriesSortFields relaciona los nombres de campo aceptados en el parámetro "sortBy"
+// de "GET /api/series" con la columna correspondiente en la base de datos.
+// "score" es la puntuación de la reseña del usuario (tabla reviews, ver Review).
+var SeriesSortFields = map[string]string{
+	"id":                 "id",
+	"title":              "title",
+	"status":             "status",
+	"lastEpisodeWatched": "last_episode_watched",
+	"totalEpisodes":      "total_episodes",
+	"ranking":            "ranking",
+	"score":              "reviews.score",
+}
+
+// SeriesQuery agrupa los criterios de filtrado, ordenamiento y paginación
+// que acepta el listado de series. Los campos vacíos o nil no filtran.
+type SeriesQuery struct {
+	// Search filtra por coincidencia parcial (sin distinguir mayúsculas) en el título.
+	Search string
+	// Status filtra por estado exacto de visualización.
+	Status SeriesStatus
+	// MinRanking y MaxRanking limitan el rango (inclusivo) del ranking.
+	MinRanking *int
+	MaxRanking *int
+	// TagIDs limita el listado a las series que tienen todas esas etiquetas.
+	TagIDs []int
+	// ListID limita el listado a las series de esa lista personalizada.
+	ListID *int
+	// SortBy es el nombre de campo (clave de SeriesSortFields) por el que se ordena.
+	SortBy string
+	// SortDesc invierte el orden a descendente.
+	SortDesc bool
+	// Page es el número de página (empezando en 1) y PageSize la cantidad de elementos por página.
+	Page     int
+	PageSize int
+}
+
+// Offset devuelve la cantidad de registros a saltar para la página solicitada.
+func (q SeriesQuery) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
+
+// SeriesMergePatch documenta el cuerpo JSON Merge Patch de "PATCH /api/series/{id}":
+// solo se modifican los campos presentes. No se usa para decodificar.
+// @Description Campos modificables con JSON Merge Patch; los omitidos no cambian.
+type SeriesMergePatch struct {
+	// example: "Attack on Titan"
+	Title *string `json:"title,omitempty"`
+	// example: ["Shingeki no Kyojin"]
+	AltTitles []string `json:"altTitles,omitempty"`
+	// example: "Watching"
+	Status *SeriesStatus `json:"status,omitempty"`
+	// example: 10
+	LastEpisodeWatched *int `json:"lastEpisodeWatched,omitempty"`
+	// example: 24
+	TotalEpisodes *int `json:"totalEpisodes,omitempty"`
+	// example: [25,25,24]
+	SeasonEpisodes []int `json:"seasonEpisodes,omitempty"`
+	// example: "https://cdn.example.com/covers/attack-on-titan.jpg"
+	CoverURL *string `json:"coverUrl,omitempty"`
+	// example: "La humanidad vive tras enormes murallas que la protegen de los titanes."
+	Synopsis *string `json:"synopsis,omitempty"`
+	// example: 2013
+	StartYear *int `json:"startYear,omitempty"`
+	// example: 2023
+	EndYear *int `json:"endYear,omitempty"`
+	// example: "TV"
+	MediaType *MediaType `json:"mediaType,omitempty"`
+	// example: "Wit Studio"
+	Studio *string `json:"studio,omitempty"`
+	// example: "Finished Airing"
+	AiringStatus *AiringStatus `json:"airingStatus,omitempty"`
 }
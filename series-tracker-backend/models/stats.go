@@ -0,0 +1,172 @@
+package models
+
+import "time"
+
+// Formatos de los períodos de las estadísticas: días y semanas se identifican
+// por su primer día (las semanas empiezan el lunes) y los meses por año y mes.
+const (
+	DayFormat   = "2006-01-02"
+	MonthFormat = "2006-01"
+)
+
+// StatsQuery indica cuántos períodos recientes incluir en cada serie temporal de
+// las estadísticas, contados hacia atrás desde Now (en UTC) e incluyendo el actual.
+type StatsQuery struct {
+	Now    time.Time
+	Days   int
+	Weeks  int
+	Months int
+}
+
+// DaysSince devuelve el inicio del primer día incluido en la serie diaria.
+func (q StatsQuery) DaysSince() time.Time {
+	return StartOfDay(q.Now).AddDate(0, 0, -(q.Days - 1))
+}
+
+// WeeksSince devuelve el inicio (lunes) de la primera semana incluida.
+func (q StatsQuery) WeeksSince() time.Time {
+	return StartOfWeek(q.Now).AddDate(0, 0, -7*(q.Weeks-1))
+}
+
+// MonthsSince devuelve el inicio del primer mes incluido.
+func (q StatsQuery) MonthsSince() time.Time {
+	return StartOfMonth(q.Now).AddDate(0, -(q.Months - 1), 0)
+}
+
+// StartOfDay devuelve el inicio (UTC) del día de t.
+func StartOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfWeek devuelve el inicio (UTC) del lunes de la semana de t.
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// StartOfMonth devuelve el inicio (UTC) del mes de t.
+func StartOfMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Stats reúne las estadísticas de visualización de un usuario.
+// @Description Estadísticas de visualización del usuario autenticado.
+type Stats struct {
+	// Totals son los totales generales.
+	Totals StatsTotals `json:"totals"`
+	// ByStatus agrupa las series por estado.
+	ByStatus []StatusStats `json:"byStatus"`
+	// EpisodesPerDay, EpisodesPerWeek y EpisodesPerMonth son los episodios vistos
+	// en cada período reciente, del más antiguo al más reciente (incluye los períodos sin actividad).
+	EpisodesPerDay   []PeriodCount `json:"episodesPerDay"`
+	EpisodesPerWeek  []PeriodCount `json:"episodesPerWeek"`
+	EpisodesPerMonth []PeriodCount `json:"episodesPerMonth"`
+	// AverageDaysToFinish es el tiempo medio, en días, entre el primer cambio de
+	// progreso de una serie y su paso a 'Completed'. Es null si no se completó ninguna.
+	// example: 12.5
+	AverageDaysToFinish *float64 `json:"averageDaysToFinish"`
+	// Streaks son las rachas de días consecutivos viendo al menos un episodio.
+	Streaks StreakStats `json:"streaks"`
+}
+
+// StatsTotals son los totales generales de las estadísticas.
+// @Description Totales de series, episodios y eventos del historial.
+type StatsTotals struct {
+	// Series es la cantidad de series.
+	// example: 42
+	Series int64 `json:"series"`
+	// EpisodesWatched es la suma del progreso actual (lastEpisodeWatched) de todas las series.
+	// example: 830
+	EpisodesWatched int64 `json:"episodesWatched"`
+	// TotalEpisodes es la suma de los episodios conocidos (totalEpisodes) de todas las series.
+	// example: 1200
+	TotalEpisodes int64 `json:"totalEpisodes"`
+	// HistoryEpisodes es la cantidad de episodios vistos según el historial (incluye los vueltos a ver).
+	// example: 910
+	HistoryEpisodes int64 `json:"historyEpisodes"`
+	// Events es la cantidad de eventos del historial.
+	// example: 315
+	Events int64 `json:"events"`
+}
+
+// StatusStats son las estadísticas de las series en un estado.
+// @Description Estadísticas de las series en un estado.
+type StatusStats struct {
+	// example: "Watching"
+	Status SeriesStatus `json:"status"`
+	// Series es la cantidad de series en el estado.
+	// example: 7
+	Series int64 `json:"series"`
+	// Share es la fracción (0 a 1) del total de series que está en el estado.
+	// example: 0.25
+	Share float64 `json:"share"`
+	// CompletionRate es el progreso medio (0 a 1, lastEpisodeWatched / totalEpisodes)
+	// de las series del estado con total de episodios conocido. Es null si no hay ninguna.
+	// example: 0.4
+	CompletionRate *float64 `json:"completionRate"`
+}
+
+// PeriodCount es la cantidad de episodios vistos en un período.
+// @Description Episodios vistos en un día, semana (desde el lunes) o mes.
+type PeriodCount struct {
+	// Period es el día o la semana ("2006-01-02") o el mes ("2006-01").
+	// example: "2024-05-13"
+	Period string `json:"period"`
+	// example: 6
+	Episodes int64 `json:"episodes"`
+}
+
+// Streak es una racha de días consecutivos con episodios vistos.
+// @Description Racha de días consecutivos (UTC) viendo episodios.
+type Streak struct {
+	// example: 5
+	Days int `json:"days"`
+	// example: "2024-05-09"
+	Start string `json:"start"`
+	// example: "2024-05-13"
+	End string `json:"end"`
+}
+
+// StreakStats reúne la racha más larga y la actual.
+// @Description Rachas de visualización.
+type StreakStats struct {
+	// Longest es la racha más larga (null si no hay historial).
+	Longest *Streak `json:"longest"`
+	// Current es la racha que termina hoy o ayer (null si no hay ninguna en curso).
+	Current *Streak `json:"current"`
+}
+
+// FillPeriods devuelve una entrada por cada período desde since hasta now
+// (inclusive), tomando los episodios de counts (indexado por período) o 0.
+// step avanza de un período al siguiente y format da su identificador.
+func FillPeriods(counts map[string]int64, since, now time.Time, step func(time.Time) time.Time, format string) []PeriodCount {
+	periods := []PeriodCount{}
+	for t := since; !t.After(now); t = step(t) {
+		key := t.Format(format)
+		periods = append(periods, PeriodCount{Period: key, Episodes: counts[key]})
+	}
+	return periods
+}
+
+// PickStreaks elige la racha más larga y la actual (la que termina el día de
+// now o el anterior) entre streaks.
+func PickStreaks(streaks []Streak, now time.Time) StreakStats {
+	var stats StreakStats
+	today := StartOfDay(now)
+	recent := map[string]bool{
+		today.Format(DayFormat):                   true,
+		today.AddDate(0, 0, -1).Format(DayFormat): true,
+	}
+	for i := range streaks {
+		s := &streaks[i]
+		if stats.Longest == nil || s.Days > stats.Longest.Days {
+			stats.Longest = s
+		}
+		if recent[s.End] {
+			stats.Current = s
+		}
+	}
+	return stats
+}
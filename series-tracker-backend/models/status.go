@@ -0,0 +1,137 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SeriesStatus es el estado de visualización de una serie.
+type SeriesStatus string
+
+// Estados de visualización válidos.
+const (
+	StatusPlanToWatch SeriesStatus = "Plan to Watch"
+	StatusWatching    SeriesStatus = "Watching"
+	StatusOnHold      SeriesStatus = "On Hold"
+	StatusCompleted   SeriesStatus = "Completed"
+	StatusDropped     SeriesStatus = "Dropped"
+)
+
+// SeriesStatuses enumera los estados válidos en su orden natural de progreso.
+var SeriesStatuses = []SeriesStatus{StatusPlanToWatch, StatusWatching, StatusOnHold, StatusCompleted, StatusDropped}
+
+// statusTransitions define a qué estados se puede pasar manualmente desde cada
+// estado. Mantener el mismo estado siempre está permitido.
+var statusTransitions = map[SeriesStatus][]SeriesStatus{
+	StatusPlanToWatch: {StatusWatching, StatusOnHold, StatusCompleted, StatusDropped},
+	StatusWatching:    {StatusOnHold, StatusCompleted, StatusDropped},
+	StatusOnHold:      {StatusWatching, StatusCompleted, StatusDropped},
+	StatusCompleted:   {StatusWatching},
+	StatusDropped:     {StatusPlanToWatch, StatusWatching},
+}
+
+// Valid indica si s es uno de los estados conocidos.
+func (s SeriesStatus) Valid() bool {
+	return slices.Contains(SeriesStatuses, s)
+}
+
+// CanTransitionTo indica si está permitido pasar manualmente del estado s a next.
+func (s SeriesStatus) CanTransitionTo(next SeriesStatus) bool {
+	return s == next || slices.Contains(statusTransitions[s], next)
+}
+
+// StatusError describe un estado desconocido o un cambio de estado no permitido.
+// La API lo traduce a una respuesta 422.
+type StatusError struct {
+	From SeriesStatus
+	To   SeriesStatus
+}
+
+// Error implementa error.
+func (e *StatusError) Error() string {
+	if !e.To.Valid() {
+		return fmt.Sprintf("estado inválido: %q (use uno de: %s)", e.To, quotedList(SeriesStatuses))
+	}
+	allowed := statusTransitions[e.From]
+	if len(allowed) == 0 {
+		return fmt.Sprintf("no se puede cambiar el estado de '%s' a '%s'", e.From, e.To)
+	}
+	return fmt.Sprintf("no se puede cambiar el estado de '%s' a '%s' (permitidos: %s)", e.From, e.To, quotedList(allowed))
+}
+
+// ValidateStatus devuelve un *StatusError si status no es un estado conocido.
+func ValidateStatus(status SeriesStatus) error {
+	if !status.Valid() {
+		return &StatusError{To: status}
+	}
+	return nil
+}
+
+// ValidateTransition devuelve un *StatusError si no se puede pasar del estado
+// from al estado to. Si from no es un estado conocido (datos anteriores a la
+// validación) se acepta cualquier estado válido.
+func ValidateTransition(from, to SeriesStatus) error {
+	if !to.Valid() || (from.Valid() && !from.CanTransitionTo(to)) {
+		return &StatusError{From: from, To: to}
+	}
+	return nil
+}
+
+// TransitionTo cambia el estado de la serie a next si la transición está
+// permitida. Al marcarla como 'Completed' se considera vista hasta el último
+// episodio conocido; los demás cambios conservan el progreso (para volver a
+// verla desde el principio se usa Rewatch).
+func (s *Series) TransitionTo(next SeriesStatus) error {
+	if err := ValidateTransition(s.Status, next); err != nil {
+		return err
+	}
+	if next == StatusCompleted && s.TotalEpisodes > 0 {
+		s.LastEpisodeWatched = s.TotalEpisodes
+	}
+	s.Status = next
+	s.SyncSeasons()
+	return nil
+}
+
+// Rewatch empieza a ver de nuevo una serie completada: pasa a 'Watching' con
+// el progreso en 0. Devuelve un *ProgressError si la serie no está completada.
+func (s *Series) Rewatch() error {
+	if s.Status != StatusCompleted {
+		return &ProgressError{"status", "solo se puede volver a ver una serie 'Completed'"}
+	}
+	s.Status = StatusWatching
+	s.LastEpisodeWatched = 0
+	s.SyncSeasons()
+	return nil
+}
+
+// WatchNextEpisode registra un episodio más visto. Devuelve false si ya se
+// vieron todos los episodios (cuando el total es conocido). Ver un episodio
+// pasa la serie a 'Watching' con TransitionTo (permitido desde cualquier
+// estado) y, al llegar al último, a 'Completed'.
+func (s *Series) WatchNextEpisode() (bool, error) {
+	if s.TotalEpisodes > 0 && s.LastEpisodeWatched >= s.TotalEpisodes {
+		return false, nil
+	}
+	if err := s.TransitionTo(StatusWatching); err != nil {
+		return false, err
+	}
+	s.LastEpisodeWatched++
+	s.ApplyProgressRules()
+	return true, nil
+}
+
+// ApplyProgressRules ajusta el estado según el progreso: una serie en curso
+// (o pendiente) cuyo último episodio visto alcanza el total pasa a 'Completed'.
+// Las series abandonadas conservan su estado. También recalcula los campos
+// derivados de las temporadas (ver SyncSeasons).
+func (s *Series) ApplyProgressRules() {
+	s.SyncSeasons()
+	if s.TotalEpisodes <= 0 || s.LastEpisodeWatched < s.TotalEpisodes {
+		return
+	}
+	switch s.Status {
+	case StatusPlanToWatch, StatusWatching, StatusOnHold:
+		s.Status = StatusCompleted
+	}
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+)
+
+// Titles es una lista de títulos alternativos de una serie (romaji, nativo,
+// inglés, abreviaturas...). Se guarda en la base de datos como texto con un
+// título por línea, que los índices de búsqueda tratan como palabras sueltas.
+type Titles []string
+
+// Value implementa driver.Valuer.
+func (t Titles) Value() (driver.Value, error) {
+	return strings.Join(t, "\n"), nil
+}
+
+// Scan implementa sql.Scanner.
+func (t *Titles) Scan(src any) error {
+	var raw string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("tipo no soportado para Titles: %T", src)
+	}
+
+	*t = nil
+	if raw == "" {
+		return nil
+	}
+	*t = strings.Split(raw, "\n")
+	return nil
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TrashedSeries es una serie de la papelera, con las fechas de eliminación y
+// de purga definitiva.
+// @Description Serie en la papelera; puede restaurarse hasta 'purgeAt'.
+type TrashedSeries struct {
+	Series
+
+	// DeletedAt es el momento en que la serie se movió a la papelera (UTC).
+	DeletedAt time.Time `json:"deletedAt"`
+
+	// PurgeAt es el momento a partir del cual la serie se elimina definitivamente (UTC).
+	PurgeAt time.Time `json:"purgeAt"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// User representa una cuenta de usuario. Cada usuario tiene su propia lista de series.
+// @Description Cuenta de usuario (la contraseña nunca se incluye en las respuestas).
+type User struct {
+	// ID es el identificador único del usuario.
+	// example: 1
+	ID int `json:"id" gorm:"primaryKey"`
+
+	// Username es el nombre de usuario, único en el sistema.
+	// example: "jonialen"
+	Username string `json:"username" gorm:"not null;uniqueIndex"`
+
+	// PasswordHash es el hash bcrypt de la contraseña. Nunca se serializa.
+	PasswordHash string `json:"-" gorm:"not null"`
+
+	// CreatedAt es la fecha de registro del usuario.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Session representa una sesión iniciada por un usuario. Solo se guarda el hash
+// del token entregado al cliente.
+type Session struct {
+	ID        int    `gorm:"primaryKey"`
+	UserID    int    `gorm:"not null;index"`
+	TokenHash string `gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+// Credentials es el cuerpo esperado por los endpoints de registro e inicio de sesión.
+// @Description Credenciales de acceso de un usuario.
+type Credentials struct {
+	// Username es el nombre de usuario (3 a 50 caracteres: letras, números, '.', '_' o '-').
+	// example: "jonialen"
+	// required: true
+	Username string `json:"username" binding:"required"`
+
+	// Password es la contraseña en texto plano (mínimo 8 caracteres).
+	// example: "s3cr3t-pass"
+	// required: true
+	Password string `json:"password" binding:"required"`
+}
+
+// AuthResponse es la respuesta de registro e inicio de sesión.
+// @Description Token de sesión emitido para el usuario autenticado.
+type AuthResponse struct {
+	// Token debe enviarse en el header "Authorization: Bearer <token>".
+	// example: "T2hJbUFUb2tlbkV4YW1wbGVWYWx1ZUZvckRvY3M"
+	Token string `json:"token"`
+
+	// ExpiresAt es el instante en que el token deja de ser válido.
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// User contiene los datos públicos del usuario autenticado.
+	User User `json:"user"`
+}
@@ -0,0 +1,106 @@
+package models
+
+import "time"
+
+// Valores posibles de un voto.
+const (
+	VoteUp   = 1
+	VoteDown = -1
+	// VoteNone indica que el usuario no votó (o retiró su voto).
+	VoteNone = 0
+)
+
+// Vote es el voto de un usuario sobre una serie. Cada usuario tiene como mucho
+// un voto por serie.
+type Vote struct {
+	SeriesID  int       `gorm:"primaryKey;autoIncrement:false"`
+	UserID    int       `gorm:"primaryKey;autoIncrement:false"`
+	Value     int       `gorm:"not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+// VoteValue es el cuerpo de "PUT /api/series/{id}/vote" y la respuesta de
+// "GET /api/series/{id}/vote".
+// @Description Voto del usuario: 1 (positivo), -1 (negativo) o 0 (sin voto).
+type VoteValue struct {
+	// example: 1
+	Value int `json:"value" enums:"-1,0,1"`
+}
+
+// PublicSeries son los datos de una serie que ven los usuarios que no son su
+// dueño: los descriptivos y el ranking, sin el estado ni el progreso del dueño.
+// @Description Serie de otro usuario (votos y ranking de todos los usuarios).
+type PublicSeries struct {
+	// example: 1
+	ID int `json:"id"`
+	// example: "Shingeki no Kyojin"
+	Title     string    `json:"title"`
+	AltTitles Titles    `json:"altTitles,omitempty" swaggertype:"array,string"`
+	CoverURL  string    `json:"coverUrl"`
+	Synopsis  string    `json:"synopsis"`
+	StartYear *int      `json:"startYear"`
+	EndYear   *int      `json:"endYear"`
+	MediaType MediaType `json:"mediaType"`
+	Studio    string    `json:"studio"`
+	// example: 8
+	Ranking int `json:"ranking"`
+	// example: 9
+	Upvotes int `json:"upvotes"`
+	// example: 1
+	Downvotes int `json:"downvotes"`
+	// example: 0
+	LegacyRanking int `json:"legacyRanking"`
+}
+
+// Public devuelve los datos de la serie visibles para otros usuarios.
+func (s Series) Public() PublicSeries {
+	return PublicSeries{
+		ID:            s.ID,
+		Title:         s.Title,
+		AltTitles:     s.AltTitles,
+		CoverURL:      s.CoverURL,
+		Synopsis:      s.Synopsis,
+		StartYear:     s.StartYear,
+		EndYear:       s.EndYear,
+		MediaType:     s.MediaType,
+		Studio:        s.Studio,
+		Ranking:       s.Ranking,
+		Upvotes:       s.Upvotes,
+		Downvotes:     s.Downvotes,
+		LegacyRanking: s.LegacyRanking,
+	}
+}
+
+// ValidVote indica si value es un valor de voto aceptado (incluido VoteNone).
+func ValidVote(value int) bool {
+	return value == VoteUp || value == VoteDown || value == VoteNone
+}
+
+// ToggleVote devuelve el voto resultante de pulsar value cuando el voto actual
+// es current: el mismo voto se retira y uno distinto lo reemplaza.
+func ToggleVote(current, value int) int {
+	if current == value {
+		return VoteNone
+	}
+	return value
+}
+
+// ApplyVote actualiza los contadores de votos y el ranking de la serie cuando
+// un usuario cambia su voto de previous a next. El ranking anterior a los votos
+// (LegacyRanking) se mantiene como punto de partida.
+func (s *Series) ApplyVote(previous, next int) {
+	switch previous {
+	case VoteUp:
+		s.Upvotes--
+	case VoteDown:
+		s.Downvotes--
+	}
+	switch next {
+	case VoteUp:
+		s.Upvotes++
+	case VoteDown:
+		s.Downvotes++
+	}
+	s.Ranking = s.LegacyRanking + s.Upvotes - s.Downvotes
+}
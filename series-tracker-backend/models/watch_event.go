@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// WatchEvent registra un cambio de progreso de una serie: el último episodio
+// visto y el estado antes y después del cambio. Se crea automáticamente en
+// cada modificación del progreso y permite deshacer el último cambio.
+// @Description Evento del historial de visualización de una serie.
+type WatchEvent struct {
+	// ID es el identificador único del evento.
+	// example: 15
+	ID int `json:"id" gorm:"primaryKey"`
+
+	// SeriesID es la serie modificada.
+	// example: 1
+	SeriesID int `json:"seriesId" gorm:"not null;index"`
+
+	// UserID es el usuario que hizo el cambio (el dueño de la serie).
+	// example: 1
+	UserID int `json:"userId" gorm:"not null;index"`
+
+	// Episode es el último episodio visto después del cambio.
+	// example: 11
+	Episode int `json:"episode"`
+
+	// PreviousEpisode es el último episodio visto antes del cambio.
+	// example: 10
+	PreviousEpisode int `json:"previousEpisode"`
+
+	// Status es el estado de la serie después del cambio.
+	// example: "Watching"
+	Status SeriesStatus `json:"status"`
+
+	// PreviousStatus es el estado de la serie antes del cambio.
+	// example: "Watching"
+	PreviousStatus SeriesStatus `json:"previousStatus"`
+
+	// WatchedAt es el instante del cambio (UTC).
+	WatchedAt time.Time `json:"watchedAt" gorm:"not null;index"`
+}
+
+// ProgressChanged indica si el progreso o el estado de la serie son distintos
+// de los de before.
+func (s Series) ProgressChanged(before Series) bool {
+	return s.LastEpisodeWatched != before.LastEpisodeWatched || s.Status != before.Status
+}
+
+// NewWatchEvent devuelve el evento que lleva la serie del estado before al
+// estado after, o nil si no cambiaron ni el progreso ni el estado.
+func NewWatchEvent(before, after Series, at time.Time) *WatchEvent {
+	if !after.ProgressChanged(before) {
+		return nil
+	}
+	return &WatchEvent{
+		SeriesID:        after.ID,
+		UserID:          after.UserID,
+		Episode:         after.LastEpisodeWatched,
+		PreviousEpisode: before.LastEpisodeWatched,
+		Status:          after.Status,
+		PreviousStatus:  before.Status,
+		WatchedAt:       at.UTC(),
+	}
+}
+
+// Undo devuelve la serie al progreso y estado anteriores al evento e. Si
+// desde entonces el total de episodios bajó, el progreso restaurado se limita
+// al nuevo total.
+func (s *Series) Undo(e WatchEvent) {
+	s.LastEpisodeWatched = e.PreviousEpisode
+	if s.TotalEpisodes > 0 && s.LastEpisodeWatched > s.TotalEpisodes {
+		s.LastEpisodeWatched = s.TotalEpisodes
+	}
+	s.Status = e.PreviousStatus
+	s.SyncSeasons()
+}
+
+// HistoryQuery indica la página del historial de una serie que se solicita.
+type HistoryQuery struct {
+	// Page es el número de página (empezando en 1) y PageSize la cantidad de eventos por página.
+	Page     int
+	PageSize int
+}
+
+// Offset devuelve la cantidad de eventos a saltar para la página solicitada.
+func (q HistoryQuery) Offset() int {
+	if q.Page < 1 {
+		return 0
+	}
+	return (q.Page - 1) * q.PageSize
+}
@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"lab6/migrations"
+	"lab6/models"
+)
+
+// newSQLiteDB abre una base SQLite temporal con las migraciones aplicadas y la
+// misma configuración que InitDB (claves foráneas y una única conexión).
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+		filepath.Join(t.TempDir(), "test.db"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("abriendo SQLite: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("obteniendo la conexión: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatalf("cargando migraciones: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("aplicando migraciones: %v", err)
+	}
+	return db
+}
+
+// newExternalDB abre la base MySQL o PostgreSQL de la variable de entorno env
+// (un DSN, por ejemplo TEST_MYSQL_DSN) con varias conexiones y las migraciones
+// aplicadas, y crea un usuario nuevo. Omite el test si env no está definida.
+// A diferencia de SQLite, que serializa las escrituras en una sola conexión e
+// ignora FOR UPDATE, estos motores ejercitan el lock de fila de lockSeries.
+func newExternalDB(t *testing.T, env string, open func(dsn string) gorm.Dialector) (*gorm.DB, int) {
+	t.Helper()
+	dsn := os.Getenv(env)
+	if dsn == "" {
+		t.Skipf("%s no está definida", env)
+	}
+	db, err := gorm.Open(open(dsn), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatalf("abriendo %s: %v", env, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("obteniendo la conexión: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(20)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	migrator, err := migrations.New(db)
+	if err != nil {
+		t.Fatalf("cargando migraciones: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("aplicando migraciones: %v", err)
+	}
+	// La base puede reutilizarse entre ejecuciones: cada test usa su propio usuario
+	user := models.User{Username: fmt.Sprintf("test-%d", time.Now().UnixNano()), PasswordHash: "x"}
+	if err := NewGormUserRepository(db).CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("creando el usuario: %v", err)
+	}
+	return db, user.ID
+}
+
+// testIncrementWaitsForRowLock mantiene abierta una transacción que bloquea la
+// serie (lockSeries) mientras otra conexión la incrementa: el incremento debe
+// esperar a que la transacción confirme y partir de su resultado, en lugar de
+// leer el valor anterior y pisarlo (actualización perdida).
+func testIncrementWaitsForRowLock(t *testing.T, db *gorm.DB, userID int) {
+	ctx := context.Background()
+	repo := NewGormSeriesRepository(db)
+	serie := models.Series{UserID: userID, Title: "Frieren", TotalEpisodes: 12}
+	if err := repo.Create(ctx, &serie); err != nil {
+		t.Fatalf("creando la serie: %v", err)
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+	locked, err := lockSeries(tx, userID, serie.ID)
+	if err != nil {
+		t.Fatalf("bloqueando la serie: %v", err)
+	}
+
+	done := make(chan models.Series, 1)
+	go func() {
+		got, err := repo.IncrementEpisode(ctx, userID, serie.ID)
+		if err != nil {
+			t.Errorf("IncrementEpisode: %v", err)
+		}
+		done <- got
+	}()
+	select {
+	case got := <-done:
+		t.Fatalf("IncrementEpisode no esperó el lock de la fila (episodio %d)", got.LastEpisodeWatched)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	locked.LastEpisodeWatched++
+	locked.Version++
+	if err := saveProgress(tx, &locked); err != nil {
+		t.Fatalf("guardando el progreso: %v", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		t.Fatalf("confirmando la transacción: %v", err)
+	}
+
+	got := <-done
+	if got.LastEpisodeWatched != 2 || got.Version != 3 {
+		t.Errorf("después del incremento: episodio %d, versión %d; se esperaba 2 y 3 (actualización perdida)",
+			got.LastEpisodeWatched, got.Version)
+	}
+}
+
+// testIncrementEpisodeConcurrent lanza más incrementos concurrentes que
+// episodios tiene la serie y comprueba que el tope nunca se supera y que
+// exactamente TotalEpisodes incrementos cambian la serie.
+func testIncrementEpisodeConcurrent(t *testing.T, repo SeriesRepository, userID int) {
+	const totalEpisodes, workers = 12, 40
+	ctx := context.Background()
+
+	serie := models.Series{UserID: userID, Title: "Frieren", TotalEpisodes: totalEpisodes}
+	if err := repo.Create(ctx, &serie); err != nil {
+		t.Fatalf("creando la serie: %v", err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		versions = map[int]int{} // versión resultante -> episodio visto
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := repo.IncrementEpisode(ctx, userID, serie.ID)
+			if err != nil {
+				t.Errorf("IncrementEpisode: %v", err)
+				return
+			}
+			if got.LastEpisodeWatched > totalEpisodes {
+				t.Errorf("lastEpisodeWatched = %d, supera el total %d", got.LastEpisodeWatched, totalEpisodes)
+			}
+			mu.Lock()
+			versions[got.Version] = got.LastEpisodeWatched
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// Cada incremento efectivo deja una versión distinta (de 2 a totalEpisodes+1)
+	// y los que llegan con la serie completa no la cambian
+	for episode := 1; episode <= totalEpisodes; episode++ {
+		if got, ok := versions[episode+1]; !ok || got != episode {
+			t.Errorf("versión %d: episodio %d (presente: %t), se esperaba %d", episode+1, got, ok, episode)
+		}
+	}
+	if len(versions) != totalEpisodes {
+		t.Errorf("se observaron %d versiones distintas, se esperaban %d", len(versions), totalEpisodes)
+	}
+
+	final, err := repo.Get(ctx, userID, serie.ID)
+	if err != nil {
+		t.Fatalf("obteniendo la serie: %v", err)
+	}
+	if final.LastEpisodeWatched != totalEpisodes || final.Status != models.StatusCompleted {
+		t.Errorf("serie final: episodio %d, estado %q; se esperaba %d y %q",
+			final.LastEpisodeWatched, final.Status, totalEpisodes, models.StatusCompleted)
+	}
+	if final.Version != totalEpisodes+1 {
+		t.Errorf("versión final = %d, se esperaba %d", final.Version, totalEpisodes+1)
+	}
+
+	events, total, err := repo.History(ctx, userID, serie.ID, models.HistoryQuery{Page: 1, PageSize: workers})
+	if err != nil {
+		t.Fatalf("obteniendo el historial: %v", err)
+	}
+	if total != totalEpisodes || len(events) != totalEpisodes {
+		t.Errorf("historial con %d eventos (%d devueltos), se esperaban %d", total, len(events), totalEpisodes)
+	}
+}
+
+// TestGormIncrementEpisodeConcurrent usa SQLite con una sola conexión, por lo
+// que solo comprueba la lógica del tope y del historial; el lock de fila lo
+// ejercitan los tests de MySQL y PostgreSQL.
+func TestGormIncrementEpisodeConcurrent(t *testing.T) {
+	db := newSQLiteDB(t)
+	user := models.User{Username: "ana", PasswordHash: "x"}
+	if err := NewGormUserRepository(db).CreateUser(context.Background(), &user); err != nil {
+		t.Fatalf("creando el usuario: %v", err)
+	}
+	testIncrementEpisodeConcurrent(t, NewGormSeriesRepository(db), user.ID)
+}
+
+func TestMemoryIncrementEpisodeConcurrent(t *testing.T) {
+	testIncrementEpisodeConcurrent(t, NewMemorySeriesRepository(), 1)
+}
+
+func TestMySQLIncrementEpisodeRowLock(t *testing.T) {
+	db, userID := newExternalDB(t, "TEST_MYSQL_DSN", mysql.Open)
+	t.Run("concurrente", func(t *testing.T) { testIncrementEpisodeConcurrent(t, NewGormSeriesRepository(db), userID) })
+	t.Run("espera el lock", func(t *testing.T) { testIncrementWaitsForRowLock(t, db, userID) })
+}
+
+func TestPostgresIncrementEpisodeRowLock(t *testing.T) {
+	db, userID := newExternalDB(t, "TEST_POSTGRES_DSN", postgres.Open)
+	t.Run("concurrente", func(t *testing.T) { testIncrementEpisodeConcurrent(t, NewGormSeriesRepository(db), userID) })
+	t.Run("espera el lock", func(t *testing.T) { testIncrementWaitsForRowLock(t, db, userID) })
+}
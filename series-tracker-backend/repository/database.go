@@ -6,73 +6,121 @@ import (
 	"fmt"
 	"log"
 	"os" // Importar os para leer variables de entorno
+	"strings"
 
+	"github.com/glebarez/sqlite" // Driver SQLite en Go puro (no requiere CGO)
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"lab6/models" // Asegúrate que la ruta de importación sea correcta
 )
 
-// DB es la instancia global y exportada de la conexión a la base de datos GORM.
-// Otros paquetes la usarán para realizar operaciones en la base de datos.
-var DB *gorm.DB
+// Motores de base de datos soportados en la variable de entorno DB_DRIVER.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
 
-// InitDB inicializa la conexión con la base de datos MySQL usando GORM.
-// Lee la cadena de conexión (DSN) preferentemente desde variables de entorno
-// y realiza la automigración del modelo Series.
-// Termina la aplicación si la conexión o la migración fallan.
-func InitDB() {
-	// Leer configuración de la base de datos desde variables de entorno
-	dbUser := os.Getenv("DB_USER")
-	dbPassword := os.Getenv("DB_PASSWORD")
-	dbHost := os.Getenv("DB_HOST")
-	dbPort := os.Getenv("DB_PORT")
-	dbName := os.Getenv("DB_NAME")
+// getenv devuelve el valor de la variable de entorno key o def si está vacía.
+func getenv(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
 
+// dialector construye el gorm.Dialector del driver indicado junto con el DSN usado.
+// Si DB_DSN está definida se usa tal cual; si no, el DSN se arma a partir de
+// DB_HOST, DB_PORT, DB_USER, DB_PASSWORD y DB_NAME (o DB_PATH para SQLite). En
+// SQLite se añade siempre el pragma que activa las claves foráneas.
+func dialector(driver string) (gorm.Dialector, string, error) {
 	// Valores por defecto si no se establecen las variables de entorno
 	// (Útil para desarrollo local si no se usa Docker Compose con env vars)
-	if dbUser == "" {
-		dbUser = "app_user"
-	}
-	if dbPassword == "" {
-		dbPassword = "app_password" // ¡Cambiar en producción!
-	}
-	if dbHost == "" {
-		dbHost = "database" // Nombre del servicio en Docker Compose o localhost
-	}
-	if dbPort == "" {
-		dbPort = "3306"
-	}
-	if dbName == "" {
-		dbName = "anime_db"
+	dbUser := getenv("DB_USER", "app_user")
+	dbPassword := getenv("DB_PASSWORD", "app_password") // ¡Cambiar en producción!
+	dbHost := getenv("DB_HOST", "database")             // Nombre del servicio en Docker Compose o localhost
+	dbName := getenv("DB_NAME", "anime_db")
+	dsn := os.Getenv("DB_DSN")
+
+	switch driver {
+	case DriverMySQL:
+		if dsn == "" {
+			// Añadidos parámetros recomendados: charset, parseTime, loc. Las
+			// fechas se guardan en UTC: las estadísticas agrupan por día sobre
+			// los valores de las columnas DATETIME sin convertir la zona horaria
+			dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
+				dbUser, dbPassword, dbHost, getenv("DB_PORT", "3306"), dbName)
+		}
+		return mysql.Open(dsn), dsn, nil
+
+	case DriverPostgres:
+		if dsn == "" {
+			dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s TimeZone=UTC",
+				dbHost, getenv("DB_PORT", "5432"), dbUser, dbPassword, dbName, getenv("DB_SSLMODE", "disable"))
+		}
+		return postgres.Open(dsn), dsn, nil
+
+	case DriverSQLite:
+		if dsn == "" {
+			// Activar claves foráneas y esperar en lugar de fallar si la base está bloqueada
+			dsn = fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)",
+				getenv("DB_PATH", "series_tracker.db"))
+		} else if !strings.Contains(dsn, "foreign_keys(1)") {
+			// Las claves foráneas se activan en cada conexión: también con un DSN propio
+			sep := "?"
+			if strings.Contains(dsn, "?") {
+				sep = "&"
+			}
+			dsn += sep + "_pragma=foreign_keys(1)"
+		}
+		return sqlite.Open(dsn), dsn, nil
 	}
 
-	// Construir la cadena de conexión (DSN)
-	// Añadidos parámetros recomendados: charset, parseTime, loc
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		dbUser, dbPassword, dbHost, dbPort, dbName)
+	return nil, "", fmt.Errorf("DB_DRIVER no soportado: %q (use %s, %s o %s)", driver, DriverMySQL, DriverPostgres, DriverSQLite)
+}
 
-	var err error
-	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{})
+// InitDB inicializa la conexión con la base de datos usando GORM y la devuelve.
+// El motor se elige con la variable de entorno DB_DRIVER (mysql por defecto,
+// postgres o sqlite) y la cadena de conexión (DSN) se construye según el motor.
+// El esquema no se crea aquí: lo gestionan las migraciones del paquete migrations.
+// Termina la aplicación si la conexión falla.
+func InitDB() *gorm.DB {
+	driver := getenv("DB_DRIVER", DriverMySQL)
+	dial, dsn, err := dialector(driver)
 	if err != nil {
-		log.Fatalf("Error fatal al conectar a la base de datos: %v\nDSN: %s", err, dsn)
+		log.Fatalf("Error fatal en la configuración de la base de datos: %v", err)
 	}
 
-	fmt.Println("Conexión a la base de datos exitosa.")
-
-	// AutoMigrate intentará crear o actualizar la tabla 'series' según el modelo models.Series.
-	fmt.Println("Ejecutando AutoMigrate para el modelo Series...")
-	err = DB.AutoMigrate(&models.Series{})
+	// TranslateError convierte los errores de unicidad de cada motor en gorm.ErrDuplicatedKey
+	db, err := gorm.Open(dial, &gorm.Config{TranslateError: true})
 	if err != nil {
-		log.Fatalf("Error fatal durante AutoMigrate: %v", err)
+		log.Fatalf("Error fatal al conectar a la base de datos (%s): %v\nDSN: %s", driver, err, dsn)
 	}
-	fmt.Println("AutoMigrate completado.")
+
+	if driver == DriverSQLite {
+		// SQLite admite un solo escritor a la vez; una única conexión evita errores "database is locked"
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatalf("Error obteniendo la instancia DB subyacente: %v", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+
+		// Los borrados en cascada dependen de las claves foráneas, que SQLite
+		// trae desactivadas; se activan también sobre la conexión ya abierta
+		if err := db.Exec("PRAGMA foreign_keys = ON").Error; err != nil {
+			log.Fatalf("Error activando las claves foráneas de SQLite: %v", err)
+		}
+	}
+
+	fmt.Printf("Conexión a la base de datos exitosa (%s).\n", driver)
+	return db
 }
 
 // CloseDB cierra la conexión a la base de datos si está abierta.
 // Es importante llamar a esta función para liberar recursos idealmente durante el cierre grácil de la aplicación.
-func CloseDB() {
-	if DB != nil {
-		sqlDB, err := DB.DB()
+func CloseDB(db *gorm.DB) {
+	if db != nil {
+		sqlDB, err := db.DB()
 		if err != nil {
 			log.Printf("Error obteniendo la instancia DB subyacente: %v", err)
 		}
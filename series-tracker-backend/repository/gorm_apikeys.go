@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ APIKeyRepository = (*GormAPIKeyRepository)(nil)
+
+// GormAPIKeyRepository implementa APIKeyRepository sobre una conexión GORM.
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAPIKeyRepository crea un repositorio de claves de API que usa la conexión db.
+func NewGormAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+// CreateAPIKey implementa APIKeyRepository.
+func (r *GormAPIKeyRepository) CreateAPIKey(ctx context.Context, k *models.APIKey) error {
+	return translateError(r.db.WithContext(ctx).Create(k).Error)
+}
+
+// ListAPIKeys implementa APIKeyRepository.
+func (r *GormAPIKeyRepository) ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error) {
+	keys := []models.APIKey{}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// GetAPIKeyByHash implementa APIKeyRepository.
+func (r *GormAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ? AND revoked_at IS NULL", keyHash).First(&key).Error; err != nil {
+		return models.APIKey{}, translateError(err)
+	}
+	return key, nil
+}
+
+// RevokeAPIKey implementa APIKeyRepository.
+func (r *GormAPIKeyRepository) RevokeAPIKey(ctx context.Context, userID, id int) error {
+	var key models.APIKey
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&key, id).Error; err != nil {
+		return translateError(err)
+	}
+	if key.RevokedAt != nil {
+		return nil // Revocar es idempotente
+	}
+	return r.db.WithContext(ctx).Model(&key).Update("revoked_at", time.Now().UTC()).Error
+}
+
+// TouchAPIKey implementa APIKeyRepository.
+func (r *GormAPIKeyRepository) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
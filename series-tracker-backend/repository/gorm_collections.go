@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ CollectionRepository = (*GormCollectionRepository)(nil)
+
+// Subconsultas que calculan la cantidad de series de cada etiqueta y lista, sin
+// contar las que están en la papelera.
+const (
+	tagSeriesCount  = "(SELECT COUNT(*) FROM series_tags JOIN series ON series.id = series_tags.series_id WHERE series_tags.tag_id = tags.id AND series.deleted_at IS NULL) AS series_count"
+	listSeriesCount = "(SELECT COUNT(*) FROM custom_list_items JOIN series ON series.id = custom_list_items.series_id WHERE custom_list_items.list_id = custom_lists.id AND series.deleted_at IS NULL) AS series_count"
+)
+
+// GormCollectionRepository implementa CollectionRepository sobre una conexión GORM.
+type GormCollectionRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCollectionRepository crea un repositorio de etiquetas y listas que usa la conexión db.
+func NewGormCollectionRepository(db *gorm.DB) *GormCollectionRepository {
+	return &GormCollectionRepository{db: db}
+}
+
+// checkOwned devuelve ErrNotFound si la tabla de model no tiene una fila con
+// ese id que pertenezca al usuario.
+func checkOwned(tx *gorm.DB, model any, userID, id int) error {
+	var count int64
+	if err := tx.Model(model).Where("id = ? AND user_id = ?", id, userID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// checkNameFree devuelve ErrConflict si el usuario ya tiene otra fila (distinta
+// de id) en la tabla de model con el mismo nombre, sin distinguir mayúsculas.
+func checkNameFree(tx *gorm.DB, model any, userID, id int, name string) error {
+	var count int64
+	err := tx.Model(model).Where("user_id = ? AND id <> ? AND LOWER(name) = LOWER(?)", userID, id, name).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+// ListTags implementa CollectionRepository.
+func (r *GormCollectionRepository) ListTags(ctx context.Context, userID int) ([]models.Tag, error) {
+	tags := []models.Tag{}
+	err := r.db.WithContext(ctx).Select("tags.*, "+tagSeriesCount).
+		Where("user_id = ?", userID).Order("LOWER(name), id").Find(&tags).Error
+	return tags, err
+}
+
+// CreateTag implementa CollectionRepository.
+func (r *GormCollectionRepository) CreateTag(ctx context.Context, t *models.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkNameFree(tx, &models.Tag{}, t.UserID, 0, t.Name); err != nil {
+			return err
+		}
+		t.CreatedAt = time.Now().UTC()
+		t.SeriesCount = 0
+		return translateError(tx.Create(t).Error)
+	})
+}
+
+// RenameTag implementa CollectionRepository.
+func (r *GormCollectionRepository) RenameTag(ctx context.Context, t *models.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.Tag{}, t.UserID, t.ID); err != nil {
+			return err
+		}
+		if err := checkNameFree(tx, &models.Tag{}, t.UserID, t.ID, t.Name); err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Tag{}).Where("id = ?", t.ID).Update("name", t.Name).Error; err != nil {
+			return translateError(err)
+		}
+		return tx.Select("tags.*, "+tagSeriesCount).First(t, t.ID).Error
+	})
+}
+
+// DeleteTag implementa CollectionRepository.
+func (r *GormCollectionRepository) DeleteTag(ctx context.Context, userID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.Tag{}, userID, id); err != nil {
+			return err
+		}
+		if err := tx.Where("tag_id = ?", id).Delete(&models.SeriesTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+// SeriesTags implementa CollectionRepository.
+func (r *GormCollectionRepository) SeriesTags(ctx context.Context, userID, seriesID int) ([]models.Tag, error) {
+	db := r.db.WithContext(ctx)
+	if err := checkOwned(db, &models.Series{}, userID, seriesID); err != nil {
+		return nil, err
+	}
+	tags := []models.Tag{}
+	err := db.Select("tags.*, "+tagSeriesCount).
+		Where("user_id = ? AND id IN (SELECT tag_id FROM series_tags WHERE series_id = ?)", userID, seriesID).
+		Order("LOWER(name), id").Find(&tags).Error
+	return tags, err
+}
+
+// AttachTag implementa CollectionRepository.
+func (r *GormCollectionRepository) AttachTag(ctx context.Context, userID, seriesID, tagID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.Series{}, userID, seriesID); err != nil {
+			return err
+		}
+		if err := checkOwned(tx, &models.Tag{}, userID, tagID); err != nil {
+			return err
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.SeriesTag{SeriesID: seriesID, TagID: tagID}).Error
+	})
+}
+
+// DetachTag implementa CollectionRepository.
+func (r *GormCollectionRepository) DetachTag(ctx context.Context, userID, seriesID, tagID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.Series{}, userID, seriesID); err != nil {
+			return err
+		}
+		if err := checkOwned(tx, &models.Tag{}, userID, tagID); err != nil {
+			return err
+		}
+		return tx.Where("series_id = ? AND tag_id = ?", seriesID, tagID).Delete(&models.SeriesTag{}).Error
+	})
+}
+
+// ListLists implementa CollectionRepository.
+func (r *GormCollectionRepository) ListLists(ctx context.Context, userID int) ([]models.CustomList, error) {
+	lists := []models.CustomList{}
+	err := r.db.WithContext(ctx).Select("custom_lists.*, "+listSeriesCount).
+		Where("user_id = ?", userID).Order("LOWER(name), id").Find(&lists).Error
+	return lists, err
+}
+
+// GetList implementa CollectionRepository.
+func (r *GormCollectionRepository) GetList(ctx context.Context, userID, id int) (models.CustomList, error) {
+	var list models.CustomList
+	err := r.db.WithContext(ctx).Select("custom_lists.*, "+listSeriesCount).
+		Where("user_id = ?", userID).First(&list, id).Error
+	return list, translateError(err)
+}
+
+// CreateList implementa CollectionRepository.
+func (r *GormCollectionRepository) CreateList(ctx context.Context, l *models.CustomList) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkNameFree(tx, &models.CustomList{}, l.UserID, 0, l.Name); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		l.CreatedAt, l.UpdatedAt = now, now
+		l.SeriesCount = 0
+		return translateError(tx.Create(l).Error)
+	})
+}
+
+// UpdateList implementa CollectionRepository.
+func (r *GormCollectionRepository) UpdateList(ctx context.Context, l *models.CustomList) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.CustomList{}, l.UserID, l.ID); err != nil {
+			return err
+		}
+		if err := checkNameFree(tx, &models.CustomList{}, l.UserID, l.ID, l.Name); err != nil {
+			return err
+		}
+		err := tx.Model(&models.CustomList{}).Where("id = ?", l.ID).Updates(map[string]any{
+			"name":        l.Name,
+			"description": l.Description,
+			"updated_at":  time.Now().UTC(),
+		}).Error
+		if err != nil {
+			return translateError(err)
+		}
+		return tx.Select("custom_lists.*, "+listSeriesCount).First(l, l.ID).Error
+	})
+}
+
+// DeleteList implementa CollectionRepository.
+func (r *GormCollectionRepository) DeleteList(ctx context.Context, userID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.CustomList{}, userID, id); err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ?", id).Delete(&models.CustomListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.CustomList{}, id).Error
+	})
+}
+
+// AddToList implementa CollectionRepository.
+func (r *GormCollectionRepository) AddToList(ctx context.Context, userID, listID, seriesID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.CustomList{}, userID, listID); err != nil {
+			return err
+		}
+		if err := checkOwned(tx, &models.Series{}, userID, seriesID); err != nil {
+			return err
+		}
+		item := models.CustomListItem{ListID: listID, SeriesID: seriesID, AddedAt: time.Now().UTC()}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&item).Error
+	})
+}
+
+// RemoveFromList implementa CollectionRepository.
+func (r *GormCollectionRepository) RemoveFromList(ctx context.Context, userID, listID, seriesID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkOwned(tx, &models.CustomList{}, userID, listID); err != nil {
+			return err
+		}
+		if err := checkOwned(tx, &models.Series{}, userID, seriesID); err != nil {
+			return err
+		}
+		return tx.Where("list_id = ? AND series_id = ?", listID, seriesID).Delete(&models.CustomListItem{}).Error
+	})
+}
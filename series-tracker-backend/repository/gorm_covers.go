@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// SetCover implementa SeriesRepository.
+func (r *GormSeriesRepository) SetCover(ctx context.Context, userID, id int, token, coverURL string) (models.Series, string, error) {
+	var serie models.Series
+	var previous string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if serie, err = lockSeries(tx, userID, id); err != nil {
+			return err
+		}
+		if err := checkVersion(ctx, serie.Version); err != nil {
+			return err
+		}
+		previous = serie.CoverKey
+		serie.CoverKey, serie.CoverURL = token, coverURL
+		serie.Version++
+		return tx.Model(&serie).Select("cover_key", "cover_url", "version").Updates(&serie).Error
+	})
+	if err != nil {
+		return models.Series{}, "", err
+	}
+	return serie, previous, nil
+}
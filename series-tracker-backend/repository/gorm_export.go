@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// exportBatchSize es la cantidad de series que se leen por consulta al exportar.
+const exportBatchSize = 100
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ ExportRepository = (*GormExportRepository)(nil)
+
+// GormExportRepository implementa ExportRepository sobre una conexión GORM.
+type GormExportRepository struct {
+	db *gorm.DB
+}
+
+// NewGormExportRepository crea un repositorio de exportación que usa la conexión db.
+func NewGormExportRepository(db *gorm.DB) *GormExportRepository {
+	return &GormExportRepository{db: db}
+}
+
+// ExportSeries implementa ExportRepository. Las series se leen por lotes
+// paginando por id (sin OFFSET) y los datos relacionados de cada lote se cargan
+// con una consulta por tabla.
+func (r *GormExportRepository) ExportSeries(ctx context.Context, userID int, fn func(models.SeriesExport) error) error {
+	db := r.db.WithContext(ctx)
+	lastID := 0
+	for {
+		var batch []models.Series
+		err := owned(db, userID).Where("series.id > ?", lastID).
+			Order("series.id").Limit(exportBatchSize).Find(&batch).Error
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+
+		exports, err := r.related(db, userID, batch)
+		if err != nil {
+			return err
+		}
+		for _, e := range exports {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+}
+
+// related completa las series del lote con sus etiquetas, listas, reseña e historial.
+func (r *GormExportRepository) related(db *gorm.DB, userID int, batch []models.Series) ([]models.SeriesExport, error) {
+	ids := make([]int, len(batch))
+	exports := make([]models.SeriesExport, len(batch))
+	index := make(map[int]*models.SeriesExport, len(batch))
+	for i, s := range batch {
+		ids[i] = s.ID
+		exports[i] = models.SeriesExport{Series: s, Tags: []string{}, Lists: []string{}, History: []models.WatchEvent{}}
+		index[s.ID] = &exports[i]
+	}
+
+	type nameRow struct {
+		SeriesID int
+		Name     string
+	}
+	var tags []nameRow
+	err := db.Table("series_tags").Select("series_tags.series_id, tags.name").
+		Joins("JOIN tags ON tags.id = series_tags.tag_id").
+		Where("series_tags.series_id IN ?", ids).
+		Order("LOWER(tags.name), tags.id").Scan(&tags).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		index[t.SeriesID].Tags = append(index[t.SeriesID].Tags, t.Name)
+	}
+
+	var lists []nameRow
+	err = db.Table("custom_list_items").Select("custom_list_items.series_id, custom_lists.name").
+		Joins("JOIN custom_lists ON custom_lists.id = custom_list_items.list_id").
+		Where("custom_list_items.series_id IN ?", ids).
+		Order("LOWER(custom_lists.name), custom_lists.id").Scan(&lists).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lists {
+		index[l.SeriesID].Lists = append(index[l.SeriesID].Lists, l.Name)
+	}
+
+	var reviews []models.Review
+	if err := db.Where("user_id = ? AND series_id IN ?", userID, ids).Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	for i := range reviews {
+		index[reviews[i].SeriesID].Review = &reviews[i]
+	}
+
+	var events []models.WatchEvent
+	if err := db.Where("series_id IN ?", ids).Order("series_id, id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	for _, e := range events {
+		index[e.SeriesID].History = append(index[e.SeriesID].History, e)
+	}
+	return exports, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// saveProgress guarda el estado, el progreso y la versión de s.
+func saveProgress(tx *gorm.DB, s *models.Series) error {
+	return tx.Model(s).Select("status", "last_episode_watched", "current_season", "current_episode", "version").Updates(s).Error
+}
+
+// recordEvent guarda en el historial el cambio de before a after, si lo hubo.
+func recordEvent(tx *gorm.DB, before, after models.Series) error {
+	event := models.NewWatchEvent(before, after, time.Now())
+	if event == nil {
+		return nil
+	}
+	return tx.Create(event).Error
+}
+
+// History implementa SeriesRepository.
+func (r *GormSeriesRepository) History(ctx context.Context, userID, id int, q models.HistoryQuery) ([]models.WatchEvent, int64, error) {
+	db := r.db.WithContext(ctx)
+	if _, err := r.Get(ctx, userID, id); err != nil {
+		return nil, 0, err
+	}
+
+	query := db.Model(&models.WatchEvent{}).Where("series_id = ? AND user_id = ?", id, userID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	events := []models.WatchEvent{}
+	err := query.Order("id DESC").Limit(q.PageSize).Offset(q.Offset()).Find(&events).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
+
+// UndoLastEvent implementa SeriesRepository.
+func (r *GormSeriesRepository) UndoLastEvent(ctx context.Context, userID, id int) (models.Series, error) {
+	var serie models.Series
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if serie, err = lockSeries(tx, userID, id); err != nil {
+			return err
+		}
+		if err := checkVersion(ctx, serie.Version); err != nil {
+			return err
+		}
+
+		var event models.WatchEvent
+		err = tx.Where("series_id = ? AND user_id = ?", id, userID).Order("id DESC").First(&event).Error
+		if err != nil {
+			if translateError(err) == ErrNotFound {
+				return ErrNoHistory
+			}
+			return err
+		}
+
+		serie.Undo(event)
+		serie.Version++
+		if err := saveProgress(tx, &serie); err != nil {
+			return err
+		}
+		return tx.Delete(&event).Error
+	})
+	if err != nil {
+		return models.Series{}, err
+	}
+	return serie, nil
+}
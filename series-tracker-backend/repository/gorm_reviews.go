@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// GetReview implementa SeriesRepository.
+func (r *GormSeriesRepository) GetReview(ctx context.Context, userID, id int) (models.Review, error) {
+	var review models.Review
+	err := r.db.WithContext(ctx).Where("series_id = ? AND user_id = ?", id, userID).First(&review).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Distinguir una serie inexistente de una serie sin reseña no cambia la
+		// respuesta (404), pero sí el mensaje.
+		if _, err := r.Get(ctx, userID, id); err != nil {
+			return models.Review{}, err
+		}
+		return models.Review{}, ErrNoReview
+	}
+	return review, err
+}
+
+// SaveReview implementa SeriesRepository.
+func (r *GormSeriesRepository) SaveReview(ctx context.Context, review *models.Review) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockSeries(tx, review.UserID, review.SeriesID); err != nil {
+			return err
+		}
+
+		var existing models.Review
+		err := tx.Where("series_id = ? AND user_id = ?", review.SeriesID, review.UserID).First(&existing).Error
+		now := time.Now().UTC()
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			created = true
+			review.CreatedAt, review.UpdatedAt = now, now
+			return tx.Create(review).Error
+		case err != nil:
+			return err
+		}
+		review.CreatedAt, review.UpdatedAt = existing.CreatedAt, now
+		return tx.Model(&models.Review{}).
+			Where("series_id = ? AND user_id = ?", review.SeriesID, review.UserID).
+			Updates(map[string]any{"score": review.Score, "body": review.Body, "updated_at": now}).Error
+	})
+	return created, err
+}
+
+// DeleteReview implementa SeriesRepository.
+func (r *GormSeriesRepository) DeleteReview(ctx context.Context, userID, id int) error {
+	if _, err := r.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	result := r.db.WithContext(ctx).Where("series_id = ? AND user_id = ?", id, userID).Delete(&models.Review{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNoReview
+	}
+	return nil
+}
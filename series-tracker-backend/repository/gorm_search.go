@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// searchSQL agrupa las consultas de búsqueda que cambian según el motor (ver
+// la migración 0014_add_series_search). Devuelven los ids de las series de
+// @user fuera de la papelera, de la más a la menos relevante, hasta @limit.
+type searchSQL struct {
+	// fullText busca @match en el índice de texto completo; match construye
+	// esa expresión a partir de los términos de la búsqueda.
+	fullText string
+	match    func(terms []string) string
+	// fuzzy busca @fuzzy en el índice de trigramas, que tolera errores de
+	// escritura; fuzzyMatch construye esa expresión (vacía si no hay nada que
+	// buscar). Está vacío si el motor no tiene índices de trigramas.
+	fuzzy      string
+	fuzzyMatch func(terms []string) string
+}
+
+// searchDialects contiene las consultas de cada driver (ver gorm.Dialector.Name).
+// Los términos solo tienen letras y dígitos (ver searchTerms), por lo que no
+// pueden alterar la sintaxis de las expresiones de búsqueda.
+var searchDialects = map[string]searchSQL{
+	// MariaDB no tiene índices de trigramas (el parser ngram es exclusivo de
+	// MySQL), por lo que allí los errores de escritura se detectan comparando
+	// los títulos preseleccionados con LIKE (ver likeCandidates).
+	"mysql": {
+		fullText: `SELECT id FROM series
+			WHERE MATCH (title, alt_titles) AGAINST (@match IN BOOLEAN MODE) AND user_id = @user AND deleted_at IS NULL
+			ORDER BY MATCH (title, alt_titles) AGAINST (@match IN BOOLEAN MODE) DESC, id LIMIT @limit`,
+		match: func(terms []string) string { return joinTerms(terms, "", "*", " ") },
+	},
+	"postgres": {
+		fullText: `SELECT id FROM series
+			WHERE search_vector @@ to_tsquery('simple', @match) AND user_id = @user AND deleted_at IS NULL
+			ORDER BY ts_rank(search_vector, to_tsquery('simple', @match)) DESC, id LIMIT @limit`,
+		match: func(terms []string) string { return joinTerms(terms, "", ":*", " | ") },
+		fuzzy: `SELECT id FROM series
+			WHERE @fuzzy <% search_text AND user_id = @user AND deleted_at IS NULL
+			ORDER BY word_similarity(@fuzzy, search_text) DESC, id LIMIT @limit`,
+		fuzzyMatch: func(terms []string) string { return strings.Join(terms, " ") },
+	},
+	"sqlite": {
+		fullText: `SELECT s.id FROM series_fts JOIN series s ON s.id = series_fts.rowid
+			WHERE series_fts MATCH @match AND s.user_id = @user AND s.deleted_at IS NULL
+			ORDER BY series_fts.rank, s.id LIMIT @limit`,
+		match: func(terms []string) string { return joinTerms(terms, `"`, `"*`, " OR ") },
+		fuzzy: `SELECT s.id FROM series_trigrams JOIN series s ON s.id = series_trigrams.rowid
+			WHERE series_trigrams MATCH @fuzzy AND s.user_id = @user AND s.deleted_at IS NULL
+			ORDER BY series_trigrams.rank, s.id LIMIT @limit`,
+		fuzzyMatch: func(terms []string) string { return joinTerms(termTrigrams(terms), `"`, `"`, " OR ") },
+	},
+}
+
+// joinTerms rodea cada término con prefix y suffix y los une con sep.
+func joinTerms(terms []string, prefix, suffix, sep string) string {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = prefix + term + suffix
+	}
+	return strings.Join(quoted, sep)
+}
+
+// Search implementa SeriesRepository. Los índices de texto completo y de
+// trigramas (o, sin estos, la preselección con LIKE) aportan hasta
+// searchCandidateLimit candidatas cada uno, que se ordenan por relevancia con
+// rankSearch (la misma en todos los motores).
+func (r *GormSeriesRepository) Search(ctx context.Context, userID int, query string, limit int) ([]models.SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+	dialect, ok := searchDialects[r.db.Dialector.Name()]
+	if !ok {
+		return nil, fmt.Errorf("búsqueda no soportada para el driver %q", r.db.Dialector.Name())
+	}
+	db := r.db.WithContext(ctx)
+
+	var ids []int
+	args := map[string]any{"match": dialect.match(terms), "user": userID, "limit": searchCandidateLimit}
+	if err := db.Raw(dialect.fullText, args).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	var fuzzyIDs []int
+	switch {
+	case dialect.fuzzy != "":
+		if args["fuzzy"] = dialect.fuzzyMatch(terms); args["fuzzy"] != "" {
+			if err := db.Raw(dialect.fuzzy, args).Scan(&fuzzyIDs).Error; err != nil {
+				return nil, err
+			}
+		}
+	case len(ids) < limit:
+		// Sin índice de trigramas, si el de texto completo no alcanza para
+		// completar los resultados se buscan también las escritas con errores
+		var err error
+		if fuzzyIDs, err = likeCandidates(db, userID, terms); err != nil {
+			return nil, err
+		}
+	}
+	ids = append(ids, fuzzyIDs...)
+	if len(ids) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	// Para puntuar solo hacen falta los títulos
+	var rows []models.Series
+	err := owned(db.Model(&models.Series{}), userID).Select("id", "title", "alt_titles").
+		Where("id IN ?", ids).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	results := rankSearch(terms, rows, limit)
+	if len(results) == 0 {
+		return results, nil
+	}
+
+	resultIDs := make([]int, len(results))
+	for i, result := range results {
+		resultIDs[i] = result.ID
+	}
+	var series []models.Series
+	if err := owned(db, userID).Where("id IN ?", resultIDs).Find(&series).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int]models.Series, len(series))
+	for _, s := range series {
+		byID[s.ID] = s
+	}
+	found := results[:0]
+	for _, result := range results {
+		// Una serie eliminada entre ambas consultas ya no se incluye
+		if s, ok := byID[result.ID]; ok {
+			result.Series = s
+			found = append(found, result)
+		}
+	}
+	return found, nil
+}
+
+// likeCandidates devuelve hasta searchCandidateLimit series de userID cuyo
+// título o títulos alternativos contienen el principio o el final de alguno de
+// los términos (ver termAffixes), para los motores sin índice de trigramas.
+func likeCandidates(db *gorm.DB, userID int, terms []string) ([]int, error) {
+	affixes := termAffixes(terms)
+	if len(affixes) == 0 {
+		return nil, nil
+	}
+	conditions := make([]string, len(affixes))
+	args := make([]any, 0, 2*len(affixes))
+	for i, affix := range affixes {
+		// Los términos solo tienen letras y dígitos: no hay comodines que escapar
+		conditions[i] = "series.title LIKE ? OR series.alt_titles LIKE ?"
+		args = append(args, "%"+affix+"%", "%"+affix+"%")
+	}
+	var ids []int
+	err := owned(db.Model(&models.Series{}), userID).Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Order("series.id").Limit(searchCandidateLimit).Pluck("series.id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"lab6/models"
+)
+
+// likeEscaper escapa los comodines de LIKE para que la búsqueda sea literal.
+// Se usa '!' como carácter de escape porque '\' no es portable entre motores.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ SeriesRepository = (*GormSeriesRepository)(nil)
+
+// GormSeriesRepository implementa SeriesRepository sobre una conexión GORM.
+type GormSeriesRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSeriesRepository crea un repositorio de series que usa la conexión db.
+func NewGormSeriesRepository(db *gorm.DB) *GormSeriesRepository {
+	return &GormSeriesRepository{db: db}
+}
+
+// translateError convierte los errores propios de GORM en errores del paquete.
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrConflict
+	}
+	return err
+}
+
+// owned limita una consulta a las series del usuario indicado.
+func owned(db *gorm.DB, userID int) *gorm.DB {
+	return db.Where("series.user_id = ?", userID)
+}
+
+// lockSeries lee la serie dentro de la transacción tx bloqueando la fila
+// (SELECT ... FOR UPDATE) hasta que la transacción termine, de modo que otras
+// modificaciones concurrentes de la misma serie esperan en lugar de pisarse.
+// SQLite no admite bloqueos por fila, pero serializa las escrituras.
+func lockSeries(tx *gorm.DB, userID, id int) (models.Series, error) {
+	var serie models.Series
+	err := owned(tx, userID).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&serie, id).Error
+	return serie, translateError(err)
+}
+
+// List implementa SeriesRepository.
+func (r *GormSeriesRepository) List(ctx context.Context, userID int, q models.SeriesQuery) ([]models.Series, int64, error) {
+	query := owned(r.db.WithContext(ctx).Model(&models.Series{}), userID)
+
+	if q.Search != "" {
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(q.Search)) + "%"
+		query = query.Where("LOWER(title) LIKE ? ESCAPE '!'", pattern)
+	}
+	if q.Status != "" {
+		query = query.Where("status = ?", q.Status)
+	}
+	if q.MinRanking != nil {
+		query = query.Where("ranking >= ?", *q.MinRanking)
+	}
+	if q.MaxRanking != nil {
+		query = query.Where("ranking <= ?", *q.MaxRanking)
+	}
+	for _, tagID := range q.TagIDs {
+		query = query.Where("series.id IN (SELECT series_id FROM series_tags WHERE tag_id = ?)", tagID)
+	}
+	if q.ListID != nil {
+		query = query.Where("series.id IN (SELECT series_id FROM custom_list_items WHERE list_id = ?)", *q.ListID)
+	}
+
+	// Contar antes de aplicar orden y paginación
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	column, ok := models.SeriesSortFields[q.SortBy]
+	if !ok {
+		column = "id"
+	}
+	direction := " ASC"
+	if q.SortDesc {
+		direction = " DESC"
+	}
+	if q.SortBy == "score" {
+		// La puntuación está en la reseña del dueño; las series sin puntuar van al final
+		query = query.Select("series.*").
+			Joins("LEFT JOIN reviews ON reviews.series_id = series.id AND reviews.user_id = series.user_id").
+			Order("CASE WHEN reviews.score IS NULL THEN 1 ELSE 0 END")
+	}
+	// El id como desempate garantiza un orden estable entre páginas
+	query = query.Order(column + direction)
+	if column != "id" {
+		query = query.Order("series.id ASC")
+	}
+
+	if q.PageSize > 0 {
+		query = query.Limit(q.PageSize).Offset(q.Offset())
+	}
+
+	series := []models.Series{}
+	if err := query.Find(&series).Error; err != nil {
+		return nil, 0, err
+	}
+	return series, total, nil
+}
+
+// Get implementa SeriesRepository.
+func (r *GormSeriesRepository) Get(ctx context.Context, userID, id int) (models.Series, error) {
+	var serie models.Series
+	if err := owned(r.db.WithContext(ctx), userID).First(&serie, id).Error; err != nil {
+		return models.Series{}, translateError(err)
+	}
+	return serie, nil
+}
+
+// Create implementa SeriesRepository.
+func (r *GormSeriesRepository) Create(ctx context.Context, s *models.Series) error {
+	// GORM asignará el ID automáticamente si la creación es exitosa.
+	s.Version = 1
+	s.Ranking, s.Upvotes, s.Downvotes, s.LegacyRanking = 0, 0, 0, 0
+	s.CoverKey = ""
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// Update implementa SeriesRepository.
+func (r *GormSeriesRepository) Update(ctx context.Context, s *models.Series) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Verificar primero si la serie existe; Save insertaría una fila nueva si no.
+		existing, err := lockSeries(tx, s.UserID, s.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(ctx, existing.Version); err != nil {
+			return err
+		}
+		if err := models.ValidateTransition(existing.Status, s.Status); err != nil {
+			return err
+		}
+		s.ApplyProgressRules()
+		s.Version = existing.Version + 1
+		s.Ranking, s.Upvotes, s.Downvotes, s.LegacyRanking = existing.Ranking, existing.Upvotes, existing.Downvotes, existing.LegacyRanking
+		s.CoverKey = existing.CoverKey
+		if err := tx.Save(s).Error; err != nil {
+			return err
+		}
+		return recordEvent(tx, existing, *s)
+	})
+}
+
+// Delete implementa SeriesRepository.
+func (r *GormSeriesRepository) Delete(ctx context.Context, userID, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := lockSeries(tx, userID, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(ctx, existing.Version); err != nil {
+			return err
+		}
+		return tx.Delete(&existing).Error
+	})
+}
+
+// UpdateStatus implementa SeriesRepository.
+func (r *GormSeriesRepository) UpdateStatus(ctx context.Context, userID, id int, status models.SeriesStatus) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.TransitionTo(status)
+	}))
+}
+
+// Rewatch implementa SeriesRepository.
+func (r *GormSeriesRepository) Rewatch(ctx context.Context, userID, id int) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.Rewatch()
+	}))
+}
+
+// IncrementEpisode implementa SeriesRepository.
+func (r *GormSeriesRepository) IncrementEpisode(ctx context.Context, userID, id int) (models.Series, error) {
+	return r.modify(ctx, userID, id, func(s *models.Series) (bool, error) {
+		return s.WatchNextEpisode()
+	})
+}
+
+// DecrementEpisode implementa SeriesRepository.
+func (r *GormSeriesRepository) DecrementEpisode(ctx context.Context, userID, id int) (models.Series, error) {
+	return r.modify(ctx, userID, id, func(s *models.Series) (bool, error) {
+		return s.UnwatchLastEpisode()
+	})
+}
+
+// SetEpisode implementa SeriesRepository.
+func (r *GormSeriesRepository) SetEpisode(ctx context.Context, userID, id int, ref models.EpisodeRef) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.SetProgress(ref)
+	}))
+}
+
+// WatchRange implementa SeriesRepository.
+func (r *GormSeriesRepository) WatchRange(ctx context.Context, userID, id int, rng models.EpisodeRange) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.WatchRange(rng)
+	}))
+}
+
+// modifyFunc aplica una operación sobre la serie e indica si la cambió.
+type modifyFunc func(s *models.Series) (changed bool, err error)
+
+// trackProgress adapta una operación de progreso o estado a modifyFunc: la
+// serie cambió si cambiaron el último episodio visto o el estado.
+func trackProgress(fn func(s *models.Series) error) modifyFunc {
+	return func(s *models.Series) (bool, error) {
+		before := *s
+		if err := fn(s); err != nil {
+			return false, err
+		}
+		return s.ProgressChanged(before), nil
+	}
+}
+
+// modify lee y bloquea la serie dentro de una transacción, aplica fn y guarda el
+// estado y el progreso resultantes. Como la fila queda bloqueada hasta el commit,
+// las reglas de fn (tope de episodios, transiciones) se evalúan siempre sobre el
+// último estado persistido. Si fn devuelve un error no se modifica nada, y si no
+// cambió la serie se devuelve tal cual, sin incrementar la versión ni registrar
+// un evento en el historial.
+func (r *GormSeriesRepository) modify(ctx context.Context, userID, id int, fn modifyFunc) (models.Series, error) {
+	var serie models.Series
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if serie, err = lockSeries(tx, userID, id); err != nil {
+			return err
+		}
+		if err := checkVersion(ctx, serie.Version); err != nil {
+			return err
+		}
+		before := serie
+		changed, err := fn(&serie)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			serie = before
+			return nil
+		}
+		serie.Version++
+		if err := saveProgress(tx, &serie); err != nil {
+			return err
+		}
+		return recordEvent(tx, before, serie)
+	})
+	if err != nil {
+		return models.Series{}, err
+	}
+	return serie, nil
+}
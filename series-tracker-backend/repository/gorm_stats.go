@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ StatsRepository = (*GormStatsRepository)(nil)
+
+// statsSQL agrupa las expresiones SQL de fechas que cambian según el motor.
+// Todas trabajan en UTC sobre la columna watched_at; en MySQL las DATETIME no
+// guardan la zona horaria, por lo que el DSN debe usar loc=UTC.
+type statsSQL struct {
+	// day, week y month devuelven el período del evento como texto
+	// ('YYYY-MM-DD' para días y semanas, 'YYYY-MM' para meses).
+	day, week, month string
+	// dayNumber devuelve un entero que aumenta en 1 de un día al siguiente.
+	dayNumber string
+	// seconds devuelve los segundos entre las columnas started_at y finished_at.
+	seconds string
+}
+
+// statsDialects contiene las expresiones de cada driver (ver gorm.Dialector.Name).
+var statsDialects = map[string]statsSQL{
+	"mysql": {
+		day:       "DATE_FORMAT(watched_at, '%Y-%m-%d')",
+		week:      "DATE_FORMAT(DATE_SUB(DATE(watched_at), INTERVAL WEEKDAY(watched_at) DAY), '%Y-%m-%d')",
+		month:     "DATE_FORMAT(watched_at, '%Y-%m')",
+		dayNumber: "TO_DAYS(watched_at)",
+		seconds:   "TIMESTAMPDIFF(SECOND, started_at, finished_at)",
+	},
+	"postgres": {
+		day:       "to_char(watched_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')",
+		week:      "to_char(date_trunc('week', watched_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD')",
+		month:     "to_char(watched_at AT TIME ZONE 'UTC', 'YYYY-MM')",
+		dayNumber: "(CAST(watched_at AT TIME ZONE 'UTC' AS DATE) - DATE '1970-01-01')",
+		seconds:   "EXTRACT(EPOCH FROM (finished_at - started_at))",
+	},
+	"sqlite": {
+		day:       "strftime('%Y-%m-%d', watched_at)",
+		week:      "date(watched_at, 'weekday 0', '-6 days')",
+		month:     "strftime('%Y-%m', watched_at)",
+		dayNumber: "CAST(julianday(date(watched_at)) AS INTEGER)",
+		seconds:   "(julianday(finished_at) - julianday(started_at)) * 86400",
+	},
+}
+
+// watchedEpisodes suma los episodios avanzados en cada evento (los retrocesos no cuentan).
+const watchedEpisodes = "SUM(CASE WHEN episode > previous_episode THEN episode - previous_episode ELSE 0 END)"
+
+// activeEvents excluye de las consultas sobre watch_events los eventos de las
+// series que están en la papelera.
+const activeEvents = "series_id NOT IN (SELECT id FROM series WHERE deleted_at IS NOT NULL)"
+
+// GormStatsRepository implementa StatsRepository con consultas SQL agregadas
+// sobre las tablas series y watch_events.
+type GormStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewGormStatsRepository crea un repositorio de estadísticas que usa la conexión db.
+func NewGormStatsRepository(db *gorm.DB) *GormStatsRepository {
+	return &GormStatsRepository{db: db}
+}
+
+// Stats implementa StatsRepository.
+func (r *GormStatsRepository) Stats(ctx context.Context, userID int, q models.StatsQuery) (models.Stats, error) {
+	dialect, ok := statsDialects[r.db.Dialector.Name()]
+	if !ok {
+		return models.Stats{}, fmt.Errorf("estadísticas no soportadas para el driver %q", r.db.Dialector.Name())
+	}
+	db := r.db.WithContext(ctx)
+
+	var stats models.Stats
+	var err error
+	if stats.Totals, stats.ByStatus, err = r.seriesStats(db, userID); err != nil {
+		return models.Stats{}, err
+	}
+
+	err = db.Raw("SELECT COUNT(*) AS events, COALESCE("+watchedEpisodes+", 0) AS history_episodes FROM watch_events WHERE user_id = ? AND "+activeEvents, userID).
+		Row().Scan(&stats.Totals.Events, &stats.Totals.HistoryEpisodes)
+	if err != nil {
+		return models.Stats{}, err
+	}
+
+	nextDay := func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	nextWeek := func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	nextMonth := func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	periods := []struct {
+		expr   string
+		since  time.Time
+		step   func(time.Time) time.Time
+		format string
+		dst    *[]models.PeriodCount
+	}{
+		{dialect.day, q.DaysSince(), nextDay, models.DayFormat, &stats.EpisodesPerDay},
+		{dialect.week, q.WeeksSince(), nextWeek, models.DayFormat, &stats.EpisodesPerWeek},
+		{dialect.month, q.MonthsSince(), nextMonth, models.MonthFormat, &stats.EpisodesPerMonth},
+	}
+	for _, p := range periods {
+		counts, err := r.episodesPerPeriod(db, userID, p.expr, p.since)
+		if err != nil {
+			return models.Stats{}, err
+		}
+		*p.dst = models.FillPeriods(counts, p.since, q.Now, p.step, p.format)
+	}
+
+	if stats.AverageDaysToFinish, err = r.averageDaysToFinish(db, userID, dialect); err != nil {
+		return models.Stats{}, err
+	}
+
+	streaks, err := r.streaks(db, userID, dialect)
+	if err != nil {
+		return models.Stats{}, err
+	}
+	stats.Streaks = models.PickStreaks(streaks, q.Now)
+
+	return stats, nil
+}
+
+// seriesStats calcula los totales y la agrupación por estado de las series.
+func (r *GormStatsRepository) seriesStats(db *gorm.DB, userID int) (models.StatsTotals, []models.StatusStats, error) {
+	rows, err := db.Raw(`SELECT status, COUNT(*), COALESCE(SUM(last_episode_watched), 0), COALESCE(SUM(total_episodes), 0),
+		AVG(CASE WHEN total_episodes > 0 THEN 1.0 * last_episode_watched / total_episodes END)
+		FROM series WHERE user_id = ? AND deleted_at IS NULL GROUP BY status ORDER BY status`, userID).Rows()
+	if err != nil {
+		return models.StatsTotals{}, nil, err
+	}
+	defer rows.Close()
+
+	var totals models.StatsTotals
+	byStatus := []models.StatusStats{}
+	for rows.Next() {
+		var s models.StatusStats
+		var watched, episodes int64
+		if err := rows.Scan(&s.Status, &s.Series, &watched, &episodes, &s.CompletionRate); err != nil {
+			return models.StatsTotals{}, nil, err
+		}
+		totals.Series += s.Series
+		totals.EpisodesWatched += watched
+		totals.TotalEpisodes += episodes
+		byStatus = append(byStatus, s)
+	}
+	if err := rows.Err(); err != nil {
+		return models.StatsTotals{}, nil, err
+	}
+
+	for i := range byStatus {
+		byStatus[i].Share = float64(byStatus[i].Series) / float64(totals.Series)
+	}
+	return totals, byStatus, nil
+}
+
+// episodesPerPeriod suma los episodios vistos desde since agrupados por la
+// expresión de período period.
+func (r *GormStatsRepository) episodesPerPeriod(db *gorm.DB, userID int, period string, since time.Time) (map[string]int64, error) {
+	rows, err := db.Raw("SELECT "+period+" AS period, "+watchedEpisodes+" AS episodes FROM watch_events"+
+		" WHERE user_id = ? AND watched_at >= ? AND "+activeEvents+" GROUP BY "+period, userID, since).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var period string
+		var episodes int64
+		if err := rows.Scan(&period, &episodes); err != nil {
+			return nil, err
+		}
+		counts[period] = episodes
+	}
+	return counts, rows.Err()
+}
+
+// averageDaysToFinish calcula el tiempo medio entre el primer evento de cada
+// serie y el primer evento que la pasa a 'Completed'.
+func (r *GormStatsRepository) averageDaysToFinish(db *gorm.DB, userID int, dialect statsSQL) (*float64, error) {
+	var seconds *float64
+	err := db.Raw(`SELECT AVG(`+dialect.seconds+`) FROM (
+		SELECT MIN(watched_at) AS started_at,
+			MIN(CASE WHEN status = ? AND previous_status <> ? THEN watched_at END) AS finished_at
+		FROM watch_events WHERE user_id = ? AND `+activeEvents+` GROUP BY series_id
+	) finished WHERE finished_at IS NOT NULL`, models.StatusCompleted, models.StatusCompleted, userID).Row().Scan(&seconds)
+	if err != nil || seconds == nil {
+		return nil, err
+	}
+	days := *seconds / 86400
+	return &days, nil
+}
+
+// streaks calcula las rachas de días consecutivos con episodios vistos,
+// agrupando los días cuyo número menos su posición es constante.
+func (r *GormStatsRepository) streaks(db *gorm.DB, userID int, dialect statsSQL) ([]models.Streak, error) {
+	rows, err := db.Raw(`SELECT COUNT(*), MIN(day), MAX(day) FROM (
+		SELECT day, day_number - ROW_NUMBER() OVER (ORDER BY day_number) AS grp FROM (
+			SELECT `+dialect.day+` AS day, `+dialect.dayNumber+` AS day_number FROM watch_events
+			WHERE user_id = ? AND episode > previous_episode AND `+activeEvents+`
+			GROUP BY `+dialect.day+`, `+dialect.dayNumber+`
+		) days
+	) islands GROUP BY grp`, userID).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	streaks := []models.Streak{}
+	for rows.Next() {
+		var s models.Streak
+		if err := rows.Scan(&s.Days, &s.Start, &s.End); err != nil {
+			return nil, err
+		}
+		streaks = append(streaks, s)
+	}
+	return streaks, rows.Err()
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transaction implementa SeriesRepository. Las operaciones del repositorio
+// recibido usan la transacción tx; como GORM convierte las transacciones
+// anidadas en SAVEPOINTs, cada operación (y cada Transaction anidada) puede
+// fallar y deshacerse sin abortar la transacción exterior.
+func (r *GormSeriesRepository) Transaction(ctx context.Context, fn func(repo SeriesRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormSeriesRepository{db: tx})
+	})
+}
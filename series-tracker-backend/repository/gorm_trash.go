@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"lab6/models"
+)
+
+// purgeBatchSize es la cantidad de series que Purge elimina por transacción.
+const purgeBatchSize = 500
+
+// Trash implementa SeriesRepository.
+func (r *GormSeriesRepository) Trash(ctx context.Context, userID int) ([]models.Series, error) {
+	series := []models.Series{}
+	err := owned(r.db.WithContext(ctx).Unscoped(), userID).Where("series.deleted_at IS NOT NULL").
+		Order("series.deleted_at DESC").Order("series.id DESC").Find(&series).Error
+	return series, err
+}
+
+// Restore implementa SeriesRepository.
+func (r *GormSeriesRepository) Restore(ctx context.Context, userID, id int) (models.Series, error) {
+	var serie models.Series
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := owned(tx.Unscoped(), userID).Where("series.deleted_at IS NOT NULL").
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&serie, id).Error
+		if err != nil {
+			return translateError(err)
+		}
+		serie.DeletedAt = gorm.DeletedAt{}
+		serie.Version++
+		return tx.Unscoped().Model(&serie).Updates(map[string]any{"deleted_at": nil, "version": serie.Version}).Error
+	})
+	if err != nil {
+		return models.Series{}, err
+	}
+	return serie, nil
+}
+
+// Purge implementa SeriesRepository. Las series se eliminan por lotes, cada uno
+// en una transacción que bloquea las filas, para que una serie restaurada
+// mientras tanto no se elimine. Los datos relacionados se eliminan en la misma
+// transacción (deleteRelated), sin depender de que las claves foráneas estén
+// activas para el ON DELETE CASCADE.
+func (r *GormSeriesRepository) Purge(ctx context.Context, before time.Time) ([]models.Series, error) {
+	purged := []models.Series{}
+	for {
+		var batch []models.Series
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			err := tx.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+				Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+				Order("id").Limit(purgeBatchSize).Find(&batch).Error
+			if err != nil || len(batch) == 0 {
+				return err
+			}
+			ids := make([]int, len(batch))
+			for i, s := range batch {
+				ids[i] = s.ID
+			}
+			if err := deleteRelated(tx, ids); err != nil {
+				return err
+			}
+			return tx.Unscoped().Where("id IN ?", ids).Delete(&models.Series{}).Error
+		})
+		if err != nil {
+			return purged, err
+		}
+		purged = append(purged, batch...)
+		if len(batch) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// deleteRelated elimina el historial, los votos, las reseñas y las asignaciones
+// a etiquetas y listas de las series ids, lo mismo que borrarían en cascada
+// las claves foráneas.
+func deleteRelated(tx *gorm.DB, ids []int) error {
+	related := []any{&models.WatchEvent{}, &models.Vote{}, &models.Review{}, &models.SeriesTag{}, &models.CustomListItem{}}
+	for _, model := range related {
+		if err := tx.Where("series_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumplen las interfaces.
+var (
+	_ UserRepository    = (*GormUserRepository)(nil)
+	_ SessionRepository = (*GormUserRepository)(nil)
+)
+
+// GormUserRepository implementa UserRepository y SessionRepository sobre una conexión GORM.
+type GormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository crea un repositorio de usuarios y sesiones que usa la conexión db.
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
+}
+
+// CreateUser implementa UserRepository.
+func (r *GormUserRepository) CreateUser(ctx context.Context, u *models.User) error {
+	return translateError(r.db.WithContext(ctx).Create(u).Error)
+}
+
+// GetUser implementa UserRepository.
+func (r *GormUserRepository) GetUser(ctx context.Context, id int) (models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return models.User{}, translateError(err)
+	}
+	return user, nil
+}
+
+// GetUserByUsername implementa UserRepository.
+func (r *GormUserRepository) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error; err != nil {
+		return models.User{}, translateError(err)
+	}
+	return user, nil
+}
+
+// CreateSession implementa SessionRepository.
+func (r *GormUserRepository) CreateSession(ctx context.Context, s *models.Session) error {
+	return r.db.WithContext(ctx).Create(s).Error
+}
+
+// GetSessionByTokenHash implementa SessionRepository.
+func (r *GormUserRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (models.Session, error) {
+	var session models.Session
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND expires_at > ?", tokenHash, time.Now().UTC()).
+		First(&session).Error
+	if err != nil {
+		return models.Session{}, translateError(err)
+	}
+	return session, nil
+}
+
+// DeleteSession implementa SessionRepository.
+func (r *GormUserRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	return r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Delete(&models.Session{}).Error
+}
+
+// DeleteExpiredSessions implementa SessionRepository.
+func (r *GormUserRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now.UTC()).Delete(&models.Session{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"lab6/models"
+)
+
+// votable limita la consulta a las series que se pueden votar: las de
+// cualquier usuario, salvo las que no tienen dueño (ver migración 0002).
+func votable(db *gorm.DB) *gorm.DB {
+	return db.Where("series.user_id IS NOT NULL")
+}
+
+// GetVote implementa SeriesRepository.
+func (r *GormSeriesRepository) GetVote(ctx context.Context, userID, id int) (int, error) {
+	if err := votable(r.db.WithContext(ctx)).Select("id").First(&models.Series{}, id).Error; err != nil {
+		return models.VoteNone, translateError(err)
+	}
+	var vote models.Vote
+	err := r.db.WithContext(ctx).Where("series_id = ? AND user_id = ?", id, userID).First(&vote).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.VoteNone, nil
+	}
+	return vote.Value, err
+}
+
+// SetVote implementa SeriesRepository.
+func (r *GormSeriesRepository) SetVote(ctx context.Context, userID, id, value int) (models.Series, error) {
+	return r.vote(ctx, userID, id, func(int) int { return value })
+}
+
+// ToggleVote implementa SeriesRepository.
+func (r *GormSeriesRepository) ToggleVote(ctx context.Context, userID, id, value int) (models.Series, error) {
+	return r.vote(ctx, userID, id, func(current int) int { return models.ToggleVote(current, value) })
+}
+
+// TopSeries implementa SeriesRepository.
+func (r *GormSeriesRepository) TopSeries(ctx context.Context, limit int) ([]models.Series, error) {
+	series := []models.Series{}
+	err := votable(r.db.WithContext(ctx)).Order("ranking DESC").Order("id").Limit(limit).Find(&series).Error
+	return series, err
+}
+
+// vote cambia el voto del usuario sobre la serie (de cualquier dueño) por
+// next(voto actual) y actualiza los contadores de la serie en la misma
+// transacción. La fila de la serie queda bloqueada, por lo que los votos
+// concurrentes no se pierden.
+func (r *GormSeriesRepository) vote(ctx context.Context, userID, id int, next func(current int) int) (models.Series, error) {
+	var serie models.Series
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := votable(tx).Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).First(&serie, id).Error
+		if err != nil {
+			return translateError(err)
+		}
+		if err := checkVersion(ctx, serie.Version); err != nil {
+			return err
+		}
+
+		vote := models.Vote{SeriesID: id, UserID: userID}
+		err = tx.Where("series_id = ? AND user_id = ?", id, userID).First(&vote).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		current := vote.Value
+		value := next(current)
+
+		now := time.Now().UTC()
+		switch {
+		case value == current:
+			return nil // Sin cambios
+		case value == models.VoteNone:
+			err = tx.Where("series_id = ? AND user_id = ?", id, userID).Delete(&models.Vote{}).Error
+		case current == models.VoteNone:
+			vote.Value, vote.CreatedAt, vote.UpdatedAt = value, now, now
+			err = tx.Create(&vote).Error
+		default:
+			err = tx.Model(&models.Vote{}).Where("series_id = ? AND user_id = ?", id, userID).
+				Updates(map[string]any{"value": value, "updated_at": now}).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		serie.ApplyVote(current, value)
+		serie.Version++
+		return tx.Model(&serie).Select("ranking", "upvotes", "downvotes", "version").Updates(&serie).Error
+	})
+	if err != nil {
+		return models.Series{}, err
+	}
+	return serie, nil
+}
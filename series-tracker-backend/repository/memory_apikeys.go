@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ APIKeyRepository = (*MemoryAPIKeyRepository)(nil)
+
+// MemoryAPIKeyRepository implementa APIKeyRepository en memoria.
+// Es seguro para uso concurrente y está pensado para pruebas y desarrollo local.
+type MemoryAPIKeyRepository struct {
+	mu     sync.RWMutex
+	keys   map[int]models.APIKey
+	nextID int
+}
+
+// NewMemoryAPIKeyRepository crea un repositorio de claves de API en memoria vacío.
+func NewMemoryAPIKeyRepository() *MemoryAPIKeyRepository {
+	return &MemoryAPIKeyRepository{keys: make(map[int]models.APIKey), nextID: 1}
+}
+
+// CreateAPIKey implementa APIKeyRepository.
+func (r *MemoryAPIKeyRepository) CreateAPIKey(ctx context.Context, k *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.keys {
+		if existing.KeyHash == k.KeyHash {
+			return ErrConflict
+		}
+	}
+	k.ID = r.nextID
+	r.nextID++
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now()
+	}
+	r.keys[k.ID] = *k
+	return nil
+}
+
+// ListAPIKeys implementa APIKeyRepository.
+func (r *MemoryAPIKeyRepository) ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []models.APIKey{}
+	for _, k := range r.keys {
+		if k.UserID == userID {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, func(a, b models.APIKey) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return keys, nil
+}
+
+// GetAPIKeyByHash implementa APIKeyRepository.
+func (r *MemoryAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash && k.RevokedAt == nil {
+			return k, nil
+		}
+	}
+	return models.APIKey{}, ErrNotFound
+}
+
+// RevokeAPIKey implementa APIKeyRepository.
+func (r *MemoryAPIKeyRepository) RevokeAPIKey(ctx context.Context, userID, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k, ok := r.keys[id]
+	if !ok || k.UserID != userID {
+		return ErrNotFound
+	}
+	if k.RevokedAt == nil {
+		now := time.Now().UTC()
+		k.RevokedAt = &now
+		r.keys[id] = k
+	}
+	return nil
+}
+
+// TouchAPIKey implementa APIKeyRepository.
+func (r *MemoryAPIKeyRepository) TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if k, ok := r.keys[id]; ok {
+		k.LastUsedAt = &usedAt
+		r.keys[id] = k
+	}
+	return nil
+}
@@ -0,0 +1,318 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ CollectionRepository = (*MemoryCollectionRepository)(nil)
+
+// seriesTagKey identifica la asignación de una etiqueta a una serie.
+type seriesTagKey struct {
+	seriesID, tagID int
+}
+
+// listItemKey identifica la pertenencia de una serie a una lista.
+type listItemKey struct {
+	listID, seriesID int
+}
+
+// MemoryCollectionRepository implementa CollectionRepository sobre los datos de
+// un MemorySeriesRepository, de modo que el filtro por etiqueta o lista de
+// List vea las mismas asignaciones.
+type MemoryCollectionRepository struct {
+	series *MemorySeriesRepository
+}
+
+// NewMemoryCollectionRepository crea un repositorio de etiquetas y listas sobre series.
+func NewMemoryCollectionRepository(series *MemorySeriesRepository) *MemoryCollectionRepository {
+	return &MemoryCollectionRepository{series: series}
+}
+
+// compareNames ordena por nombre sin distinguir mayúsculas y luego por id.
+func compareNames(nameA, nameB string, idA, idB int) int {
+	if c := cmp.Compare(strings.ToLower(nameA), strings.ToLower(nameB)); c != 0 {
+		return c
+	}
+	return cmp.Compare(idA, idB)
+}
+
+// ownsSeries indica si la serie existe y pertenece al usuario. Debe llamarse con el lock tomado.
+func (r *MemoryCollectionRepository) ownsSeries(userID, id int) bool {
+	s, ok := r.series.series[id]
+	return ok && s.UserID == userID
+}
+
+// tag devuelve la etiqueta del usuario con su cantidad de series. Debe llamarse con el lock tomado.
+func (r *MemoryCollectionRepository) tag(userID, id int) (models.Tag, bool) {
+	t, ok := r.series.tags[id]
+	if !ok || t.UserID != userID {
+		return models.Tag{}, false
+	}
+	t.SeriesCount = 0
+	for key := range r.series.seriesTags {
+		if _, active := r.series.series[key.seriesID]; active && key.tagID == id {
+			t.SeriesCount++
+		}
+	}
+	return t, true
+}
+
+// tagNameTaken indica si el usuario tiene otra etiqueta (distinta de id) con ese nombre.
+func (r *MemoryCollectionRepository) tagNameTaken(userID, id int, name string) bool {
+	for _, t := range r.series.tags {
+		if t.UserID == userID && t.ID != id && strings.EqualFold(t.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListTags implementa CollectionRepository.
+func (r *MemoryCollectionRepository) ListTags(ctx context.Context, userID int) ([]models.Tag, error) {
+	r.series.mu.RLock()
+	defer r.series.mu.RUnlock()
+
+	tags := []models.Tag{}
+	for id := range r.series.tags {
+		if t, ok := r.tag(userID, id); ok {
+			tags = append(tags, t)
+		}
+	}
+	slices.SortFunc(tags, func(a, b models.Tag) int { return compareNames(a.Name, b.Name, a.ID, b.ID) })
+	return tags, nil
+}
+
+// CreateTag implementa CollectionRepository.
+func (r *MemoryCollectionRepository) CreateTag(ctx context.Context, t *models.Tag) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if r.tagNameTaken(t.UserID, 0, t.Name) {
+		return ErrConflict
+	}
+	t.ID = r.series.nextTagID
+	t.CreatedAt = time.Now().UTC()
+	t.SeriesCount = 0
+	r.series.nextTagID++
+	r.series.tags[t.ID] = *t
+	return nil
+}
+
+// RenameTag implementa CollectionRepository.
+func (r *MemoryCollectionRepository) RenameTag(ctx context.Context, t *models.Tag) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	existing, ok := r.tag(t.UserID, t.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	if r.tagNameTaken(t.UserID, t.ID, t.Name) {
+		return ErrConflict
+	}
+	existing.Name = t.Name
+	r.series.tags[t.ID] = existing
+	*t = existing
+	return nil
+}
+
+// DeleteTag implementa CollectionRepository.
+func (r *MemoryCollectionRepository) DeleteTag(ctx context.Context, userID, id int) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if _, ok := r.tag(userID, id); !ok {
+		return ErrNotFound
+	}
+	delete(r.series.tags, id)
+	for key := range r.series.seriesTags {
+		if key.tagID == id {
+			delete(r.series.seriesTags, key)
+		}
+	}
+	return nil
+}
+
+// SeriesTags implementa CollectionRepository.
+func (r *MemoryCollectionRepository) SeriesTags(ctx context.Context, userID, seriesID int) ([]models.Tag, error) {
+	r.series.mu.RLock()
+	defer r.series.mu.RUnlock()
+
+	if !r.ownsSeries(userID, seriesID) {
+		return nil, ErrNotFound
+	}
+	tags := []models.Tag{}
+	for key := range r.series.seriesTags {
+		if key.seriesID != seriesID {
+			continue
+		}
+		if t, ok := r.tag(userID, key.tagID); ok {
+			tags = append(tags, t)
+		}
+	}
+	slices.SortFunc(tags, func(a, b models.Tag) int { return compareNames(a.Name, b.Name, a.ID, b.ID) })
+	return tags, nil
+}
+
+// AttachTag implementa CollectionRepository.
+func (r *MemoryCollectionRepository) AttachTag(ctx context.Context, userID, seriesID, tagID int) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if _, ok := r.tag(userID, tagID); !ok || !r.ownsSeries(userID, seriesID) {
+		return ErrNotFound
+	}
+	r.series.seriesTags[seriesTagKey{seriesID, tagID}] = struct{}{}
+	return nil
+}
+
+// DetachTag implementa CollectionRepository.
+func (r *MemoryCollectionRepository) DetachTag(ctx context.Context, userID, seriesID, tagID int) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if _, ok := r.tag(userID, tagID); !ok || !r.ownsSeries(userID, seriesID) {
+		return ErrNotFound
+	}
+	delete(r.series.seriesTags, seriesTagKey{seriesID, tagID})
+	return nil
+}
+
+// list devuelve la lista del usuario con su cantidad de series. Debe llamarse con el lock tomado.
+func (r *MemoryCollectionRepository) list(userID, id int) (models.CustomList, bool) {
+	l, ok := r.series.lists[id]
+	if !ok || l.UserID != userID {
+		return models.CustomList{}, false
+	}
+	l.SeriesCount = 0
+	for key := range r.series.listItems {
+		if _, active := r.series.series[key.seriesID]; active && key.listID == id {
+			l.SeriesCount++
+		}
+	}
+	return l, true
+}
+
+// listNameTaken indica si el usuario tiene otra lista (distinta de id) con ese nombre.
+func (r *MemoryCollectionRepository) listNameTaken(userID, id int, name string) bool {
+	for _, l := range r.series.lists {
+		if l.UserID == userID && l.ID != id && strings.EqualFold(l.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListLists implementa CollectionRepository.
+func (r *MemoryCollectionRepository) ListLists(ctx context.Context, userID int) ([]models.CustomList, error) {
+	r.series.mu.RLock()
+	defer r.series.mu.RUnlock()
+
+	lists := []models.CustomList{}
+	for id := range r.series.lists {
+		if l, ok := r.list(userID, id); ok {
+			lists = append(lists, l)
+		}
+	}
+	slices.SortFunc(lists, func(a, b models.CustomList) int { return compareNames(a.Name, b.Name, a.ID, b.ID) })
+	return lists, nil
+}
+
+// GetList implementa CollectionRepository.
+func (r *MemoryCollectionRepository) GetList(ctx context.Context, userID, id int) (models.CustomList, error) {
+	r.series.mu.RLock()
+	defer r.series.mu.RUnlock()
+
+	l, ok := r.list(userID, id)
+	if !ok {
+		return models.CustomList{}, ErrNotFound
+	}
+	return l, nil
+}
+
+// CreateList implementa CollectionRepository.
+func (r *MemoryCollectionRepository) CreateList(ctx context.Context, l *models.CustomList) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if r.listNameTaken(l.UserID, 0, l.Name) {
+		return ErrConflict
+	}
+	now := time.Now().UTC()
+	l.ID = r.series.nextListID
+	l.CreatedAt, l.UpdatedAt = now, now
+	l.SeriesCount = 0
+	r.series.nextListID++
+	r.series.lists[l.ID] = *l
+	return nil
+}
+
+// UpdateList implementa CollectionRepository.
+func (r *MemoryCollectionRepository) UpdateList(ctx context.Context, l *models.CustomList) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	existing, ok := r.list(l.UserID, l.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	if r.listNameTaken(l.UserID, l.ID, l.Name) {
+		return ErrConflict
+	}
+	existing.Name, existing.Description = l.Name, l.Description
+	existing.UpdatedAt = time.Now().UTC()
+	r.series.lists[l.ID] = existing
+	*l = existing
+	return nil
+}
+
+// DeleteList implementa CollectionRepository.
+func (r *MemoryCollectionRepository) DeleteList(ctx context.Context, userID, id int) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if _, ok := r.list(userID, id); !ok {
+		return ErrNotFound
+	}
+	delete(r.series.lists, id)
+	for key := range r.series.listItems {
+		if key.listID == id {
+			delete(r.series.listItems, key)
+		}
+	}
+	return nil
+}
+
+// AddToList implementa CollectionRepository.
+func (r *MemoryCollectionRepository) AddToList(ctx context.Context, userID, listID, seriesID int) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if _, ok := r.list(userID, listID); !ok || !r.ownsSeries(userID, seriesID) {
+		return ErrNotFound
+	}
+	key := listItemKey{listID, seriesID}
+	if _, ok := r.series.listItems[key]; !ok {
+		r.series.listItems[key] = time.Now().UTC()
+	}
+	return nil
+}
+
+// RemoveFromList implementa CollectionRepository.
+func (r *MemoryCollectionRepository) RemoveFromList(ctx context.Context, userID, listID, seriesID int) error {
+	r.series.mu.Lock()
+	defer r.series.mu.Unlock()
+
+	if _, ok := r.list(userID, listID); !ok || !r.ownsSeries(userID, seriesID) {
+		return ErrNotFound
+	}
+	delete(r.series.listItems, listItemKey{listID, seriesID})
+	return nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"lab6/models"
+)
+
+// SetCover implementa SeriesRepository.
+func (r *MemorySeriesRepository) SetCover(ctx context.Context, userID, id int, token, coverURL string) (models.Series, string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[id]
+	if !ok || s.UserID != userID {
+		return models.Series{}, "", ErrNotFound
+	}
+	if err := checkVersion(ctx, s.Version); err != nil {
+		return models.Series{}, "", err
+	}
+	previous := s.CoverKey
+	s.CoverKey, s.CoverURL = token, coverURL
+	s.Version++
+	r.series[id] = s
+	return s, previous, nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ ExportRepository = (*MemoryExportRepository)(nil)
+
+// MemoryExportRepository implementa ExportRepository sobre los datos de un
+// MemorySeriesRepository, con el mismo orden que GormExportRepository.
+type MemoryExportRepository struct {
+	series *MemorySeriesRepository
+}
+
+// NewMemoryExportRepository crea un repositorio de exportación sobre series.
+func NewMemoryExportRepository(series *MemorySeriesRepository) *MemoryExportRepository {
+	return &MemoryExportRepository{series: series}
+}
+
+// ExportSeries implementa ExportRepository. Copia los datos con el lock tomado
+// y llama a fn después de soltarlo, para no bloquear las escrituras mientras
+// se envía la respuesta.
+func (r *MemoryExportRepository) ExportSeries(ctx context.Context, userID int, fn func(models.SeriesExport) error) error {
+	for _, e := range r.snapshot(userID) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshot devuelve las series del usuario con sus datos relacionados.
+func (r *MemoryExportRepository) snapshot(userID int) []models.SeriesExport {
+	r.series.mu.RLock()
+	defer r.series.mu.RUnlock()
+
+	var exports []models.SeriesExport
+	index := make(map[int]int)
+	for _, s := range r.series.series {
+		if s.UserID == userID {
+			exports = append(exports, models.SeriesExport{Series: s})
+		}
+	}
+	slices.SortFunc(exports, func(a, b models.SeriesExport) int { return cmp.Compare(a.ID, b.ID) })
+	for i, e := range exports {
+		index[e.ID] = i
+	}
+
+	tagsOf := make(map[int][]models.Tag)
+	for key := range r.series.seriesTags {
+		if _, ok := index[key.seriesID]; ok {
+			tagsOf[key.seriesID] = append(tagsOf[key.seriesID], r.series.tags[key.tagID])
+		}
+	}
+	listsOf := make(map[int][]models.CustomList)
+	for key := range r.series.listItems {
+		if _, ok := index[key.seriesID]; ok {
+			listsOf[key.seriesID] = append(listsOf[key.seriesID], r.series.lists[key.listID])
+		}
+	}
+
+	for i := range exports {
+		e := &exports[i]
+		tags := tagsOf[e.ID]
+		slices.SortFunc(tags, func(a, b models.Tag) int { return compareNames(a.Name, b.Name, a.ID, b.ID) })
+		e.Tags = make([]string, len(tags))
+		for j, t := range tags {
+			e.Tags[j] = t.Name
+		}
+		lists := listsOf[e.ID]
+		slices.SortFunc(lists, func(a, b models.CustomList) int { return compareNames(a.Name, b.Name, a.ID, b.ID) })
+		e.Lists = make([]string, len(lists))
+		for j, l := range lists {
+			e.Lists[j] = l.Name
+		}
+		if review, ok := r.series.reviews[seriesUserKey{seriesID: e.ID, userID: userID}]; ok {
+			e.Review = &review
+		}
+		e.History = []models.WatchEvent{}
+	}
+
+	// events está en orden de creación, que es el orden por id
+	for _, ev := range r.series.events {
+		if i, ok := index[ev.SeriesID]; ok {
+			exports[i].History = append(exports[i].History, ev)
+		}
+	}
+	return exports
+}
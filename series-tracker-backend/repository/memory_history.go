@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lab6/models"
+)
+
+// recordEvent guarda en el historial el cambio de before a after, si lo hubo.
+// Debe llamarse con el lock de escritura tomado.
+func (r *MemorySeriesRepository) recordEvent(before, after models.Series) {
+	event := models.NewWatchEvent(before, after, time.Now())
+	if event == nil {
+		return
+	}
+	event.ID = r.nextEventID
+	r.nextEventID++
+	r.events = append(r.events, *event)
+}
+
+// History implementa SeriesRepository.
+func (r *MemorySeriesRepository) History(ctx context.Context, userID, id int, q models.HistoryQuery) ([]models.WatchEvent, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.series[id]; !ok || s.UserID != userID {
+		return nil, 0, ErrNotFound
+	}
+
+	// Del más reciente al más antiguo
+	events := []models.WatchEvent{}
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].SeriesID == id {
+			events = append(events, r.events[i])
+		}
+	}
+
+	total := int64(len(events))
+	start := min(q.Offset(), len(events))
+	end := min(start+q.PageSize, len(events))
+	return events[start:end], total, nil
+}
+
+// UndoLastEvent implementa SeriesRepository.
+func (r *MemorySeriesRepository) UndoLastEvent(ctx context.Context, userID, id int) (models.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[id]
+	if !ok || s.UserID != userID {
+		return models.Series{}, ErrNotFound
+	}
+	if err := checkVersion(ctx, s.Version); err != nil {
+		return models.Series{}, err
+	}
+
+	for i := len(r.events) - 1; i >= 0; i-- {
+		if r.events[i].SeriesID != id {
+			continue
+		}
+		s.Undo(r.events[i])
+		s.Version++
+		r.series[id] = s
+		r.events = append(r.events[:i], r.events[i+1:]...)
+		return s, nil
+	}
+	return models.Series{}, ErrNoHistory
+}
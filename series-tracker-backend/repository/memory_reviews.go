@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"lab6/models"
+)
+
+// GetReview implementa SeriesRepository.
+func (r *MemorySeriesRepository) GetReview(ctx context.Context, userID, id int) (models.Review, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.series[id]; !ok || s.UserID != userID {
+		return models.Review{}, ErrNotFound
+	}
+	review, ok := r.reviews[seriesUserKey{id, userID}]
+	if !ok {
+		return models.Review{}, ErrNoReview
+	}
+	return review, nil
+}
+
+// SaveReview implementa SeriesRepository.
+func (r *MemorySeriesRepository) SaveReview(ctx context.Context, review *models.Review) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.series[review.SeriesID]; !ok || s.UserID != review.UserID {
+		return false, ErrNotFound
+	}
+	key := seriesUserKey{review.SeriesID, review.UserID}
+	now := time.Now().UTC()
+	existing, ok := r.reviews[key]
+	review.CreatedAt, review.UpdatedAt = now, now
+	if ok {
+		review.CreatedAt = existing.CreatedAt
+	}
+	r.reviews[key] = *review
+	return !ok, nil
+}
+
+// DeleteReview implementa SeriesRepository.
+func (r *MemorySeriesRepository) DeleteReview(ctx context.Context, userID, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.series[id]; !ok || s.UserID != userID {
+		return ErrNotFound
+	}
+	key := seriesUserKey{id, userID}
+	if _, ok := r.reviews[key]; !ok {
+		return ErrNoReview
+	}
+	delete(r.reviews, key)
+	return nil
+}
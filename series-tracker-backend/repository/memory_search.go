@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"lab6/models"
+)
+
+// Search implementa SeriesRepository comparando los títulos de todas las series
+// del usuario, con la misma relevancia que GormSeriesRepository.
+func (r *MemorySeriesRepository) Search(ctx context.Context, userID int, query string, limit int) ([]models.SearchResult, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var candidates []models.Series
+	for _, s := range r.series {
+		if s.UserID == userID {
+			candidates = append(candidates, s)
+		}
+	}
+	return rankSearch(searchTerms(query), candidates, limit), nil
+}
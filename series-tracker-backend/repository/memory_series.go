@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ SeriesRepository = (*MemorySeriesRepository)(nil)
+
+// MemorySeriesRepository implementa SeriesRepository guardando las series en memoria.
+// Es seguro para uso concurrente y está pensado para pruebas y desarrollo local;
+// los datos se pierden al terminar el proceso.
+type MemorySeriesRepository struct {
+	mu     sync.RWMutex
+	series map[int]models.Series
+	nextID int
+
+	// trash son las series eliminadas (con DeletedAt), fuera de series para que
+	// el resto de las operaciones no las vean.
+	trash map[int]models.Series
+
+	// events es el historial de todas las series, en orden de creación.
+	events      []models.WatchEvent
+	nextEventID int
+
+	// votes guarda el voto de cada usuario por serie.
+	votes map[seriesUserKey]int
+	// reviews guarda la reseña de cada usuario por serie.
+	reviews map[seriesUserKey]models.Review
+
+	// tags y lists son las etiquetas y listas personalizadas de todos los
+	// usuarios; seriesTags y listItems, su relación con las series.
+	tags       map[int]models.Tag
+	nextTagID  int
+	seriesTags map[seriesTagKey]struct{}
+	lists      map[int]models.CustomList
+	nextListID int
+	listItems  map[listItemKey]time.Time
+}
+
+// NewMemorySeriesRepository crea un repositorio en memoria vacío.
+func NewMemorySeriesRepository() *MemorySeriesRepository {
+	return &MemorySeriesRepository{
+		series:      make(map[int]models.Series),
+		nextID:      1,
+		trash:       make(map[int]models.Series),
+		nextEventID: 1,
+		votes:       make(map[seriesUserKey]int),
+		reviews:     make(map[seriesUserKey]models.Review),
+		tags:        make(map[int]models.Tag),
+		nextTagID:   1,
+		seriesTags:  make(map[seriesTagKey]struct{}),
+		lists:       make(map[int]models.CustomList),
+		nextListID:  1,
+		listItems:   make(map[listItemKey]time.Time),
+	}
+}
+
+// matches indica si la serie s cumple los filtros de q.
+func matches(s models.Series, q models.SeriesQuery) bool {
+	if q.Search != "" && !strings.Contains(strings.ToLower(s.Title), strings.ToLower(q.Search)) {
+		return false
+	}
+	if q.Status != "" && s.Status != q.Status {
+		return false
+	}
+	if q.MinRanking != nil && s.Ranking < *q.MinRanking {
+		return false
+	}
+	if q.MaxRanking != nil && s.Ranking > *q.MaxRanking {
+		return false
+	}
+	return true
+}
+
+// compareSeries compara dos series según el campo sortBy (ver models.SeriesSortFields).
+func compareSeries(a, b models.Series, sortBy string) int {
+	switch sortBy {
+	case "title":
+		return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	case "status":
+		return cmp.Compare(a.Status, b.Status)
+	case "lastEpisodeWatched":
+		return cmp.Compare(a.LastEpisodeWatched, b.LastEpisodeWatched)
+	case "totalEpisodes":
+		return cmp.Compare(a.TotalEpisodes, b.TotalEpisodes)
+	case "ranking":
+		return cmp.Compare(a.Ranking, b.Ranking)
+	}
+	return cmp.Compare(a.ID, b.ID)
+}
+
+// inCollections indica si la serie con el ID indicado tiene todas las etiquetas
+// de q.TagIDs y pertenece a la lista q.ListID. Debe llamarse con el lock tomado.
+func (r *MemorySeriesRepository) inCollections(id int, q models.SeriesQuery) bool {
+	for _, tagID := range q.TagIDs {
+		if _, ok := r.seriesTags[seriesTagKey{id, tagID}]; !ok {
+			return false
+		}
+	}
+	if q.ListID != nil {
+		if _, ok := r.listItems[listItemKey{*q.ListID, id}]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// compareScores compara las puntuaciones de las reseñas de los dueños de a y b;
+// las series sin puntuar van siempre al final, en cualquier dirección.
+// Debe llamarse con el lock tomado.
+func (r *MemorySeriesRepository) compareScores(a, b models.Series, desc bool) int {
+	sa := r.reviews[seriesUserKey{a.ID, a.UserID}].Score
+	sb := r.reviews[seriesUserKey{b.ID, b.UserID}].Score
+	switch {
+	case sa == nil || sb == nil:
+		return cmp.Compare(boolToInt(sa == nil), boolToInt(sb == nil))
+	case desc:
+		return cmp.Compare(*sb, *sa)
+	}
+	return cmp.Compare(*sa, *sb)
+}
+
+// boolToInt devuelve 1 si b es verdadero y 0 si no.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// List implementa SeriesRepository.
+func (r *MemorySeriesRepository) List(ctx context.Context, userID int, q models.SeriesQuery) ([]models.Series, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []models.Series{}
+	for _, s := range r.series {
+		if s.UserID == userID && matches(s, q) && r.inCollections(s.ID, q) {
+			result = append(result, s)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b models.Series) int {
+		var c int
+		if q.SortBy == "score" {
+			c = r.compareScores(a, b, q.SortDesc)
+		} else {
+			c = compareSeries(a, b, q.SortBy)
+			if q.SortDesc {
+				c = -c
+			}
+		}
+		// El id como desempate garantiza un orden estable entre páginas
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		return c
+	})
+
+	total := int64(len(result))
+	if q.PageSize > 0 {
+		start := min(q.Offset(), len(result))
+		end := min(start+q.PageSize, len(result))
+		result = result[start:end]
+	}
+	return result, total, nil
+}
+
+// Get implementa SeriesRepository.
+func (r *MemorySeriesRepository) Get(ctx context.Context, userID, id int) (models.Series, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.series[id]
+	if !ok || s.UserID != userID {
+		return models.Series{}, ErrNotFound
+	}
+	return s, nil
+}
+
+// Create implementa SeriesRepository.
+func (r *MemorySeriesRepository) Create(ctx context.Context, s *models.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.ID = r.nextID
+	s.Version = 1
+	s.Ranking, s.Upvotes, s.Downvotes, s.LegacyRanking = 0, 0, 0, 0
+	s.CoverKey = ""
+	r.nextID++
+	r.series[s.ID] = *s
+	return nil
+}
+
+// Update implementa SeriesRepository.
+func (r *MemorySeriesRepository) Update(ctx context.Context, s *models.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.series[s.ID]
+	if !ok || existing.UserID != s.UserID {
+		return ErrNotFound
+	}
+	if err := checkVersion(ctx, existing.Version); err != nil {
+		return err
+	}
+	if err := models.ValidateTransition(existing.Status, s.Status); err != nil {
+		return err
+	}
+	s.ApplyProgressRules()
+	s.Version = existing.Version + 1
+	s.Ranking, s.Upvotes, s.Downvotes, s.LegacyRanking = existing.Ranking, existing.Upvotes, existing.Downvotes, existing.LegacyRanking
+	s.CoverKey = existing.CoverKey
+	r.series[s.ID] = *s
+	r.recordEvent(existing, *s)
+	return nil
+}
+
+// Delete implementa SeriesRepository.
+func (r *MemorySeriesRepository) Delete(ctx context.Context, userID, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[id]
+	if !ok || s.UserID != userID {
+		return ErrNotFound
+	}
+	if err := checkVersion(ctx, s.Version); err != nil {
+		return err
+	}
+	delete(r.series, id)
+	s.DeletedAt = gorm.DeletedAt{Time: time.Now().UTC(), Valid: true}
+	r.trash[id] = s
+	return nil
+}
+
+// modify aplica fn sobre la serie con el ID indicado (del usuario userID) bajo el
+// lock de escritura, incrementa su versión y devuelve la serie resultante. Si la
+// versión no es la esperada (WithIfMatch) o fn devuelve un error la serie no se
+// modifica, y si fn no la cambió se devuelve tal cual, sin incrementar la versión.
+// Si cambian el progreso o el estado se registra el evento en el historial.
+func (r *MemorySeriesRepository) modify(ctx context.Context, userID, id int, fn modifyFunc) (models.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[id]
+	if !ok || s.UserID != userID {
+		return models.Series{}, ErrNotFound
+	}
+	if err := checkVersion(ctx, s.Version); err != nil {
+		return models.Series{}, err
+	}
+	before := s
+	changed, err := fn(&s)
+	if err != nil {
+		return models.Series{}, err
+	}
+	if !changed {
+		return before, nil
+	}
+	s.Version++
+	r.series[id] = s
+	r.recordEvent(before, s)
+	return s, nil
+}
+
+// UpdateStatus implementa SeriesRepository.
+func (r *MemorySeriesRepository) UpdateStatus(ctx context.Context, userID, id int, status models.SeriesStatus) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.TransitionTo(status)
+	}))
+}
+
+// Rewatch implementa SeriesRepository.
+func (r *MemorySeriesRepository) Rewatch(ctx context.Context, userID, id int) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.Rewatch()
+	}))
+}
+
+// IncrementEpisode implementa SeriesRepository.
+func (r *MemorySeriesRepository) IncrementEpisode(ctx context.Context, userID, id int) (models.Series, error) {
+	return r.modify(ctx, userID, id, func(s *models.Series) (bool, error) {
+		return s.WatchNextEpisode()
+	})
+}
+
+// DecrementEpisode implementa SeriesRepository.
+func (r *MemorySeriesRepository) DecrementEpisode(ctx context.Context, userID, id int) (models.Series, error) {
+	return r.modify(ctx, userID, id, func(s *models.Series) (bool, error) {
+		return s.UnwatchLastEpisode()
+	})
+}
+
+// SetEpisode implementa SeriesRepository.
+func (r *MemorySeriesRepository) SetEpisode(ctx context.Context, userID, id int, ref models.EpisodeRef) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.SetProgress(ref)
+	}))
+}
+
+// WatchRange implementa SeriesRepository.
+func (r *MemorySeriesRepository) WatchRange(ctx context.Context, userID, id int, rng models.EpisodeRange) (models.Series, error) {
+	return r.modify(ctx, userID, id, trackProgress(func(s *models.Series) error {
+		return s.WatchRange(rng)
+	}))
+}
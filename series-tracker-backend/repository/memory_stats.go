@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ StatsRepository = (*MemoryStatsRepository)(nil)
+
+// MemoryStatsRepository implementa StatsRepository sobre los datos de un
+// MemorySeriesRepository, con los mismos criterios que GormStatsRepository.
+type MemoryStatsRepository struct {
+	series *MemorySeriesRepository
+}
+
+// NewMemoryStatsRepository crea un repositorio de estadísticas sobre series.
+func NewMemoryStatsRepository(series *MemorySeriesRepository) *MemoryStatsRepository {
+	return &MemoryStatsRepository{series: series}
+}
+
+// Stats implementa StatsRepository.
+func (r *MemoryStatsRepository) Stats(ctx context.Context, userID int, q models.StatsQuery) (models.Stats, error) {
+	r.series.mu.RLock()
+	defer r.series.mu.RUnlock()
+
+	var stats models.Stats
+
+	// Totales y agrupación por estado
+	type statusAcc struct {
+		series, withTotal int64
+		progress          float64
+	}
+	acc := make(map[models.SeriesStatus]*statusAcc)
+	for _, s := range r.series.series {
+		if s.UserID != userID {
+			continue
+		}
+		stats.Totals.Series++
+		stats.Totals.EpisodesWatched += int64(s.LastEpisodeWatched)
+		stats.Totals.TotalEpisodes += int64(s.TotalEpisodes)
+		a, ok := acc[s.Status]
+		if !ok {
+			a = &statusAcc{}
+			acc[s.Status] = a
+		}
+		a.series++
+		if s.TotalEpisodes > 0 {
+			a.withTotal++
+			a.progress += float64(s.LastEpisodeWatched) / float64(s.TotalEpisodes)
+		}
+	}
+	stats.ByStatus = []models.StatusStats{}
+	for status, a := range acc {
+		s := models.StatusStats{Status: status, Series: a.series, Share: float64(a.series) / float64(stats.Totals.Series)}
+		if a.withTotal > 0 {
+			rate := a.progress / float64(a.withTotal)
+			s.CompletionRate = &rate
+		}
+		stats.ByStatus = append(stats.ByStatus, s)
+	}
+	slices.SortFunc(stats.ByStatus, func(a, b models.StatusStats) int {
+		return cmp.Compare(a.Status, b.Status)
+	})
+
+	// Historial
+	perDay := make(map[string]int64)
+	perWeek := make(map[string]int64)
+	perMonth := make(map[string]int64)
+	started := make(map[int]time.Time)
+	finished := make(map[int]time.Time)
+	daysWatched := make(map[string]bool)
+	daysSince, weeksSince, monthsSince := q.DaysSince(), q.WeeksSince(), q.MonthsSince()
+	for _, e := range r.series.events {
+		if _, trashed := r.series.trash[e.SeriesID]; trashed || e.UserID != userID {
+			continue
+		}
+		stats.Totals.Events++
+		if t, ok := started[e.SeriesID]; !ok || e.WatchedAt.Before(t) {
+			started[e.SeriesID] = e.WatchedAt
+		}
+		if e.Status == models.StatusCompleted && e.PreviousStatus != models.StatusCompleted {
+			if t, ok := finished[e.SeriesID]; !ok || e.WatchedAt.Before(t) {
+				finished[e.SeriesID] = e.WatchedAt
+			}
+		}
+
+		episodes := int64(e.Episode - e.PreviousEpisode)
+		if episodes <= 0 {
+			continue
+		}
+		stats.Totals.HistoryEpisodes += episodes
+		daysWatched[e.WatchedAt.UTC().Format(models.DayFormat)] = true
+		if !e.WatchedAt.Before(daysSince) {
+			perDay[e.WatchedAt.UTC().Format(models.DayFormat)] += episodes
+		}
+		if !e.WatchedAt.Before(weeksSince) {
+			perWeek[models.StartOfWeek(e.WatchedAt).Format(models.DayFormat)] += episodes
+		}
+		if !e.WatchedAt.Before(monthsSince) {
+			perMonth[e.WatchedAt.UTC().Format(models.MonthFormat)] += episodes
+		}
+	}
+
+	stats.EpisodesPerDay = models.FillPeriods(perDay, daysSince, q.Now, func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }, models.DayFormat)
+	stats.EpisodesPerWeek = models.FillPeriods(perWeek, weeksSince, q.Now, func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }, models.DayFormat)
+	stats.EpisodesPerMonth = models.FillPeriods(perMonth, monthsSince, q.Now, func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }, models.MonthFormat)
+
+	if len(finished) > 0 {
+		var total time.Duration
+		for id, end := range finished {
+			total += end.Sub(started[id])
+		}
+		days := total.Hours() / 24 / float64(len(finished))
+		stats.AverageDaysToFinish = &days
+	}
+
+	// Rachas: recorrer los días con actividad en orden
+	days := make([]string, 0, len(daysWatched))
+	for day := range daysWatched {
+		days = append(days, day)
+	}
+	slices.Sort(days)
+	streaks := []models.Streak{}
+	for _, day := range days {
+		if n := len(streaks); n > 0 {
+			last, _ := time.Parse(models.DayFormat, streaks[n-1].End)
+			if last.AddDate(0, 0, 1).Format(models.DayFormat) == day {
+				streaks[n-1].End = day
+				streaks[n-1].Days++
+				continue
+			}
+		}
+		streaks = append(streaks, models.Streak{Days: 1, Start: day, End: day})
+	}
+	stats.Streaks = models.PickStreaks(streaks, q.Now)
+
+	return stats, nil
+}
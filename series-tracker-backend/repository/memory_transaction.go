@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// Transaction implementa SeriesRepository. fn trabaja sobre una copia de los
+// datos mientras el repositorio queda bloqueado para las demás solicitudes; si
+// fn termina sin error la copia reemplaza a los datos originales.
+func (r *MemorySeriesRepository) Transaction(ctx context.Context, fn func(repo SeriesRepository) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := r.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.restore(tx)
+	return nil
+}
+
+// clone devuelve una copia independiente de los datos. Debe llamarse con el lock tomado.
+func (r *MemorySeriesRepository) clone() *MemorySeriesRepository {
+	return &MemorySeriesRepository{
+		series:      maps.Clone(r.series),
+		nextID:      r.nextID,
+		trash:       maps.Clone(r.trash),
+		events:      slices.Clone(r.events),
+		nextEventID: r.nextEventID,
+		votes:       maps.Clone(r.votes),
+		reviews:     maps.Clone(r.reviews),
+		tags:        maps.Clone(r.tags),
+		nextTagID:   r.nextTagID,
+		seriesTags:  maps.Clone(r.seriesTags),
+		lists:       maps.Clone(r.lists),
+		nextListID:  r.nextListID,
+		listItems:   maps.Clone(r.listItems),
+	}
+}
+
+// restore reemplaza los datos por los de tx. Debe llamarse con el lock tomado.
+func (r *MemorySeriesRepository) restore(tx *MemorySeriesRepository) {
+	r.series, r.nextID, r.trash = tx.series, tx.nextID, tx.trash
+	r.events, r.nextEventID = tx.events, tx.nextEventID
+	r.votes, r.reviews = tx.votes, tx.reviews
+	r.tags, r.nextTagID, r.seriesTags = tx.tags, tx.nextTagID, tx.seriesTags
+	r.lists, r.nextListID, r.listItems = tx.lists, tx.nextListID, tx.listItems
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+
+	"lab6/models"
+)
+
+// Trash implementa SeriesRepository.
+func (r *MemorySeriesRepository) Trash(ctx context.Context, userID int) ([]models.Series, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := []models.Series{}
+	for _, s := range r.trash {
+		if s.UserID == userID {
+			series = append(series, s)
+		}
+	}
+	slices.SortFunc(series, func(a, b models.Series) int {
+		if c := b.DeletedAt.Time.Compare(a.DeletedAt.Time); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+	return series, nil
+}
+
+// Restore implementa SeriesRepository.
+func (r *MemorySeriesRepository) Restore(ctx context.Context, userID, id int) (models.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.trash[id]
+	if !ok || s.UserID != userID {
+		return models.Series{}, ErrNotFound
+	}
+	delete(r.trash, id)
+	s.DeletedAt = gorm.DeletedAt{}
+	s.Version++
+	r.series[id] = s
+	return s, nil
+}
+
+// Purge implementa SeriesRepository.
+func (r *MemorySeriesRepository) Purge(ctx context.Context, before time.Time) ([]models.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := []models.Series{}
+	for id, s := range r.trash {
+		if !s.DeletedAt.Time.Before(before) {
+			continue
+		}
+		delete(r.trash, id)
+		r.deleteRelated(id)
+		purged = append(purged, s)
+	}
+	slices.SortFunc(purged, func(a, b models.Series) int { return cmp.Compare(a.ID, b.ID) })
+	return purged, nil
+}
+
+// deleteRelated elimina el historial, los votos, las reseñas y las asignaciones
+// a etiquetas y listas de la serie, como las claves foráneas con ON DELETE
+// CASCADE de la base de datos. Debe llamarse con el lock tomado.
+func (r *MemorySeriesRepository) deleteRelated(id int) {
+	r.events = slices.DeleteFunc(r.events, func(e models.WatchEvent) bool { return e.SeriesID == id })
+	for key := range r.votes {
+		if key.seriesID == id {
+			delete(r.votes, key)
+		}
+	}
+	for key := range r.reviews {
+		if key.seriesID == id {
+			delete(r.reviews, key)
+		}
+	}
+	for key := range r.seriesTags {
+		if key.seriesID == id {
+			delete(r.seriesTags, key)
+		}
+	}
+	for key := range r.listItems {
+		if key.seriesID == id {
+			delete(r.listItems, key)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"lab6/models"
+)
+
+// Verificación en tiempo de compilación de que se cumplen las interfaces.
+var (
+	_ UserRepository    = (*MemoryUserRepository)(nil)
+	_ SessionRepository = (*MemoryUserRepository)(nil)
+)
+
+// MemoryUserRepository implementa UserRepository y SessionRepository en memoria.
+// Es seguro para uso concurrente y está pensado para pruebas y desarrollo local.
+type MemoryUserRepository struct {
+	mu            sync.RWMutex
+	users         map[int]models.User
+	sessions      map[string]models.Session // indexadas por TokenHash
+	nextUserID    int
+	nextSessionID int
+}
+
+// NewMemoryUserRepository crea un repositorio de usuarios y sesiones en memoria vacío.
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{
+		users:         make(map[int]models.User),
+		sessions:      make(map[string]models.Session),
+		nextUserID:    1,
+		nextSessionID: 1,
+	}
+}
+
+// CreateUser implementa UserRepository.
+func (r *MemoryUserRepository) CreateUser(ctx context.Context, u *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == u.Username {
+			return ErrConflict
+		}
+	}
+	u.ID = r.nextUserID
+	r.nextUserID++
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+	r.users[u.ID] = *u
+	return nil
+}
+
+// GetUser implementa UserRepository.
+func (r *MemoryUserRepository) GetUser(ctx context.Context, id int) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+// GetUserByUsername implementa UserRepository.
+func (r *MemoryUserRepository) GetUserByUsername(ctx context.Context, username string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return models.User{}, ErrNotFound
+}
+
+// CreateSession implementa SessionRepository.
+func (r *MemoryUserRepository) CreateSession(ctx context.Context, s *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.ID = r.nextSessionID
+	r.nextSessionID++
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now()
+	}
+	r.sessions[s.TokenHash] = *s
+	return nil
+}
+
+// GetSessionByTokenHash implementa SessionRepository.
+func (r *MemoryUserRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (models.Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.sessions[tokenHash]
+	if !ok || !s.ExpiresAt.After(time.Now()) {
+		return models.Session{}, ErrNotFound
+	}
+	return s, nil
+}
+
+// DeleteSession implementa SessionRepository.
+func (r *MemoryUserRepository) DeleteSession(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sessions, tokenHash)
+	return nil
+}
+
+// DeleteExpiredSessions implementa SessionRepository.
+func (r *MemoryUserRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for tokenHash, s := range r.sessions {
+		if !s.ExpiresAt.After(now) {
+			delete(r.sessions, tokenHash)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"lab6/models"
+)
+
+// seriesUserKey identifica los datos de un usuario sobre una serie (voto, reseña).
+type seriesUserKey struct {
+	seriesID, userID int
+}
+
+// GetVote implementa SeriesRepository.
+func (r *MemorySeriesRepository) GetVote(ctx context.Context, userID, id int) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.series[id]; !ok || s.UserID == 0 {
+		return models.VoteNone, ErrNotFound
+	}
+	return r.votes[seriesUserKey{id, userID}], nil
+}
+
+// SetVote implementa SeriesRepository.
+func (r *MemorySeriesRepository) SetVote(ctx context.Context, userID, id, value int) (models.Series, error) {
+	return r.vote(ctx, userID, id, func(int) int { return value })
+}
+
+// ToggleVote implementa SeriesRepository.
+func (r *MemorySeriesRepository) ToggleVote(ctx context.Context, userID, id, value int) (models.Series, error) {
+	return r.vote(ctx, userID, id, func(current int) int { return models.ToggleVote(current, value) })
+}
+
+// TopSeries implementa SeriesRepository.
+func (r *MemorySeriesRepository) TopSeries(ctx context.Context, limit int) ([]models.Series, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	series := []models.Series{}
+	for _, s := range r.series {
+		if s.UserID != 0 {
+			series = append(series, s)
+		}
+	}
+	slices.SortFunc(series, func(a, b models.Series) int {
+		return cmp.Or(cmp.Compare(b.Ranking, a.Ranking), cmp.Compare(a.ID, b.ID))
+	})
+	return series[:min(limit, len(series))], nil
+}
+
+// vote cambia el voto del usuario sobre la serie (de cualquier dueño) por
+// next(voto actual) y actualiza los contadores de la serie bajo el lock de escritura.
+func (r *MemorySeriesRepository) vote(ctx context.Context, userID, id int, next func(current int) int) (models.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.series[id]
+	if !ok || s.UserID == 0 {
+		return models.Series{}, ErrNotFound
+	}
+	if err := checkVersion(ctx, s.Version); err != nil {
+		return models.Series{}, err
+	}
+
+	key := seriesUserKey{id, userID}
+	current := r.votes[key]
+	value := next(current)
+	if value == current {
+		return s, nil
+	}
+	if value == models.VoteNone {
+		delete(r.votes, key)
+	} else {
+		r.votes[key] = value
+	}
+
+	s.ApplyVote(current, value)
+	s.Version++
+	r.series[id] = s
+	return s, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrPreconditionFailed se devuelve cuando la versión actual de la serie no es
+// ninguna de las esperadas (ver WithIfMatch): otra solicitud la modificó antes.
+var ErrPreconditionFailed = errors.New("la versión de la serie no coincide con la esperada")
+
+// ifMatchKey es la clave de contexto de las versiones esperadas.
+type ifMatchKey struct{}
+
+// WithIfMatch devuelve una copia de ctx que condiciona las modificaciones de
+// series (Update, Delete, UpdateStatus, IncrementEpisode, SetVote, ...)
+// a que la versión actual sea una de versions. La comprobación se hace en la
+// misma transacción que la escritura, por lo que no hay ventana de carrera.
+// Corresponde al header HTTP If-Match.
+func WithIfMatch(ctx context.Context, versions ...int) context.Context {
+	return context.WithValue(ctx, ifMatchKey{}, versions)
+}
+
+// checkVersion devuelve ErrPreconditionFailed si ctx exige versiones
+// concretas y current no es ninguna de ellas.
+func checkVersion(ctx context.Context, current int) error {
+	versions, ok := ctx.Value(ifMatchKey{}).([]int)
+	if !ok || slices.Contains(versions, current) {
+		return nil
+	}
+	return ErrPreconditionFailed
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"lab6/models"
+)
+
+// ErrNotFound se devuelve cuando el registro solicitado no existe.
+// Las implementaciones lo usan en lugar de errores específicos del motor
+// (como gorm.ErrRecordNotFound) para que los handlers no dependan de GORM.
+// Un registro que pertenece a otro usuario también se reporta como ErrNotFound.
+var ErrNotFound = errors.New("registro no encontrado")
+
+// ErrConflict se devuelve cuando la operación viola una restricción de unicidad
+// (por ejemplo, un nombre de usuario ya registrado).
+var ErrConflict = errors.New("el registro ya existe")
+
+// ErrNoHistory se devuelve al intentar deshacer un cambio de una serie que no
+// tiene eventos en su historial.
+var ErrNoHistory = errors.New("la serie no tiene cambios para deshacer")
+
+// ErrNoReview se devuelve cuando el usuario no tiene reseña de la serie.
+var ErrNoReview = errors.New("la serie no tiene reseña")
+
+// SeriesRepository define las operaciones de persistencia sobre las series.
+// Los handlers dependen únicamente de esta interfaz, lo que permite cambiar
+// el almacenamiento (GORM/MySQL, memoria) o sustituirlo en pruebas.
+// Todas las operaciones se limitan a las series del usuario userID, salvo los
+// votos (GetVote, SetVote, ToggleVote, TopSeries): cualquier usuario puede votar
+// las series de los demás y el ranking agrega los votos de todos. Las que
+// modifican una serie existente incrementan su versión (las de progreso y
+// estado solo si algo cambió) y, si el contexto trae versiones esperadas
+// (WithIfMatch), devuelven ErrPreconditionFailed cuando la versión actual no
+// coincide. Cada cambio de progreso o de estado queda registrado en el
+// historial (models.WatchEvent) en la misma transacción.
+type SeriesRepository interface {
+	// List devuelve la página de series que cumple los criterios de q y el
+	// total de series que coinciden con los filtros (sin paginar).
+	List(ctx context.Context, userID int, q models.SeriesQuery) ([]models.Series, int64, error)
+	// Search devuelve hasta limit series del usuario cuyo título o títulos
+	// alternativos coinciden con query (por palabras, prefijos o con errores de
+	// escritura), de la más a la menos relevante.
+	Search(ctx context.Context, userID int, query string, limit int) ([]models.SearchResult, error)
+	// Get devuelve la serie con el ID indicado o ErrNotFound.
+	Get(ctx context.Context, userID, id int) (models.Series, error)
+	// Create guarda una nueva serie (de s.UserID), sin votos ni portada subida, y asigna su ID en s.
+	Create(ctx context.Context, s *models.Series) error
+	// Update sobrescribe todos los campos de la serie con ID s.ID de s.UserID,
+	// salvo el ranking y los votos, que solo cambian al votar, y el token de la
+	// portada subida, que solo cambia con SetCover.
+	// Devuelve un *models.StatusError si el cambio de estado no está permitido.
+	Update(ctx context.Context, s *models.Series) error
+	// Delete mueve la serie con el ID indicado a la papelera o devuelve
+	// ErrNotFound. Sus datos relacionados (historial, reseña, votos, etiquetas y
+	// listas) se conservan hasta que se purga.
+	Delete(ctx context.Context, userID, id int) error
+	// Trash devuelve las series del usuario que están en la papelera, de la
+	// eliminada más recientemente a la más antigua, con DeletedAt completo.
+	Trash(ctx context.Context, userID int) ([]models.Series, error)
+	// Restore saca la serie de la papelera, incrementa su versión y la devuelve.
+	// Devuelve ErrNotFound si la serie no está en la papelera.
+	Restore(ctx context.Context, userID, id int) (models.Series, error)
+	// Purge elimina definitivamente, con sus datos relacionados, las series de
+	// todos los usuarios que están en la papelera desde antes de before, y
+	// devuelve las series eliminadas (para borrar sus portadas subidas).
+	Purge(ctx context.Context, before time.Time) ([]models.Series, error)
+	// UpdateStatus cambia el estado (ver models.Series.TransitionTo) y devuelve la
+	// serie actualizada, o un *models.StatusError si la transición no está permitida.
+	UpdateStatus(ctx context.Context, userID, id int, status models.SeriesStatus) (models.Series, error)
+	// Rewatch vuelve a ver desde el principio una serie completada (ver
+	// models.Series.Rewatch) y devuelve la serie actualizada, o un
+	// *models.ProgressError si la serie no está completada.
+	Rewatch(ctx context.Context, userID, id int) (models.Series, error)
+	// IncrementEpisode suma un episodio visto sin superar TotalEpisodes (si es > 0),
+	// ajusta el estado (ver models.Series.WatchNextEpisode) y devuelve la serie actualizada.
+	IncrementEpisode(ctx context.Context, userID, id int) (models.Series, error)
+	// DecrementEpisode resta un episodio visto (sin bajar de 0) y devuelve la serie
+	// actualizada; una serie completada vuelve a 'Watching'.
+	DecrementEpisode(ctx context.Context, userID, id int) (models.Series, error)
+	// SetEpisode fija el último episodio visto (ver models.Series.SetProgress) y
+	// devuelve la serie actualizada, o un *models.ProgressError si ref no es válido.
+	SetEpisode(ctx context.Context, userID, id int, ref models.EpisodeRef) (models.Series, error)
+	// WatchRange marca como vistos los episodios de rng (ver models.Series.WatchRange)
+	// y devuelve la serie actualizada, o un *models.ProgressError si el rango no es válido.
+	WatchRange(ctx context.Context, userID, id int, rng models.EpisodeRange) (models.Series, error)
+	// GetVote devuelve el voto del usuario sobre la serie (models.VoteNone si no votó) o ErrNotFound.
+	GetVote(ctx context.Context, userID, id int) (int, error)
+	// SetVote fija el voto del usuario sobre la serie (models.VoteNone lo retira),
+	// recalcula el ranking y devuelve la serie actualizada.
+	SetVote(ctx context.Context, userID, id, value int) (models.Series, error)
+	// ToggleVote aplica value con models.ToggleVote (el mismo voto se retira, uno
+	// distinto lo reemplaza), recalcula el ranking y devuelve la serie actualizada.
+	ToggleVote(ctx context.Context, userID, id, value int) (models.Series, error)
+	// TopSeries devuelve las limit series de todos los usuarios con mayor
+	// ranking, de mayor a menor (a igual ranking, por ID).
+	TopSeries(ctx context.Context, limit int) ([]models.Series, error)
+	// GetReview devuelve la reseña del usuario sobre la serie, ErrNoReview si no
+	// tiene o ErrNotFound si la serie no existe.
+	GetReview(ctx context.Context, userID, id int) (models.Review, error)
+	// SaveReview crea o reemplaza la reseña de review.UserID sobre review.SeriesID,
+	// asigna sus fechas e indica si fue creada. La reseña no forma parte de la
+	// serie: no cambia su versión ni se registra en el historial.
+	SaveReview(ctx context.Context, review *models.Review) (created bool, err error)
+	// DeleteReview elimina la reseña del usuario sobre la serie o devuelve ErrNoReview.
+	DeleteReview(ctx context.Context, userID, id int) error
+	// SetCover reemplaza la portada subida de la serie: guarda token (el de las
+	// imágenes en el almacenamiento de blobs, vacío para quitarla) y coverURL,
+	// incrementa la versión y devuelve la serie actualizada y el token anterior,
+	// cuyas imágenes el llamador puede eliminar.
+	SetCover(ctx context.Context, userID, id int, token, coverURL string) (models.Series, string, error)
+	// History devuelve la página de eventos del historial de la serie (del más
+	// reciente al más antiguo) y el total de eventos, o ErrNotFound.
+	History(ctx context.Context, userID, id int, q models.HistoryQuery) ([]models.WatchEvent, int64, error)
+	// UndoLastEvent elimina el último evento del historial, devuelve la serie al
+	// progreso y estado anteriores a él y devuelve la serie actualizada.
+	// Devuelve ErrNoHistory si no hay eventos.
+	UndoLastEvent(ctx context.Context, userID, id int) (models.Series, error)
+	// Transaction ejecuta fn con un repositorio cuyas operaciones forman una
+	// única transacción: si fn devuelve un error no se guarda ninguna y se
+	// devuelve ese error; si no, se confirman todas juntas. Una llamada anidada
+	// (sobre el repositorio recibido) descarta solo sus propias operaciones si falla.
+	Transaction(ctx context.Context, fn func(repo SeriesRepository) error) error
+}
+
+// CollectionRepository define las operaciones sobre las etiquetas y las listas
+// personalizadas del usuario userID y su relación (muchos a muchos) con las
+// series. Los nombres son únicos por usuario sin distinguir mayúsculas: crear o
+// renombrar con un nombre en uso devuelve ErrConflict. Una serie, etiqueta o
+// lista de otro usuario se reporta como ErrNotFound. Asignar y quitar series no
+// cambia la versión de la serie.
+type CollectionRepository interface {
+	// ListTags devuelve las etiquetas del usuario ordenadas por nombre.
+	ListTags(ctx context.Context, userID int) ([]models.Tag, error)
+	// CreateTag guarda una nueva etiqueta (de t.UserID) y asigna su ID en t.
+	CreateTag(ctx context.Context, t *models.Tag) error
+	// RenameTag cambia el nombre de la etiqueta t.ID de t.UserID y completa t.
+	RenameTag(ctx context.Context, t *models.Tag) error
+	// DeleteTag elimina la etiqueta y la quita de todas las series.
+	DeleteTag(ctx context.Context, userID, id int) error
+	// SeriesTags devuelve las etiquetas de la serie ordenadas por nombre.
+	SeriesTags(ctx context.Context, userID, seriesID int) ([]models.Tag, error)
+	// AttachTag asigna la etiqueta a la serie; si ya la tenía no hace nada.
+	AttachTag(ctx context.Context, userID, seriesID, tagID int) error
+	// DetachTag quita la etiqueta de la serie; si no la tenía no hace nada.
+	DetachTag(ctx context.Context, userID, seriesID, tagID int) error
+
+	// ListLists devuelve las listas del usuario ordenadas por nombre.
+	ListLists(ctx context.Context, userID int) ([]models.CustomList, error)
+	// GetList devuelve la lista con el ID indicado o ErrNotFound.
+	GetList(ctx context.Context, userID, id int) (models.CustomList, error)
+	// CreateList guarda una nueva lista (de l.UserID) y asigna su ID en l.
+	CreateList(ctx context.Context, l *models.CustomList) error
+	// UpdateList cambia el nombre y la descripción de la lista l.ID de l.UserID y completa l.
+	UpdateList(ctx context.Context, l *models.CustomList) error
+	// DeleteList elimina la lista (las series no se eliminan).
+	DeleteList(ctx context.Context, userID, id int) error
+	// AddToList agrega la serie a la lista; si ya estaba no hace nada.
+	AddToList(ctx context.Context, userID, listID, seriesID int) error
+	// RemoveFromList quita la serie de la lista; si no estaba no hace nada.
+	RemoveFromList(ctx context.Context, userID, listID, seriesID int) error
+}
+
+// StatsRepository calcula estadísticas de visualización agregando los datos
+// en el almacenamiento (consultas SQL en la implementación GORM), sin cargar
+// todas las series del usuario.
+type StatsRepository interface {
+	// Stats calcula las estadísticas de las series y del historial del usuario userID.
+	Stats(ctx context.Context, userID int, q models.StatsQuery) (models.Stats, error)
+}
+
+// ExportRepository recorre todas las series de un usuario con sus datos
+// relacionados para exportarlas, por lotes, sin cargar la lista completa.
+type ExportRepository interface {
+	// ExportSeries llama a fn con cada serie del usuario userID, por id
+	// ascendente. Si fn devuelve un error el recorrido se detiene y se devuelve ese error.
+	ExportSeries(ctx context.Context, userID int, fn func(models.SeriesExport) error) error
+}
+
+// UserRepository define las operaciones de persistencia sobre usuarios.
+type UserRepository interface {
+	// CreateUser guarda un nuevo usuario y asigna su ID en u.
+	// Devuelve ErrConflict si el nombre de usuario ya existe.
+	CreateUser(ctx context.Context, u *models.User) error
+	// GetUser devuelve el usuario con el ID indicado o ErrNotFound.
+	GetUser(ctx context.Context, id int) (models.User, error)
+	// GetUserByUsername devuelve el usuario con ese nombre o ErrNotFound.
+	GetUserByUsername(ctx context.Context, username string) (models.User, error)
+}
+
+// SessionRepository define las operaciones de persistencia sobre sesiones.
+type SessionRepository interface {
+	// CreateSession guarda una nueva sesión y asigna su ID en s.
+	CreateSession(ctx context.Context, s *models.Session) error
+	// GetSessionByTokenHash devuelve la sesión vigente (no expirada) con ese hash de token o ErrNotFound.
+	GetSessionByTokenHash(ctx context.Context, tokenHash string) (models.Session, error)
+	// DeleteSession elimina la sesión con ese hash de token (cerrar sesión).
+	DeleteSession(ctx context.Context, tokenHash string) error
+	// DeleteExpiredSessions elimina las sesiones que expiraron antes de now y
+	// devuelve cuántas eliminó.
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+}
+
+// APIKeyRepository define las operaciones de persistencia sobre claves de API.
+type APIKeyRepository interface {
+	// CreateAPIKey guarda una nueva clave y asigna su ID en k.
+	CreateAPIKey(ctx context.Context, k *models.APIKey) error
+	// ListAPIKeys devuelve todas las claves del usuario (incluidas las revocadas), de la más reciente a la más antigua.
+	ListAPIKeys(ctx context.Context, userID int) ([]models.APIKey, error)
+	// GetAPIKeyByHash devuelve la clave vigente (no revocada) con ese hash o ErrNotFound.
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (models.APIKey, error)
+	// RevokeAPIKey marca como revocada la clave del usuario o devuelve ErrNotFound.
+	RevokeAPIKey(ctx context.Context, userID, id int) error
+	// TouchAPIKey registra el instante de último uso de la clave.
+	TouchAPIKey(ctx context.Context, id int, usedAt time.Time) error
+}
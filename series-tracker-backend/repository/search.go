@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+
+	"lab6/models"
+)
+
+const (
+	// searchCandidateLimit es la cantidad máxima de series que cada índice de
+	// búsqueda aporta como candidatas antes de ordenarlas por relevancia.
+	searchCandidateLimit = 200
+	// minTermsScore es la coincidencia media mínima de los términos de la
+	// búsqueda para que una serie forme parte de los resultados.
+	minTermsScore = 0.4
+	// minTrigramSimilarity es la similitud mínima entre un término y una palabra
+	// del título para considerarla un error de escritura del término.
+	minTrigramSimilarity = 0.3
+)
+
+// searchTerms normaliza s para buscar: minúsculas, sin acentos latinos y
+// dividido en palabras de letras y dígitos.
+func searchTerms(s string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case r >= 0x300 && r <= 0x36f:
+			// Diacríticos combinables (tildes, diéresis...) separados por NFD
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// termTrigrams devuelve los trigramas (de runas) de los términos, sin repetir,
+// para consultar los índices de trigramas. Los términos de menos de tres
+// letras no aportan ninguno.
+func termTrigrams(terms []string) []string {
+	var trigrams []string
+	for _, term := range terms {
+		runes := []rune(term)
+		for i := 0; i+3 <= len(runes); i++ {
+			if t := string(runes[i : i+3]); !slices.Contains(trigrams, t) {
+				trigrams = append(trigrams, t)
+			}
+		}
+	}
+	return trigrams
+}
+
+// termAffixes devuelve las tres primeras y las tres últimas runas de cada
+// término, sin repetir, para preseleccionar con LIKE las series que pueden
+// tener errores de escritura en los motores sin índice de trigramas: un error
+// en un término suele dejar intacto uno de sus extremos. Los términos de
+// menos de tres letras no aportan ninguno.
+func termAffixes(terms []string) []string {
+	var affixes []string
+	for _, term := range terms {
+		runes := []rune(term)
+		if len(runes) < 3 {
+			continue
+		}
+		for _, a := range []string{string(runes[:3]), string(runes[len(runes)-3:])} {
+			if !slices.Contains(affixes, a) {
+				affixes = append(affixes, a)
+			}
+		}
+	}
+	return affixes
+}
+
+// wordTrigrams devuelve el conjunto de trigramas de word con el mismo relleno
+// que pg_trgm (dos espacios al inicio y uno al final), de modo que las
+// primeras letras pesan más.
+func wordTrigrams(word string) map[string]bool {
+	runes := []rune("  " + word + " ")
+	set := make(map[string]bool, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = true
+	}
+	return set
+}
+
+// trigramSimilarity devuelve la proporción de trigramas que comparten a y b
+// (0 a 1), como la función similarity de pg_trgm.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := wordTrigrams(a), wordTrigrams(b)
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// termScore indica cuánto coincide el término con una palabra del título:
+// 1 si son iguales, algo menos si el término es el comienzo de la palabra o
+// parte de ella y, por similitud de trigramas, si difieren por errores de
+// escritura. Devuelve 0 si no se parecen.
+func termScore(term, word string) float64 {
+	termLen, wordLen := utf8.RuneCountInString(term), utf8.RuneCountInString(word)
+	switch {
+	case term == word:
+		return 1
+	case termLen >= 2 && strings.HasPrefix(word, term):
+		return 0.7 + 0.2*float64(termLen)/float64(wordLen)
+	case termLen >= 2 && strings.Contains(word, term):
+		return 0.5 + 0.2*float64(termLen)/float64(wordLen)
+	}
+	if sim := trigramSimilarity(term, word); sim >= minTrigramSimilarity {
+		return 0.3 + 0.5*sim
+	}
+	return 0
+}
+
+// titleScore calcula la relevancia de title para los términos de la búsqueda
+// (0 a 1). Combina la coincidencia media de los términos con la proporción de
+// palabras del título que coinciden, para preferir los títulos más cercanos a
+// la búsqueda; solo el título exacto obtiene 1.
+func titleScore(terms []string, title string) float64 {
+	words := searchTerms(title)
+	if len(words) == 0 {
+		return 0
+	}
+	if slices.Equal(words, terms) {
+		return 1
+	}
+
+	matched := make([]bool, len(words))
+	var total float64
+	for _, term := range terms {
+		best, bestWord := 0.0, -1
+		for i, word := range words {
+			if score := termScore(term, word); score > best {
+				best, bestWord = score, i
+			}
+		}
+		if bestWord >= 0 {
+			matched[bestWord] = true
+		}
+		total += best
+	}
+	termsScore := total / float64(len(terms))
+	if termsScore < minTermsScore {
+		return 0
+	}
+
+	covered := 0
+	for _, ok := range matched {
+		if ok {
+			covered++
+		}
+	}
+	return 0.95 * (0.85*termsScore + 0.15*float64(covered)/float64(len(words)))
+}
+
+// rankSearch puntúa candidates con los títulos principal y alternativos y
+// devuelve hasta limit resultados de mayor a menor relevancia (a igual
+// relevancia, por título). Las series que no coinciden se descartan.
+func rankSearch(terms []string, candidates []models.Series, limit int) []models.SearchResult {
+	results := []models.SearchResult{}
+	if len(terms) == 0 {
+		return results
+	}
+	for _, s := range candidates {
+		result := models.SearchResult{Series: s}
+		// El título principal se prefiere a un alternativo con la misma relevancia
+		for _, title := range append([]string{s.Title}, s.AltTitles...) {
+			if score := titleScore(terms, title); score > result.Score {
+				result.Score, result.MatchedTitle = score, title
+			}
+		}
+		if result.Score > 0 {
+			results = append(results, result)
+		}
+	}
+
+	slices.SortFunc(results, func(a, b models.SearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return compareNames(a.Title, b.Title, a.ID, b.ID)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		// Tres decimales bastan para ordenar y mostrar la relevancia
+		results[i].Score = math.Round(results[i].Score*1000) / 1000
+	}
+	return results
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"lab6/models"
+)
+
+func TestLikeCandidates(t *testing.T) {
+	db := newSQLiteDB(t)
+	ctx := context.Background()
+	user := models.User{Username: "ana", PasswordHash: "x"}
+	if err := NewGormUserRepository(db).CreateUser(ctx, &user); err != nil {
+		t.Fatalf("creando el usuario: %v", err)
+	}
+	repo := NewGormSeriesRepository(db)
+	create := func(title string) int {
+		t.Helper()
+		s := models.Series{UserID: user.ID, Title: title}
+		if err := repo.Create(ctx, &s); err != nil {
+			t.Fatalf("creando la serie: %v", err)
+		}
+		return s.ID
+	}
+
+	frieren := create("Sousou no Frieren")
+	create("Mushishi")
+	// Con un error al principio del término se conserva el final, y al revés
+	for _, query := range []string{"frieern", "vrieren"} {
+		ids, err := likeCandidates(db, user.ID, searchTerms(query))
+		if err != nil {
+			t.Fatalf("likeCandidates(%q): %v", query, err)
+		}
+		if !slices.Equal(ids, []int{frieren}) {
+			t.Errorf("likeCandidates(%q) = %v, se esperaba [%d]", query, ids, frieren)
+		}
+	}
+
+	// La preselección nunca devuelve más de searchCandidateLimit series
+	for i := range searchCandidateLimit + 10 {
+		create(fmt.Sprintf("Gintama %d", i))
+	}
+	ids, err := likeCandidates(db, user.ID, searchTerms("gintama"))
+	if err != nil {
+		t.Fatalf("likeCandidates: %v", err)
+	}
+	if len(ids) != searchCandidateLimit {
+		t.Errorf("likeCandidates devolvió %d series, se esperaban %d", len(ids), searchCandidateLimit)
+	}
+}
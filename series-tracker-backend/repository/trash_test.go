@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lab6/models"
+)
+
+func TestGormPurgeDeletesRelated(t *testing.T) {
+	db := newSQLiteDB(t)
+	// Purge no depende del ON DELETE CASCADE de las claves foráneas
+	if err := db.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+		t.Fatalf("desactivando las claves foráneas: %v", err)
+	}
+	ctx := context.Background()
+	repo := NewGormSeriesRepository(db)
+	user := models.User{Username: "ana", PasswordHash: "x"}
+	if err := NewGormUserRepository(db).CreateUser(ctx, &user); err != nil {
+		t.Fatalf("creando el usuario: %v", err)
+	}
+
+	serie := models.Series{UserID: user.ID, Title: "Frieren", TotalEpisodes: 28}
+	if err := repo.Create(ctx, &serie); err != nil {
+		t.Fatalf("creando la serie: %v", err)
+	}
+	if _, err := repo.IncrementEpisode(ctx, user.ID, serie.ID); err != nil {
+		t.Fatalf("IncrementEpisode: %v", err)
+	}
+	if _, err := repo.SetVote(ctx, user.ID, serie.ID, models.VoteUp); err != nil {
+		t.Fatalf("SetVote: %v", err)
+	}
+	score := 9.0
+	if _, err := repo.SaveReview(ctx, &models.Review{SeriesID: serie.ID, UserID: user.ID, Score: &score}); err != nil {
+		t.Fatalf("SaveReview: %v", err)
+	}
+	collections := NewGormCollectionRepository(db)
+	tag := models.Tag{UserID: user.ID, Name: "fantasía"}
+	if err := collections.CreateTag(ctx, &tag); err != nil {
+		t.Fatalf("CreateTag: %v", err)
+	}
+	if err := collections.AttachTag(ctx, user.ID, serie.ID, tag.ID); err != nil {
+		t.Fatalf("AttachTag: %v", err)
+	}
+	list := models.CustomList{UserID: user.ID, Name: "Favoritas"}
+	if err := collections.CreateList(ctx, &list); err != nil {
+		t.Fatalf("CreateList: %v", err)
+	}
+	if err := collections.AddToList(ctx, user.ID, list.ID, serie.ID); err != nil {
+		t.Fatalf("AddToList: %v", err)
+	}
+	if err := repo.Delete(ctx, user.ID, serie.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	purged, err := repo.Purge(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Purge: %v", err)
+	}
+	if len(purged) != 1 || purged[0].ID != serie.ID {
+		t.Fatalf("series purgadas = %+v, se esperaba la serie %d", purged, serie.ID)
+	}
+	for _, table := range []string{"watch_events", "votes", "reviews", "series_tags", "custom_list_items"} {
+		var count int64
+		if err := db.Table(table).Where("series_id = ?", serie.ID).Count(&count).Error; err != nil {
+			t.Fatalf("contando %s: %v", table, err)
+		}
+		if count != 0 {
+			t.Errorf("%s conserva %d filas de la serie purgada", table, count)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ BlobStore = (*FileStore)(nil)
+
+// FileStore implementa BlobStore guardando cada blob como un archivo dentro de
+// un directorio raíz. El tipo de contenido se deduce de la extensión de la
+// clave, por lo que las claves deben terminar en una extensión conocida.
+type FileStore struct {
+	root string
+}
+
+// NewFileStore crea un FileStore en el directorio root, creándolo si no existe.
+func NewFileStore(root string) (*FileStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("creando el directorio de blobs %q: %w", root, err)
+	}
+	return &FileStore{root: root}, nil
+}
+
+// path devuelve la ruta del archivo del blob key.
+func (s *FileStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+// Put implementa BlobStore. Escribe primero un archivo temporal y luego lo
+// renombra, para que una lectura concurrente nunca vea un archivo a medias.
+func (s *FileStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	dst := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	// CreateTemp crea el archivo con permisos 0600; usar los de un archivo normal
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
+
+// Get implementa BlobStore.
+func (s *FileStore) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	if err := checkKey(key); err != nil {
+		return nil, BlobInfo{}, err
+	}
+	f, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, BlobInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, BlobInfo{}, err
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return f, BlobInfo{ContentType: contentType, Size: stat.Size(), ModTime: stat.ModTime()}, nil
+}
+
+// Delete implementa BlobStore.
+func (s *FileStore) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Quitar los directorios que quedaron vacíos (os.Remove falla si no lo están)
+	for dir := path.Dir(key); dir != "."; dir = path.Dir(dir) {
+		if os.Remove(s.path(dir)) != nil {
+			break
+		}
+	}
+	return nil
+}
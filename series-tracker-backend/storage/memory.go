@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ BlobStore = (*MemoryStore)(nil)
+
+// memoryBlob es un blob guardado en memoria.
+type memoryBlob struct {
+	data []byte
+	info BlobInfo
+}
+
+// MemoryStore implementa BlobStore guardando los blobs en memoria. Está pensado
+// para pruebas y desarrollo local; los datos se pierden al terminar el proceso.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	blobs map[string]memoryBlob
+}
+
+// NewMemoryStore crea un almacenamiento en memoria vacío.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blobs: make(map[string]memoryBlob)}
+}
+
+// Put implementa BlobStore.
+func (s *MemoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = memoryBlob{
+		data: slices.Clone(data),
+		info: BlobInfo{ContentType: contentType, Size: int64(len(data)), ModTime: time.Now().UTC()},
+	}
+	return nil
+}
+
+// Get implementa BlobStore.
+func (s *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	blob, ok := s.blobs[key]
+	if !ok {
+		return nil, BlobInfo{}, ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(blob.data)), blob.info, nil
+}
+
+// Delete implementa BlobStore.
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blobs, key)
+	return nil
+}
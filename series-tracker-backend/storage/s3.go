@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Verificación en tiempo de compilación de que se cumple la interfaz.
+var _ BlobStore = (*S3Store)(nil)
+
+// Formatos de fecha de la firma AWS Signature Version 4.
+const (
+	amzDateFormat  = "20060102T150405Z"
+	amzScopeFormat = "20060102"
+)
+
+// S3Config configura un S3Store.
+type S3Config struct {
+	// Endpoint es la URL base del servicio, por ejemplo "https://s3.us-east-1.amazonaws.com"
+	// o "http://localhost:9000" para un MinIO local.
+	Endpoint string
+	// Region es la región usada en la firma (MinIO acepta "us-east-1").
+	Region string
+	// Bucket es el bucket (que debe existir) donde se guardan los blobs.
+	Bucket string
+	// AccessKeyID y SecretAccessKey son las credenciales de acceso.
+	AccessKeyID     string
+	SecretAccessKey string
+	// Client es el cliente HTTP; si es nil se usa uno con un timeout de 30 segundos.
+	Client *http.Client
+}
+
+// S3Store implementa BlobStore sobre la API REST de S3 (PutObject, GetObject y
+// DeleteObject) con direcciones de estilo ruta ({endpoint}/{bucket}/{clave}) y
+// firma AWS Signature Version 4, de modo que funciona con AWS S3 y con
+// servicios compatibles como MinIO sin depender del SDK de AWS.
+type S3Store struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+}
+
+// NewS3Store crea un S3Store con la configuración indicada.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID y S3_SECRET_ACCESS_KEY son obligatorias")
+	}
+	base, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT inválido: %q", cfg.Endpoint)
+	}
+	if err := checkKey(cfg.Bucket); err != nil || strings.Contains(cfg.Bucket, "/") {
+		return nil, fmt.Errorf("S3_BUCKET inválido: %q", cfg.Bucket)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &S3Store{cfg: cfg, base: base, client: client}, nil
+}
+
+// objectURL devuelve la URL del objeto key.
+func (s *S3Store) objectURL(key string) string {
+	u := *s.base
+	u.Path += "/" + s.cfg.Bucket + "/" + key
+	return u.String()
+}
+
+// do firma y envía una solicitud sobre el objeto key.
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte, header http.Header) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	signV4(req, body, s.cfg, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// s3Error construye un error con el estado y el comienzo del cuerpo de la respuesta.
+func s3Error(op string, resp *http.Response) error {
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("S3 %s: %s: %s", op, resp.Status, strings.TrimSpace(string(detail)))
+}
+
+// Put implementa BlobStore.
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data, http.Header{"Content-Type": {contentType}})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error("PutObject", resp)
+	}
+	return nil
+}
+
+// Get implementa BlobStore.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, BlobInfo{}, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, BlobInfo{}, s3Error("GetObject", resp)
+	}
+
+	info := BlobInfo{ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return resp.Body, info, nil
+}
+
+// Delete implementa BlobStore.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error("DeleteObject", resp)
+	}
+	return nil
+}
+
+// hmacSHA256 devuelve el HMAC-SHA256 de data con la clave key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// signV4 firma req con AWS Signature Version 4 para el servicio "s3". Firma el
+// header Host y todos los headers presentes en req.Header, y añade los headers
+// X-Amz-Date, X-Amz-Content-Sha256 y Authorization.
+func signV4(req *http.Request, body []byte, cfg S3Config, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.Format(amzDateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	scope := now.Format(amzScopeFormat) + "/" + cfg.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+cfg.SecretAccessKey), now.Format(amzScopeFormat))
+	key = hmacSHA256(key, cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+cfg.AccessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
@@ -0,0 +1,93 @@
+// Package storage guarda archivos binarios (blobs), como las portadas de las
+// series, detrás de la interfaz BlobStore. Incluye un almacenamiento en el
+// sistema de archivos local (por defecto), uno compatible con la API de S3
+// (AWS S3, MinIO u otro servicio compatible) y uno en memoria para desarrollo.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+)
+
+// Tipos de almacenamiento soportados en la variable de entorno BLOB_STORE.
+const (
+	KindFile   = "fs"
+	KindS3     = "s3"
+	KindMemory = "memory"
+)
+
+// ErrNotFound se devuelve cuando no existe un blob con la clave indicada.
+var ErrNotFound = errors.New("blob no encontrado")
+
+// ErrInvalidKey se devuelve cuando la clave no tiene un formato válido.
+var ErrInvalidKey = errors.New("clave de blob inválida")
+
+// keyPattern limita las claves a segmentos de letras, dígitos, '-', '_' y '.'
+// separados por '/', sin segmentos vacíos ni relativos ("." o "..").
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]*(/[A-Za-z0-9_-][A-Za-z0-9._-]*)*$`)
+
+// BlobInfo describe un blob guardado.
+type BlobInfo struct {
+	// ContentType es el tipo MIME del contenido.
+	ContentType string
+	// Size es el tamaño en bytes.
+	Size int64
+	// ModTime es el momento en que se guardó.
+	ModTime time.Time
+}
+
+// BlobStore guarda y recupera blobs identificados por una clave del tipo
+// "covers/abc123/small.jpg". Las implementaciones son seguras para uso concurrente.
+type BlobStore interface {
+	// Put guarda data con la clave indicada, reemplazando el blob anterior si existía.
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Get devuelve el contenido del blob (que el llamador debe cerrar) y su
+	// descripción, o ErrNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, BlobInfo, error)
+	// Delete elimina el blob; si no existe no hace nada.
+	Delete(ctx context.Context, key string) error
+}
+
+// checkKey devuelve ErrInvalidKey si key no tiene un formato válido.
+func checkKey(key string) error {
+	if len(key) > 512 || !keyPattern.MatchString(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// getenv devuelve el valor de la variable de entorno key o def si está vacía.
+func getenv(key, def string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// Open crea el BlobStore configurado en las variables de entorno. BLOB_STORE
+// elige el tipo: "fs" (por defecto) guarda en el directorio BLOB_DIR
+// ("data/blobs" por defecto); "s3" usa el bucket S3_BUCKET del servicio
+// S3_ENDPOINT (ver S3Config); "memory" no persiste nada.
+func Open() (BlobStore, error) {
+	switch kind := getenv("BLOB_STORE", KindFile); kind {
+	case KindFile:
+		return NewFileStore(getenv("BLOB_DIR", "data/blobs"))
+	case KindS3:
+		return NewS3Store(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          getenv("S3_REGION", "us-east-1"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	case KindMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("BLOB_STORE no soportado: %q (use %s, %s o %s)", kind, KindFile, KindS3, KindMemory)
+	}
+}
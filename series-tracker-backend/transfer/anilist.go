@@ -0,0 +1,209 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"lab6/models"
+)
+
+// maxAniListSynonyms limita los sinónimos que se importan como títulos
+// alternativos, para no superar el máximo de títulos de una serie.
+const maxAniListSynonyms = 16
+
+// aniListCollection es la colección de listas (MediaListCollection) de la API
+// GraphQL de AniList.
+type aniListCollection struct {
+	Lists []struct {
+		Name    string         `json:"name"`
+		Entries []aniListEntry `json:"entries"`
+	} `json:"lists"`
+}
+
+// aniListEntry es una entrada (MediaList) de una lista de AniList.
+type aniListEntry struct {
+	Status   string       `json:"status"`
+	Progress int          `json:"progress"`
+	Score    float64      `json:"score"`
+	Media    aniListMedia `json:"media"`
+}
+
+// aniListMedia son los datos de la obra de una entrada de AniList.
+type aniListMedia struct {
+	Title struct {
+		UserPreferred string `json:"userPreferred"`
+		Romaji        string `json:"romaji"`
+		English       string `json:"english"`
+		Native        string `json:"native"`
+	} `json:"title"`
+	Synonyms  []string `json:"synonyms"`
+	Episodes  int      `json:"episodes"`
+	Format    string   `json:"format"`
+	Status    string   `json:"status"`
+	StartDate struct {
+		Year int `json:"year"`
+	} `json:"startDate"`
+	EndDate struct {
+		Year int `json:"year"`
+	} `json:"endDate"`
+	CoverImage struct {
+		ExtraLarge string `json:"extraLarge"`
+		Large      string `json:"large"`
+		Medium     string `json:"medium"`
+	} `json:"coverImage"`
+	Studios struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+		Edges []struct {
+			IsMain bool `json:"isMain"`
+			Node   struct {
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"studios"`
+}
+
+// aniListStatuses relaciona los estados de AniList con los de la aplicación.
+// REPEATING (volver a verla) se importa como 'Watching'.
+var aniListStatuses = map[string]models.SeriesStatus{
+	"CURRENT":   models.StatusWatching,
+	"REPEATING": models.StatusWatching,
+	"PLANNING":  models.StatusPlanToWatch,
+	"COMPLETED": models.StatusCompleted,
+	"PAUSED":    models.StatusOnHold,
+	"DROPPED":   models.StatusDropped,
+}
+
+// aniListFormats relaciona los formatos de AniList con los de la aplicación;
+// los demás (MUSIC, MANGA, ...) quedan vacíos.
+var aniListFormats = map[string]models.MediaType{
+	"TV":       models.MediaTypeTV,
+	"TV_SHORT": models.MediaTypeTV,
+	"MOVIE":    models.MediaTypeMovie,
+	"SPECIAL":  models.MediaTypeSpecial,
+	"OVA":      models.MediaTypeOVA,
+	"ONA":      models.MediaTypeONA,
+}
+
+// aniListAiring relaciona el estado de publicación de AniList con el de emisión.
+var aniListAiring = map[string]models.AiringStatus{
+	"FINISHED":         models.AiringFinished,
+	"RELEASING":        models.AiringCurrently,
+	"NOT_YET_RELEASED": models.AiringNotYetAired,
+	"HIATUS":           models.AiringCurrently,
+}
+
+// ParseAniList convierte un volcado JSON de las listas de anime de AniList:
+// la respuesta de la consulta GraphQL MediaListCollection (con o sin el objeto
+// "data"), el objeto MediaListCollection o directamente un array de entradas.
+// El título usado es el preferido por el usuario o, si falta, el romaji, el
+// inglés o el nativo; los demás y hasta maxAniListSynonyms sinónimos quedan como
+// títulos alternativos (al validar se descartan los repetidos). Las puntuaciones
+// de hasta 10 se toman en escala de 10 y las mayores en escala de 100 (formato
+// POINT_100).
+func ParseAniList(data []byte) ([]Entry, error) {
+	raw, err := aniListEntries(data)
+	if err != nil {
+		return nil, fmt.Errorf("JSON de AniList inválido: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(raw))
+	for _, e := range raw {
+		media := e.Media
+		synonyms := media.Synonyms[:min(len(media.Synonyms), maxAniListSynonyms)]
+		titles := append([]string{media.Title.UserPreferred, media.Title.Romaji, media.Title.English, media.Title.Native}, synonyms...)
+		entry := Entry{
+			Series: models.Series{
+				Title:              firstNonEmpty(titles...),
+				AltTitles:          titles,
+				LastEpisodeWatched: e.Progress,
+				TotalEpisodes:      media.Episodes,
+				MediaType:          aniListFormats[media.Format],
+				AiringStatus:       aniListAiring[media.Status],
+				StartYear:          knownYear(media.StartDate.Year),
+				EndYear:            knownYear(media.EndDate.Year),
+				CoverURL:           firstNonEmpty(media.CoverImage.ExtraLarge, media.CoverImage.Large, media.CoverImage.Medium),
+				Studio:             aniListStudio(media),
+			},
+		}
+		if e.Score > 10 {
+			entry.Score = normalizeScore(e.Score, 100)
+		} else {
+			entry.Score = normalizeScore(e.Score, 10)
+		}
+		if status, ok := aniListStatuses[strings.ToUpper(strings.TrimSpace(e.Status))]; ok {
+			entry.Series.Status = status
+		} else {
+			entry.Problem = fmt.Sprintf("estado de AniList desconocido: %q", e.Status)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// aniListEntries extrae las entradas de cualquiera de las formas aceptadas por
+// ParseAniList. Una misma obra puede aparecer en varias listas personalizadas;
+// la deduplicación por título la resuelve al importar.
+func aniListEntries(data []byte) ([]aniListEntry, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var entries []aniListEntry
+		err := json.Unmarshal(trimmed, &entries)
+		return entries, err
+	}
+
+	var doc struct {
+		Data *struct {
+			MediaListCollection *aniListCollection `json:"MediaListCollection"`
+		} `json:"data"`
+		MediaListCollection *aniListCollection `json:"MediaListCollection"`
+		aniListCollection
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	collection := &doc.aniListCollection
+	switch {
+	case doc.Data != nil && doc.Data.MediaListCollection != nil:
+		collection = doc.Data.MediaListCollection
+	case doc.MediaListCollection != nil:
+		collection = doc.MediaListCollection
+	case doc.Lists == nil:
+		return nil, fmt.Errorf("no se encontró MediaListCollection ni la propiedad \"lists\"")
+	}
+
+	var entries []aniListEntry
+	for _, list := range collection.Lists {
+		entries = append(entries, list.Entries...)
+	}
+	return entries, nil
+}
+
+// aniListStudio devuelve el estudio principal de la obra o, si no se indica
+// cuál es, el primero.
+func aniListStudio(media aniListMedia) string {
+	for _, edge := range media.Studios.Edges {
+		if edge.IsMain {
+			return edge.Node.Name
+		}
+	}
+	if len(media.Studios.Edges) > 0 {
+		return media.Studios.Edges[0].Node.Name
+	}
+	if len(media.Studios.Nodes) > 0 {
+		return media.Studios.Nodes[0].Name
+	}
+	return ""
+}
+
+// firstNonEmpty devuelve el primer valor no vacío (tras quitar espacios).
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"lab6/models"
+)
+
+// Formatos de exportación aceptados.
+const (
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+	FormatMALXML = "malxml"
+)
+
+// Writer escribe una exportación serie a serie, sin tener la lista completa en
+// memoria: Begin escribe el encabezado, Write cada serie y End cierra el documento.
+type Writer interface {
+	Begin() error
+	Write(s models.SeriesExport) error
+	End() error
+}
+
+// ExportFormat describe un formato de exportación.
+type ExportFormat struct {
+	// ContentType es el tipo de la respuesta y Ext la extensión del archivo descargado.
+	ContentType string
+	Ext         string
+
+	newWriter func(w io.Writer, exportedAt time.Time) Writer
+}
+
+// NewWriter crea un Writer del formato que escribe en w.
+func (f ExportFormat) NewWriter(w io.Writer, exportedAt time.Time) Writer {
+	return f.newWriter(w, exportedAt)
+}
+
+// ExportFormats son los formatos de exportación por nombre.
+var ExportFormats = map[string]ExportFormat{
+	FormatJSON: {
+		ContentType: "application/json; charset=utf-8",
+		Ext:         ".json",
+		newWriter:   func(w io.Writer, at time.Time) Writer { return &jsonWriter{w: w, exportedAt: at} },
+	},
+	FormatCSV: {
+		ContentType: "text/csv; charset=utf-8",
+		Ext:         ".csv",
+		newWriter:   func(w io.Writer, _ time.Time) Writer { return &csvWriter{w: csv.NewWriter(w)} },
+	},
+	FormatMALXML: {
+		ContentType: "application/xml; charset=utf-8",
+		Ext:         ".xml",
+		newWriter:   newMALWriter,
+	},
+}
+
+// jsonWriter escribe un models.ExportDocument con una serie por línea.
+type jsonWriter struct {
+	w          io.Writer
+	exportedAt time.Time
+	count      int
+}
+
+// Begin implementa Writer.
+func (j *jsonWriter) Begin() error {
+	at, err := json.Marshal(j.exportedAt)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, `{"exportedAt":%s,"series":[`, at)
+	return err
+}
+
+// Write implementa Writer.
+func (j *jsonWriter) Write(s models.SeriesExport) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	sep := ",\n"
+	if j.count == 0 {
+		sep = "\n"
+	}
+	j.count++
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+// End implementa Writer.
+func (j *jsonWriter) End() error {
+	_, err := io.WriteString(j.w, "\n]}\n")
+	return err
+}
+
+// csvColumns son las columnas de la exportación CSV, una fila por serie. Las
+// listas de valores (títulos alternativos, temporadas, etiquetas, listas) se separan con ';'; del
+// historial solo se incluye la cantidad de eventos y la fecha del último.
+var csvColumns = []string{
+	"id", "title", "altTitles", "status", "lastEpisodeWatched", "totalEpisodes", "seasonEpisodes",
+	"mediaType", "airingStatus", "startYear", "endYear", "studio", "coverUrl", "synopsis",
+	"ranking", "score", "review", "tags", "lists", "historyEvents", "lastWatchedAt",
+}
+
+// csvWriter escribe una fila por serie con las columnas de csvColumns.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// Begin implementa Writer.
+func (c *csvWriter) Begin() error {
+	return c.w.Write(csvColumns)
+}
+
+// Write implementa Writer.
+func (c *csvWriter) Write(s models.SeriesExport) error {
+	seasons := make([]string, len(s.SeasonEpisodes))
+	for i, n := range s.SeasonEpisodes {
+		seasons[i] = strconv.Itoa(n)
+	}
+	var score, review string
+	if s.Review != nil {
+		if s.Review.Score != nil {
+			score = strconv.FormatFloat(*s.Review.Score, 'f', -1, 64)
+		}
+		review = s.Review.Body
+	}
+	var lastWatched string
+	if n := len(s.History); n > 0 {
+		lastWatched = s.History[n-1].WatchedAt.UTC().Format(time.RFC3339)
+	}
+	return c.w.Write([]string{
+		strconv.Itoa(s.ID),
+		s.Title,
+		strings.Join(s.AltTitles, ";"),
+		string(s.Status),
+		strconv.Itoa(s.LastEpisodeWatched),
+		strconv.Itoa(s.TotalEpisodes),
+		strings.Join(seasons, ";"),
+		string(s.MediaType),
+		string(s.AiringStatus),
+		optionalInt(s.StartYear),
+		optionalInt(s.EndYear),
+		s.Studio,
+		s.CoverURL,
+		s.Synopsis,
+		strconv.Itoa(s.Ranking),
+		score,
+		review,
+		strings.Join(s.Tags, ";"),
+		strings.Join(s.Lists, ";"),
+		strconv.Itoa(len(s.History)),
+		lastWatched,
+	})
+}
+
+// End implementa Writer.
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// optionalInt devuelve n como texto o vacío si es nil.
+func optionalInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
@@ -0,0 +1,89 @@
+package transfer
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"lab6/models"
+)
+
+// malExport es la raíz del XML que genera la exportación de listas de MyAnimeList.
+type malExport struct {
+	XMLName xml.Name   `xml:"myanimelist"`
+	Anime   []malAnime `xml:"anime"`
+	Manga   []struct{} `xml:"manga"`
+}
+
+// malAnime es una entrada <anime> de la exportación.
+type malAnime struct {
+	ID              int     `xml:"series_animedb_id"`
+	Title           string  `xml:"series_title"`
+	Type            string  `xml:"series_type"`
+	Episodes        int     `xml:"series_episodes"`
+	WatchedEpisodes int     `xml:"my_watched_episodes"`
+	Score           float64 `xml:"my_score"`
+	Status          string  `xml:"my_status"`
+}
+
+// malStatuses relaciona los estados de MyAnimeList (por nombre o por su código
+// numérico, que usan algunas exportaciones) con los de la aplicación.
+var malStatuses = map[string]models.SeriesStatus{
+	"watching":      models.StatusWatching,
+	"1":             models.StatusWatching,
+	"completed":     models.StatusCompleted,
+	"2":             models.StatusCompleted,
+	"on-hold":       models.StatusOnHold,
+	"on hold":       models.StatusOnHold,
+	"3":             models.StatusOnHold,
+	"dropped":       models.StatusDropped,
+	"4":             models.StatusDropped,
+	"plan to watch": models.StatusPlanToWatch,
+	"6":             models.StatusPlanToWatch,
+}
+
+// malTypes relaciona los tipos de MyAnimeList con los formatos de la
+// aplicación; los demás ("Music", "Unknown", ...) quedan vacíos.
+var malTypes = map[string]models.MediaType{
+	"tv":         models.MediaTypeTV,
+	"movie":      models.MediaTypeMovie,
+	"ova":        models.MediaTypeOVA,
+	"ona":        models.MediaTypeONA,
+	"special":    models.MediaTypeSpecial,
+	"tv special": models.MediaTypeSpecial,
+}
+
+// ParseMAL convierte una exportación XML de la lista de anime de MyAnimeList.
+// Las puntuaciones (de 1 a 10, 0 sin puntuar) se conservan tal cual.
+func ParseMAL(r io.Reader) ([]Entry, error) {
+	var export malExport
+	if err := xml.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("XML de MyAnimeList inválido: %w", err)
+	}
+	if len(export.Anime) == 0 && len(export.Manga) > 0 {
+		return nil, errors.New("la exportación es de una lista de manga; solo se admiten listas de anime")
+	}
+
+	entries := make([]Entry, 0, len(export.Anime))
+	for _, anime := range export.Anime {
+		entry := Entry{
+			Series: models.Series{
+				Title:              anime.Title,
+				LastEpisodeWatched: anime.WatchedEpisodes,
+				TotalEpisodes:      anime.Episodes,
+				MediaType:          malTypes[strings.ToLower(strings.TrimSpace(anime.Type))],
+			},
+			Score: normalizeScore(anime.Score, 10),
+		}
+		status, ok := malStatuses[strings.ToLower(strings.TrimSpace(anime.Status))]
+		if ok {
+			entry.Series.Status = status
+		} else {
+			entry.Problem = fmt.Sprintf("estado de MyAnimeList desconocido: %q", anime.Status)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,145 @@
+package transfer
+
+import (
+	"encoding/xml"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"lab6/models"
+)
+
+// malNoDate es la fecha que usa MyAnimeList para "sin fecha".
+const malNoDate = "0000-00-00"
+
+// malStatusNames son los nombres de estado que escribe la exportación de
+// MyAnimeList, que ParseMAL y los demás gestores reconocen al importar.
+var malStatusNames = map[models.SeriesStatus]string{
+	models.StatusWatching:    "Watching",
+	models.StatusCompleted:   "Completed",
+	models.StatusOnHold:      "On-Hold",
+	models.StatusDropped:     "Dropped",
+	models.StatusPlanToWatch: "Plan to Watch",
+}
+
+// malCDATA es un texto que se escribe como sección CDATA, como en las
+// exportaciones de MyAnimeList.
+type malCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+// malMyInfo es el encabezado <myinfo> de la exportación; user_export_type 1 es
+// una lista de anime.
+type malMyInfo struct {
+	XMLName    xml.Name `xml:"myinfo"`
+	ExportType int      `xml:"user_export_type"`
+}
+
+// malExportAnime es una entrada <anime> tal como la escribe MyAnimeList. El id
+// de la obra en MyAnimeList no se conoce y se deja en 0: los importadores la
+// buscan por título.
+type malExportAnime struct {
+	XMLName         xml.Name `xml:"anime"`
+	ID              int      `xml:"series_animedb_id"`
+	Title           malCDATA `xml:"series_title"`
+	Type            string   `xml:"series_type"`
+	Episodes        int      `xml:"series_episodes"`
+	MyID            int      `xml:"my_id"`
+	WatchedEpisodes int      `xml:"my_watched_episodes"`
+	StartDate       string   `xml:"my_start_date"`
+	FinishDate      string   `xml:"my_finish_date"`
+	Score           int      `xml:"my_score"`
+	Status          string   `xml:"my_status"`
+	Comments        malCDATA `xml:"my_comments"`
+	TimesWatched    int      `xml:"my_times_watched"`
+	Tags            malCDATA `xml:"my_tags"`
+	UpdateOnImport  int      `xml:"update_on_import"`
+}
+
+// malWriter escribe una exportación compatible con la de MyAnimeList.
+type malWriter struct {
+	w   io.Writer
+	enc *xml.Encoder
+}
+
+// newMALWriter crea un malWriter que escribe en w.
+func newMALWriter(w io.Writer, _ time.Time) Writer {
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	return &malWriter{w: w, enc: enc}
+}
+
+// Begin implementa Writer.
+func (m *malWriter) Begin() error {
+	if _, err := io.WriteString(m.w, xml.Header); err != nil {
+		return err
+	}
+	if err := m.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "myanimelist"}}); err != nil {
+		return err
+	}
+	return m.enc.Encode(malMyInfo{ExportType: 1})
+}
+
+// Write implementa Writer. La puntuación se redondea al entero más cercano (la
+// escala de MyAnimeList no tiene medios puntos), el tipo desconocido se escribe
+// como "Unknown" y las fechas de inicio y fin se deducen del historial.
+func (m *malWriter) Write(s models.SeriesExport) error {
+	anime := malExportAnime{
+		Title:           malCDATA{s.Title},
+		Type:            string(s.MediaType),
+		Episodes:        s.TotalEpisodes,
+		WatchedEpisodes: s.LastEpisodeWatched,
+		StartDate:       malNoDate,
+		FinishDate:      malNoDate,
+		Status:          malStatusNames[s.Status],
+		Tags:            malCDATA{strings.Join(s.Tags, ", ")},
+		UpdateOnImport:  1,
+	}
+	if anime.Type == "" {
+		anime.Type = "Unknown"
+	}
+	if s.Review != nil {
+		if s.Review.Score != nil {
+			anime.Score = int(math.Round(*s.Review.Score))
+		}
+		anime.Comments.Text = s.Review.Body
+	}
+	if start, finish := malDates(s); !start.IsZero() {
+		anime.StartDate = start.UTC().Format(time.DateOnly)
+		if !finish.IsZero() {
+			anime.FinishDate = finish.UTC().Format(time.DateOnly)
+		}
+	}
+	return m.enc.Encode(anime)
+}
+
+// End implementa Writer.
+func (m *malWriter) End() error {
+	if err := m.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "myanimelist"}}); err != nil {
+		return err
+	}
+	if err := m.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(m.w, "\n")
+	return err
+}
+
+// malDates devuelve la fecha del primer evento del historial como inicio y, si
+// la serie está completada, la del último paso a 'Completed' como fin. Son cero
+// si no hay datos.
+func malDates(s models.SeriesExport) (start, finish time.Time) {
+	if len(s.History) == 0 {
+		return time.Time{}, time.Time{}
+	}
+	start = s.History[0].WatchedAt
+	if s.Status == models.StatusCompleted {
+		for _, e := range s.History {
+			if e.Status == models.StatusCompleted && e.PreviousStatus != models.StatusCompleted {
+				finish = e.WatchedAt
+			}
+		}
+	}
+	return start, finish
+}
@@ -0,0 +1,153 @@
+// Package transfer convierte las exportaciones de listas de MyAnimeList (XML)
+// y AniList (JSON) en entradas con los campos de models.Series, para importarlas,
+// y escribe las exportaciones propias en JSON, CSV y XML compatible con MyAnimeList.
+package transfer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"lab6/models"
+)
+
+// Formatos de importación aceptados.
+const (
+	FormatMAL     = "mal"
+	FormatAniList = "anilist"
+)
+
+// MaxDecompressedBytes limita el tamaño de una exportación comprimida con gzip
+// una vez descomprimida.
+const MaxDecompressedBytes = 64 << 20
+
+// ErrUnknownFormat se devuelve cuando no se reconoce el formato del archivo.
+var ErrUnknownFormat = errors.New("formato no reconocido: se espera una exportación XML de MyAnimeList o JSON de AniList")
+
+// Entry es una fila de una exportación ya convertida a los campos de la serie.
+type Entry struct {
+	// Row es la posición (desde 1) de la entrada en el archivo.
+	Row int
+	// Title es el título con el que se deduplica la entrada.
+	Title string
+	// Series tiene el título, el estado, el progreso y los datos descriptivos
+	// conocidos; los campos que la exportación no trae quedan vacíos.
+	Series models.Series
+	// Score es la puntuación en la escala de la reseña (1 a 10 en pasos de 0.5), o nil.
+	Score *float64
+	// Problem describe por qué la entrada no puede importarse (por ejemplo, un
+	// estado desconocido); vacío si es válida.
+	Problem string
+}
+
+// Parse convierte el contenido de una exportación en entradas. format es
+// FormatMAL, FormatAniList o vacío para detectarlo a partir del contenido. Las
+// exportaciones de MyAnimeList comprimidas con gzip (.xml.gz) se descomprimen.
+// Devuelve también el formato usado.
+func Parse(data []byte, format string) ([]Entry, string, error) {
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		var err error
+		if data, err = gunzip(data); err != nil {
+			return nil, "", err
+		}
+	}
+	if format == "" {
+		format = Detect(data)
+	}
+
+	var entries []Entry
+	var err error
+	switch format {
+	case FormatMAL:
+		entries, err = ParseMAL(bytes.NewReader(data))
+	case FormatAniList:
+		entries, err = ParseAniList(data)
+	default:
+		return nil, "", ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, format, err
+	}
+	for i := range entries {
+		entries[i].Row = i + 1
+		entries[i].Title = strings.TrimSpace(entries[i].Series.Title)
+		entries[i].Series.Title = entries[i].Title
+		if entries[i].Title == "" && entries[i].Problem == "" {
+			entries[i].Problem = "la entrada no tiene título"
+		}
+		clampProgress(&entries[i].Series)
+	}
+	return entries, format, nil
+}
+
+// Detect deduce el formato a partir del primer carácter significativo: '<'
+// para el XML de MyAnimeList y '{' o '[' para el JSON de AniList. Devuelve
+// vacío si no lo reconoce.
+func Detect(data []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	switch {
+	case len(trimmed) == 0:
+		return ""
+	case trimmed[0] == '<':
+		return FormatMAL
+	case trimmed[0] == '{' || trimmed[0] == '[':
+		return FormatAniList
+	}
+	return ""
+}
+
+// gunzip descomprime data sin superar MaxDecompressedBytes.
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("archivo gzip inválido: %w", err)
+	}
+	defer reader.Close()
+	out, err := io.ReadAll(io.LimitReader(reader, MaxDecompressedBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("archivo gzip inválido: %w", err)
+	}
+	if len(out) > MaxDecompressedBytes {
+		return nil, fmt.Errorf("el archivo descomprimido supera el máximo de %d MiB", MaxDecompressedBytes>>20)
+	}
+	return out, nil
+}
+
+// clampProgress limita el progreso al total de episodios conocido: las
+// exportaciones pueden traer más episodios vistos que los emitidos (por
+// ejemplo, tras volver a verla).
+func clampProgress(s *models.Series) {
+	if s.LastEpisodeWatched < 0 {
+		s.LastEpisodeWatched = 0
+	}
+	if s.TotalEpisodes < 0 {
+		s.TotalEpisodes = 0
+	}
+	if s.TotalEpisodes > 0 && s.LastEpisodeWatched > s.TotalEpisodes {
+		s.LastEpisodeWatched = s.TotalEpisodes
+	}
+}
+
+// normalizeScore convierte una puntuación de la escala scale (10 o 100) a la de
+// la reseña, redondeada a medios puntos. 0 significa "sin puntuar" y devuelve nil.
+func normalizeScore(score, scale float64) *float64 {
+	if score <= 0 || math.IsNaN(score) || math.IsInf(score, 0) {
+		return nil
+	}
+	normalized := math.Round(score*10/scale*2) / 2
+	normalized = min(max(normalized, models.MinScore), models.MaxScore)
+	return &normalized
+}
+
+// knownYear devuelve year si es un año de emisión plausible (1900 a 2100) o nil
+// en otro caso: las exportaciones usan 0 o fechas como 0000-00-00 para "desconocido".
+func knownYear(year int) *int {
+	if year < 1900 || year > 2100 {
+		return nil
+	}
+	return &year
+}
@@ -0,0 +1,248 @@
+package transfer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lab6/models"
+)
+
+// wantEntry es lo que se comprueba de cada entrada de las exportaciones de testdata.
+type wantEntry struct {
+	title   string
+	status  models.SeriesStatus
+	watched int
+	total   int
+	media   models.MediaType
+	score   *float64
+	problem string
+}
+
+func score(v float64) *float64 { return &v }
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("leyendo %s: %v", name, err)
+	}
+	return data
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("comprimiendo: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("comprimiendo: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func checkEntries(t *testing.T, entries []Entry, want []wantEntry) {
+	t.Helper()
+	if len(entries) != len(want) {
+		t.Fatalf("se obtuvieron %d entradas, se esperaban %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		e := entries[i]
+		if e.Row != i+1 {
+			t.Errorf("entrada %d: Row = %d", i+1, e.Row)
+		}
+		if e.Title != w.title || e.Series.Title != w.title {
+			t.Errorf("entrada %d: título = %q/%q, se esperaba %q", i+1, e.Title, e.Series.Title, w.title)
+		}
+		if e.Series.Status != w.status {
+			t.Errorf("entrada %d: estado = %q, se esperaba %q", i+1, e.Series.Status, w.status)
+		}
+		if e.Series.LastEpisodeWatched != w.watched || e.Series.TotalEpisodes != w.total {
+			t.Errorf("entrada %d: progreso = %d/%d, se esperaba %d/%d", i+1,
+				e.Series.LastEpisodeWatched, e.Series.TotalEpisodes, w.watched, w.total)
+		}
+		if e.Series.MediaType != w.media {
+			t.Errorf("entrada %d: formato = %q, se esperaba %q", i+1, e.Series.MediaType, w.media)
+		}
+		switch {
+		case w.score == nil && e.Score != nil:
+			t.Errorf("entrada %d: puntuación = %v, se esperaba nil", i+1, *e.Score)
+		case w.score != nil && (e.Score == nil || *e.Score != *w.score):
+			t.Errorf("entrada %d: puntuación = %v, se esperaba %v", i+1, e.Score, *w.score)
+		}
+		if e.Problem != w.problem {
+			t.Errorf("entrada %d: problema = %q, se esperaba %q", i+1, e.Problem, w.problem)
+		}
+	}
+}
+
+func TestParseMAL(t *testing.T) {
+	want := []wantEntry{
+		{title: "Cowboy Bebop", status: models.StatusCompleted, watched: 26, total: 26, media: models.MediaTypeTV, score: score(9)},
+		// Más episodios vistos que emitidos: se limita al total
+		{title: "Trigun", status: models.StatusWatching, watched: 26, total: 26, media: models.MediaTypeTV},
+		// Estado por código numérico
+		{title: "Cowboy Bebop: Tengoku no Tobira", status: models.StatusPlanToWatch, total: 1, media: models.MediaTypeMovie},
+		{title: "Neon Genesis Evangelion", watched: 3, total: 26, score: score(7),
+			problem: `estado de MyAnimeList desconocido: "Rewatching"`},
+		{title: "cowboy  bebop", status: models.StatusOnHold, watched: 10, total: 26, media: models.MediaTypeTV},
+	}
+
+	data := readFixture(t, "mal.xml")
+	for name, input := range map[string][]byte{"xml": data, "gzip": gzipped(t, data)} {
+		t.Run(name, func(t *testing.T) {
+			entries, format, err := Parse(input, "")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if format != FormatMAL {
+				t.Errorf("formato = %q, se esperaba %q", format, FormatMAL)
+			}
+			checkEntries(t, entries, want)
+		})
+	}
+}
+
+func TestParseMALRejectsManga(t *testing.T) {
+	data := []byte(`<myanimelist><manga><series_title>Berserk</series_title></manga></myanimelist>`)
+	if _, _, err := Parse(data, FormatMAL); err == nil {
+		t.Fatal("se esperaba un error con una exportación de manga")
+	}
+}
+
+func TestParseAniList(t *testing.T) {
+	entries, format, err := Parse(readFixture(t, "anilist.json"), "")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if format != FormatAniList {
+		t.Errorf("formato = %q, se esperaba %q", format, FormatAniList)
+	}
+	checkEntries(t, entries, []wantEntry{
+		// Puntuación en escala de 100
+		{title: "Sousou no Frieren", status: models.StatusWatching, watched: 5, total: 28, media: models.MediaTypeTV, score: score(8.5)},
+		// REPEATING se importa como 'Watching'; el progreso se limita al total
+		{title: "Mushishi", status: models.StatusWatching, watched: 26, total: 26, score: score(7.5)},
+		{title: "Dorohedoro", watched: 1, total: 12, problem: `estado de AniList desconocido: "WATCHING"`},
+		{title: "", status: models.StatusOnHold, problem: "la entrada no tiene título"},
+	})
+
+	frieren := entries[0].Series
+	if frieren.Studio != "Madhouse" {
+		t.Errorf("estudio = %q, se esperaba el principal (Madhouse)", frieren.Studio)
+	}
+	if frieren.AiringStatus != models.AiringFinished {
+		t.Errorf("emisión = %q, se esperaba %q", frieren.AiringStatus, models.AiringFinished)
+	}
+	if frieren.StartYear == nil || *frieren.StartYear != 2023 || frieren.EndYear == nil || *frieren.EndYear != 2024 {
+		t.Errorf("años = %v-%v, se esperaba 2023-2024", frieren.StartYear, frieren.EndYear)
+	}
+	if frieren.CoverURL != "https://img.example.com/frieren.jpg" {
+		t.Errorf("portada = %q", frieren.CoverURL)
+	}
+	if !strings.Contains(strings.Join(frieren.AltTitles, "|"), "Frieren: Beyond Journey's End") {
+		t.Errorf("títulos alternativos = %v, falta el inglés", frieren.AltTitles)
+	}
+	if entries[1].Series.StartYear != nil {
+		t.Errorf("un año 0 debería quedar vacío, es %d", *entries[1].Series.StartYear)
+	}
+}
+
+func TestParseAniListShapes(t *testing.T) {
+	entry := `{"status": "COMPLETED", "progress": 12, "score": 8, "media": {"title": {"romaji": "Mob Psycho 100"}, "episodes": 12}}`
+	inputs := map[string]string{
+		"array":               `[` + entry + `]`,
+		"MediaListCollection": `{"MediaListCollection": {"lists": [{"entries": [` + entry + `]}]}}`,
+		"lists":               `{"lists": [{"entries": [` + entry + `]}]}`,
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			entries, _, err := Parse([]byte(input), FormatAniList)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			checkEntries(t, entries, []wantEntry{
+				{title: "Mob Psycho 100", status: models.StatusCompleted, watched: 12, total: 12, score: score(8)},
+			})
+		})
+	}
+
+	if _, _, err := Parse([]byte(`{"user": "ana"}`), FormatAniList); err == nil {
+		t.Error("se esperaba un error sin MediaListCollection ni lists")
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`<?xml version="1.0"?><myanimelist/>`, FormatMAL},
+		{"\xef\xbb\xbf\r\n  <myanimelist/>", FormatMAL},
+		{`{"data": {}}`, FormatAniList},
+		{"\n[]", FormatAniList},
+		{"title,status\n", ""},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		if got := Detect([]byte(tt.data)); got != tt.want {
+			t.Errorf("Detect(%q) = %q, se esperaba %q", tt.data, got, tt.want)
+		}
+	}
+
+	if _, _, err := Parse([]byte("title,status\n"), ""); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Parse de un CSV = %v, se esperaba ErrUnknownFormat", err)
+	}
+}
+
+func TestParseGzipLimit(t *testing.T) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(make([]byte, MaxDecompressedBytes+1)); err != nil {
+		t.Fatalf("comprimiendo: %v", err)
+	}
+	w.Close()
+
+	_, _, err := Parse(buf.Bytes(), "")
+	if err == nil || !strings.Contains(err.Error(), "supera el máximo") {
+		t.Errorf("Parse = %v, se esperaba el error de tamaño máximo", err)
+	}
+
+	if _, _, err := Parse([]byte{0x1f, 0x8b, 0x00}, ""); err == nil {
+		t.Error("se esperaba un error con un gzip truncado")
+	}
+}
+
+func TestNormalizeScore(t *testing.T) {
+	tests := []struct {
+		score, scale float64
+		want         *float64
+	}{
+		{9, 10, score(9)},
+		{7.5, 10, score(7.5)},
+		{7.3, 10, score(7.5)},
+		{85, 100, score(8.5)},
+		{87, 100, score(8.5)},
+		{88, 100, score(9)},
+		{100, 100, score(10)},
+		// Por debajo del mínimo de la reseña se sube a 1
+		{3, 100, score(1)},
+		{0, 10, nil},
+		{-1, 100, nil},
+	}
+	for _, tt := range tests {
+		got := normalizeScore(tt.score, tt.scale)
+		switch {
+		case tt.want == nil && got != nil:
+			t.Errorf("normalizeScore(%v, %v) = %v, se esperaba nil", tt.score, tt.scale, *got)
+		case tt.want != nil && (got == nil || *got != *tt.want):
+			t.Errorf("normalizeScore(%v, %v) = %v, se esperaba %v", tt.score, tt.scale, got, *tt.want)
+		}
+	}
+}
@@ -29,7 +29,7 @@ const Filters = ({ filters, onFilterChange }) => {
   // Create the dropdown for status
   const statusDropdown = document.createElement('select')
   statusDropdown.classList.add('filter-status')
-  const statuses = ['All', 'Plan to Watch', 'Watching', 'Dropped', 'Completed']
+  const statuses = ['All', 'Plan to Watch', 'Watching', 'On Hold', 'Dropped', 'Completed']
   statuses.forEach((status) => {
     const option = document.createElement('option')
     option.value = status === 'All' ? '' : status
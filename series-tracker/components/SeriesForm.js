@@ -26,7 +26,7 @@ const SeriesForm = ({ series = {}, onSubmit }) => {
 
   // Status dropdown
   const statusDropdown = document.createElement('select')
-  const statuses = ['Plan to Watch', 'Watching', 'Dropped', 'Completed']
+  const statuses = ['Plan to Watch', 'Watching', 'On Hold', 'Dropped', 'Completed']
   statuses.forEach((status) => {
     const option = document.createElement('option')
     option.value = status
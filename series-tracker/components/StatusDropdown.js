@@ -4,7 +4,7 @@ const StatusDropdown = ({ status, seriesId, reRenderTable }) => {
   const dropdown = document.createElement('select')
   dropdown.classList.add('status-dropdown')
 
-  const statuses = ['Plan to Watch', 'Watching', 'Dropped', 'Completed']
+  const statuses = ['Plan to Watch', 'Watching', 'On Hold', 'Dropped', 'Completed']
   statuses.forEach((optionStatus) => {
     const option = document.createElement('option')
     option.value = optionStatus
@@ -0,0 +1,92 @@
+import { login, register } from '../utils/auth.js'
+
+const LoginPage = (parentElement) => {
+  const container = document.createElement('div')
+  container.classList.add('login-page')
+
+  const title = document.createElement('h1')
+  title.textContent = 'Series Backlog Tracker'
+  container.appendChild(title)
+
+  const form = document.createElement('form')
+  form.classList.add('series-form')
+
+  // Helper function to create labeled inputs
+  const createLabeledInput = (labelText, inputElement) => {
+    const wrapper = document.createElement('div')
+    wrapper.classList.add('form-group')
+
+    const label = document.createElement('label')
+    label.textContent = labelText
+    label.classList.add('form-label')
+    wrapper.appendChild(label)
+
+    wrapper.appendChild(inputElement)
+    return wrapper
+  }
+
+  const usernameInput = document.createElement('input')
+  usernameInput.type = 'text'
+  usernameInput.placeholder = 'Username'
+  usernameInput.autocomplete = 'username'
+  usernameInput.required = true
+  usernameInput.classList.add('form-input')
+  form.appendChild(createLabeledInput('Username:', usernameInput))
+
+  const passwordInput = document.createElement('input')
+  passwordInput.type = 'password'
+  passwordInput.placeholder = 'Password'
+  passwordInput.autocomplete = 'current-password'
+  passwordInput.required = true
+  passwordInput.classList.add('form-input')
+  form.appendChild(createLabeledInput('Password:', passwordInput))
+
+  const errorMessage = document.createElement('p')
+  errorMessage.classList.add('login-error')
+  form.appendChild(errorMessage)
+
+  // Submits the credentials with the given action (login or register)
+  const submit = async (action) => {
+    if (!form.reportValidity()) {
+      return
+    }
+    errorMessage.textContent = ''
+    try {
+      await action(usernameInput.value.trim(), passwordInput.value)
+      window.navigate('/')
+    } catch (error) {
+      console.error('Failed to authenticate:', error)
+      errorMessage.textContent = error.message
+    }
+  }
+
+  const buttons = document.createElement('div')
+  buttons.classList.add('form-group')
+
+  const loginButton = document.createElement('button')
+  loginButton.type = 'submit'
+  loginButton.textContent = 'Log In'
+  buttons.appendChild(loginButton)
+
+  const registerButton = document.createElement('button')
+  registerButton.type = 'button'
+  registerButton.textContent = 'Register'
+  registerButton.addEventListener('click', () => submit(register))
+  buttons.appendChild(registerButton)
+
+  form.appendChild(buttons)
+  form.addEventListener('submit', (event) => {
+    event.preventDefault()
+    submit(login)
+  })
+  container.appendChild(form)
+
+  return {
+    render: () => {
+      parentElement.innerHTML = ''
+      parentElement.appendChild(container)
+    },
+  }
+}
+
+export default LoginPage
@@ -1,6 +1,7 @@
 import getSeriesList from '../utils/getSeriesList.js'
 import Filters from '../components/Filters.js'
 import Table from '../components/Table.js'
+import { logout } from '../utils/auth.js'
 
 const SeriesPage = (parentElement) => {
   // State-like variables to hold series data and filters
@@ -69,6 +70,16 @@ const SeriesPage = (parentElement) => {
     })
     container.appendChild(addNewButton)
 
+    // Add "Log Out" button
+    const logoutButton = document.createElement('button')
+    logoutButton.textContent = 'Log Out'
+    logoutButton.classList.add('logout-button')
+    logoutButton.addEventListener('click', async () => {
+      await logout()
+      window.navigate('login')
+    })
+    container.appendChild(logoutButton)
+
     // Create and append the Filters component
     const filtersComponent = Filters({
       filters,
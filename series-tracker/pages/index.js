@@ -1,13 +1,15 @@
 import SeriesPage from './SeriesPage.js'
 import CreateSeriesPage from './CreateSeriesPage.js'
 import EditSeriesPage from './EditSeriesPage.js'
+import LoginPage from './LoginPage.js'
 import render from '../utils/render.js'
+import { isLoggedIn } from '../utils/auth.js'
 
 // Select the root element
 const root = document.getElementById('root')
 
-// Initialize the global currentPage variable
-window.currentPage = { page: SeriesPage, params: null }
+// Initialize the global currentPage variable (the login page until there is a session)
+window.currentPage = { page: isLoggedIn() ? SeriesPage : LoginPage, params: null }
 
 // Define the navigate function
 window.navigate = (path) => {
@@ -18,7 +20,9 @@ window.navigate = (path) => {
   let page
   let params = null
 
-  if (pageName === 'create') {
+  if (pageName === 'login' || !isLoggedIn()) {
+    page = LoginPage
+  } else if (pageName === 'create') {
     page = CreateSeriesPage
   } else if (pageName === 'edit') {
     page = EditSeriesPage
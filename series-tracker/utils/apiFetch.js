@@ -0,0 +1,24 @@
+import BASE_URL from './BASE_URL.js'
+import { getToken, clearToken } from './auth.js'
+
+// fetch wrapper for the authenticated API: adds the session token and sends
+// the user back to the login page when the session is missing or expired
+const apiFetch = async (path, options = {}) => {
+  const headers = { ...options.headers }
+  const token = getToken()
+  if (token) {
+    headers.Authorization = `Bearer ${token}`
+  }
+
+  const response = await fetch(`${BASE_URL}${path}`, { ...options, headers })
+
+  if (response.status === 401) {
+    clearToken()
+    window.navigate('login')
+    throw new Error('Session expired, please log in again')
+  }
+
+  return response
+}
+
+export default apiFetch
@@ -0,0 +1,53 @@
+import BASE_URL from './BASE_URL.js'
+
+// The session token is kept in localStorage so it survives page reloads
+const TOKEN_KEY = 'seriesTrackerToken'
+
+export const getToken = () => localStorage.getItem(TOKEN_KEY)
+
+export const isLoggedIn = () => getToken() !== null
+
+export const clearToken = () => localStorage.removeItem(TOKEN_KEY)
+
+// Logs in (or registers) and stores the returned session token
+const authenticate = async (action, username, password) => {
+  const response = await fetch(`${BASE_URL}/auth/${action}`, {
+    method: 'POST',
+    headers: {
+      'Content-Type': 'application/json',
+    },
+    body: JSON.stringify({ username, password }),
+  })
+
+  const body = await response.json().catch(() => ({}))
+  if (!response.ok) {
+    throw new Error(body.message || `Authentication failed: ${response.statusText}`)
+  }
+
+  localStorage.setItem(TOKEN_KEY, body.token)
+  return body
+}
+
+export const login = (username, password) => authenticate('login', username, password)
+
+export const register = (username, password) => authenticate('register', username, password)
+
+// Revokes the session on the server and forgets the token
+export const logout = async () => {
+  const token = getToken()
+  clearToken()
+  if (!token) {
+    return
+  }
+
+  try {
+    await fetch(`${BASE_URL}/auth/logout`, {
+      method: 'POST',
+      headers: {
+        Authorization: `Bearer ${token}`,
+      },
+    })
+  } catch (error) {
+    console.error('Failed to log out:', error)
+  }
+}
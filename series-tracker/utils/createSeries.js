@@ -1,7 +1,7 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const createSeries = async (seriesData) => {
-  const response = await fetch(`${BASE_URL}/series`, {
+  const response = await apiFetch('/series', {
     method: 'POST',
     headers: {
       'Content-Type': 'application/json',
@@ -1,10 +1,10 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const deleteSeries = async (id) => {
-  const url = `${BASE_URL}/series/${id}`
+  const url = `/series/${id}`
 
   try {
-    const response = await fetch(url, {
+    const response = await apiFetch(url, {
       method: 'DELETE',
     })
 
@@ -1,7 +1,7 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const getSeriesById = async (id) => {
-  const response = await fetch(`${BASE_URL}/series/${id}`)
+  const response = await apiFetch(`/series/${id}`)
 
   if (!response.ok) {
     throw new Error('Failed to fetch series')
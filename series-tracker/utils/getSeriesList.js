@@ -1,4 +1,4 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 // Function to fetch the series list
 const getSeriesList = async (queryParams = {}) => {
@@ -6,11 +6,11 @@ const getSeriesList = async (queryParams = {}) => {
   const params = new URLSearchParams(queryParams)
 
   // Build the full URL
-  const url = `${BASE_URL}/series?${params.toString()}`
+  const url = `/series?${params.toString()}`
 
   try {
     // Make the GET request
-    const response = await fetch(url)
+    const response = await apiFetch(url)
 
     // Check if the response is OK
     if (!response.ok) {
@@ -1,10 +1,10 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const incrementEpisode = async (id) => {
-  const url = `${BASE_URL}/series/${id}/episode`
+  const url = `/series/${id}/episode`
 
   try {
-    const response = await fetch(url, {
+    const response = await apiFetch(url, {
       method: 'PATCH',
     })
 
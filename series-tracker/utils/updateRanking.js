@@ -1,14 +1,14 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const updateRanking = async (id, direction) => {
   if (direction !== 'upvote' && direction !== 'downvote') {
     throw new Error('Invalid direction. Use "upvote" or "downvote".')
   }
 
-  const url = `${BASE_URL}/series/${id}/${direction}`
+  const url = `/series/${id}/${direction}`
 
   try {
-    const response = await fetch(url, {
+    const response = await apiFetch(url, {
       method: 'PATCH',
     })
 
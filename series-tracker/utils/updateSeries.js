@@ -1,7 +1,7 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const updateSeries = async (id, seriesData) => {
-  const response = await fetch(`${BASE_URL}/series/${id}`, {
+  const response = await apiFetch(`/series/${id}`, {
     method: 'PUT',
     headers: {
       'Content-Type': 'application/json',
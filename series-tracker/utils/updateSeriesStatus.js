@@ -1,10 +1,10 @@
-import BASE_URL from './BASE_URL.js'
+import apiFetch from './apiFetch.js'
 
 const updateSeriesStatus = async (id, status) => {
-  const url = `${BASE_URL}/series/${id}/status`
+  const url = `/series/${id}/status`
 
   try {
-    const response = await fetch(url, {
+    const response = await apiFetch(url, {
       method: 'PATCH',
       headers: {
         'Content-Type': 'application/json',